@@ -8,6 +8,7 @@ import (
 	"gps-no-server/internal/common/config"
 	"gps-no-server/internal/common/logger"
 	"gps-no-server/internal/di"
+	"gps-no-server/internal/infrastructure/http/actor"
 	"gps-no-server/internal/infrastructure/http/api"
 	"net/http"
 	"os"
@@ -35,8 +36,22 @@ func main() {
 	if err := container.MqttClient.Connect(); err != nil {
 		appLog.Error().Err(err).Msg("Error connecting to MQTT broker")
 	}
-	if err := container.MqttClient.SubscribeRegistry(); err != nil {
-		appLog.Error().Err(err).Msg("Error subscribing to MQTT topics")
+
+	container.PresenceService.Start()
+	container.ConfigSyncService.Start()
+	container.ShadowService.Start()
+	container.RolloutService.Start()
+
+	if container.NmeaServer != nil {
+		if err := container.NmeaServer.Start(); err != nil {
+			appLog.Error().Err(err).Msg("Error starting NMEA server")
+		}
+	}
+
+	if container.GpsdServer != nil {
+		if err := container.GpsdServer.Start(); err != nil {
+			appLog.Error().Err(err).Msg("Error starting gpsd server")
+		}
 	}
 
 	server, err := setupServer(cfg, container)
@@ -76,7 +91,7 @@ func setupServer(cfg *config.Config, container *di.Container) (*http.Server, err
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, "+actor.Header)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -86,11 +101,21 @@ func setupServer(cfg *config.Config, container *di.Container) (*http.Server, err
 		c.Next()
 	})
 
+	router.Use(actor.Middleware())
+
 	apiHandler := api.NewAPI(
 		container.StationController,
 		container.StationConfigController,
 		container.ClusterController,
 		container.RangingController,
+		container.PositionController,
+		container.ZoneController,
+		container.CoverageController,
+		container.RangingGraphController,
+		container.ShadowController,
+		container.ProfileController,
+		container.RolloutController,
+		container.ChannelPlanController,
 	)
 	apiHandler.RegisterRoutes(router)
 
@@ -0,0 +1,25 @@
+package actor
+
+import (
+	"context"
+)
+
+const (
+	// ContextKey is the key the actor of a request is stored under.
+	ContextKey = "actor"
+
+	// Anonymous is recorded for API requests without an actor.
+	Anonymous = "anonymous"
+	// System is recorded for changes made by the server itself.
+	System = "system"
+)
+
+// FromContext returns the actor of the request the context belongs to, or
+// System outside of a request.
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(ContextKey).(string); ok && name != "" {
+		return name
+	}
+
+	return System
+}
@@ -10,9 +10,19 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Database DatabaseConfig `json:"database"`
-	Mqtt     MqttConfig     `json:"mqtt"`
+	Server      ServerConfig      `json:"server"`
+	Database    DatabaseConfig    `json:"database"`
+	Mqtt        MqttConfig        `json:"mqtt"`
+	Positioning PositioningConfig `json:"positioning"`
+	Ranging     RangingConfig     `json:"ranging"`
+	Tracking    TrackingConfig    `json:"tracking"`
+	Nmea        NmeaConfig        `json:"nmea"`
+	Gpsd        GpsdConfig        `json:"gpsd"`
+	Presence    PresenceConfig    `json:"presence"`
+	ConfigSync  ConfigSyncConfig  `json:"config_sync"`
+	Shadow      ShadowConfig      `json:"shadow"`
+	Rollout     RolloutConfig     `json:"rollout"`
+	Planning    PlanningConfig    `json:"planning"`
 }
 
 type ServerConfig struct {
@@ -45,6 +55,114 @@ type MqttConfig struct {
 	AutoReconnect        bool          `json:"auto_reconnect"`
 	MaxReconnectInterval time.Duration `json:"max_reconnect_interval"`
 	CleanSession         bool          `json:"clean_session"`
+	// WillTopic receives a retained "offline" when the server disconnects
+	// unexpectedly and "online" once connected. It is opt-in, empty disables it.
+	WillTopic string `json:"will_topic"`
+	// PublishTimeout bounds how long a publish waits for the broker, for
+	// example while the client is reconnecting.
+	PublishTimeout time.Duration `json:"publish_timeout"`
+}
+
+type PositioningConfig struct {
+	MaxRangingAge     time.Duration `json:"max_ranging_age"`
+	Dimension         int           `json:"dimension"`
+	MinVerticalSpread float64       `json:"min_vertical_spread"`
+	// SelfLocalizationWindow is how far back anchor-to-anchor rangings are aggregated.
+	SelfLocalizationWindow time.Duration `json:"self_localization_window"`
+	// Fixes above either limit are classified as degraded.
+	MaxHDOP     float64 `json:"max_hdop"`
+	MaxResidual float64 `json:"max_residual"`
+	// CoverageMaxRange is the default anchor range in metres for coverage maps.
+	CoverageMaxRange float64 `json:"coverage_max_range"`
+	CoverageMaxCells int     `json:"coverage_max_cells"`
+}
+
+type NmeaConfig struct {
+	Enabled   bool          `json:"enabled"`
+	Host      string        `json:"host"`
+	Port      int           `json:"port"`
+	StationID uint          `json:"station_id"`
+	PipePath  string        `json:"pipe_path"`
+	Interval  time.Duration `json:"interval"`
+	MaxAge    time.Duration `json:"max_age"`
+}
+
+type GpsdConfig struct {
+	Enabled   bool          `json:"enabled"`
+	Host      string        `json:"host"`
+	Port      int           `json:"port"`
+	StationID uint          `json:"station_id"`
+	Interval  time.Duration `json:"interval"`
+	MaxAge    time.Duration `json:"max_age"`
+}
+
+type PresenceConfig struct {
+	// OfflineTimeout is how long a station may stay silent before it is
+	// marked offline.
+	OfflineTimeout time.Duration `json:"offline_timeout"`
+	CheckInterval  time.Duration `json:"check_interval"`
+}
+
+type ConfigSyncConfig struct {
+	// AckTimeout is how long a pushed configuration may stay pending.
+	AckTimeout    time.Duration `json:"ack_timeout"`
+	CheckInterval time.Duration `json:"check_interval"`
+}
+
+type ShadowConfig struct {
+	ReconcileInterval time.Duration `json:"reconcile_interval"`
+	// ResendAfter is the minimum time between two pushes to a drifted station.
+	ResendAfter time.Duration `json:"resend_after"`
+	// MaxAttempts stops re-sending to a station whose device did not apply
+	// the configuration after this many pushes. Zero retries forever.
+	MaxAttempts int `json:"max_attempts"`
+}
+
+type RolloutConfig struct {
+	CheckInterval time.Duration `json:"check_interval"`
+	// Defaults for rollouts that do not set their own waves and limits.
+	Waves            []int         `json:"waves"`
+	SettleTime       time.Duration `json:"settle_time"`
+	MaxRateDrop      float64       `json:"max_rate_drop"`
+	MaxErrorIncrease float64       `json:"max_error_increase"`
+	FailureAction    string        `json:"failure_action"`
+}
+
+type PlanningConfig struct {
+	// Window is how far back rangings between clusters are considered.
+	Window time.Duration `json:"window"`
+	// InterferenceRange is the distance in metres within which anchors of
+	// georeferenced clusters are treated as neighbours.
+	InterferenceRange float64 `json:"interference_range"`
+	// Channels restricts the channels the planner may assign, empty allows
+	// every channel the stations support.
+	Channels []int `json:"channels"`
+}
+
+type RangingConfig struct {
+	ExpectedRate              float64       `json:"expected_rate"`
+	FilterStages              []string      `json:"filter_stages"`
+	FilterMinDistance         float64       `json:"filter_min_distance"`
+	FilterMaxDistance         float64       `json:"filter_max_distance"`
+	FilterMaxSpeed            float64       `json:"filter_max_speed"`
+	FilterRateResetAfter      int           `json:"filter_rate_reset_after"`
+	FilterMedianWindow        int           `json:"filter_median_window"`
+	FilterHampelWindow        int           `json:"filter_hampel_window"`
+	FilterHampelThreshold     float64       `json:"filter_hampel_threshold"`
+	FilterHampelMinDeviation  float64       `json:"filter_hampel_min_deviation"`
+	FilterReciprocalTolerance float64       `json:"filter_reciprocal_tolerance"`
+	FilterReciprocalMaxAge    time.Duration `json:"filter_reciprocal_max_age"`
+	// Pairs ranging below FormationMinRate (Hz) within FormationWindow are
+	// ignored when proposing clusters.
+	FormationWindow  time.Duration `json:"formation_window"`
+	FormationMinRate float64       `json:"formation_min_rate"`
+}
+
+type TrackingConfig struct {
+	Model            string        `json:"model"`
+	ProcessNoise     float64       `json:"process_noise"`
+	MeasurementNoise float64       `json:"measurement_noise"`
+	ResetTimeout     time.Duration `json:"reset_timeout"`
 }
 
 func LoadEnvFile() {
@@ -92,13 +210,89 @@ func Load() (*Config, error) {
 			AutoReconnect:        getEnvAsBool("MQTT_AUTO_RECONNECT", true),
 			MaxReconnectInterval: getEnvAsDuration("MQTT_MAX_RECONNECT", 1*time.Second),
 			CleanSession:         getEnvAsBool("MQTT_CLEAN_SESSION", true),
+			WillTopic:            getEnv("MQTT_WILL_TOPIC", ""),
+			PublishTimeout:       getEnvAsDuration("MQTT_PUBLISH_TIMEOUT", 5*time.Second),
+		},
+		Positioning: PositioningConfig{
+			MaxRangingAge:          getEnvAsDuration("POSITIONING_MAX_RANGING_AGE", 5*time.Second),
+			Dimension:              getEnvAsInt("POSITIONING_DIMENSION", 0),
+			MinVerticalSpread:      getEnvAsFloat("POSITIONING_MIN_VERTICAL_SPREAD", 0.5),
+			SelfLocalizationWindow: getEnvAsDuration("POSITIONING_SELF_LOCALIZATION_WINDOW", 10*time.Minute),
+			MaxHDOP:                getEnvAsFloat("POSITIONING_MAX_HDOP", 5),
+			MaxResidual:            getEnvAsFloat("POSITIONING_MAX_RESIDUAL", 0.5),
+			CoverageMaxRange:       getEnvAsFloat("POSITIONING_COVERAGE_MAX_RANGE", 30),
+			CoverageMaxCells:       getEnvAsInt("POSITIONING_COVERAGE_MAX_CELLS", 250000),
+		},
+		Ranging: RangingConfig{
+			ExpectedRate:              getEnvAsFloat("RANGING_EXPECTED_RATE", 10),
+			FilterStages:              getEnvAsStringArray("RANGING_FILTER_STAGES", []string{"bounds", "rate", "hampel", "reciprocal"}),
+			FilterMinDistance:         getEnvAsFloat("RANGING_FILTER_MIN_DISTANCE", 0),
+			FilterMaxDistance:         getEnvAsFloat("RANGING_FILTER_MAX_DISTANCE", 200),
+			FilterMaxSpeed:            getEnvAsFloat("RANGING_FILTER_MAX_SPEED", 10),
+			FilterRateResetAfter:      getEnvAsInt("RANGING_FILTER_RATE_RESET_AFTER", 5),
+			FilterMedianWindow:        getEnvAsInt("RANGING_FILTER_MEDIAN_WINDOW", 5),
+			FilterHampelWindow:        getEnvAsInt("RANGING_FILTER_HAMPEL_WINDOW", 9),
+			FilterHampelThreshold:     getEnvAsFloat("RANGING_FILTER_HAMPEL_THRESHOLD", 3),
+			FilterHampelMinDeviation:  getEnvAsFloat("RANGING_FILTER_HAMPEL_MIN_DEVIATION", 0.05),
+			FilterReciprocalTolerance: getEnvAsFloat("RANGING_FILTER_RECIPROCAL_TOLERANCE", 0.5),
+			FilterReciprocalMaxAge:    getEnvAsDuration("RANGING_FILTER_RECIPROCAL_MAX_AGE", 2*time.Second),
+			FormationWindow:           getEnvAsDuration("RANGING_FORMATION_WINDOW", 5*time.Minute),
+			FormationMinRate:          getEnvAsFloat("RANGING_FORMATION_MIN_RATE", 0.5),
+		},
+		Tracking: TrackingConfig{
+			Model:            getEnv("TRACKING_MODEL", "constant_velocity"),
+			ProcessNoise:     getEnvAsFloat("TRACKING_PROCESS_NOISE", 0.5),
+			MeasurementNoise: getEnvAsFloat("TRACKING_MEASUREMENT_NOISE", 0.1),
+			ResetTimeout:     getEnvAsDuration("TRACKING_RESET_TIMEOUT", 5*time.Second),
+		},
+		Nmea: NmeaConfig{
+			Enabled:   getEnvAsBool("NMEA_ENABLED", false),
+			Host:      getEnv("NMEA_HOST", "localhost"),
+			Port:      getEnvAsInt("NMEA_PORT", 10110),
+			StationID: uint(getEnvAsInt("NMEA_STATION_ID", 0)),
+			PipePath:  getEnv("NMEA_PIPE_PATH", ""),
+			Interval:  getEnvAsDuration("NMEA_INTERVAL", time.Second),
+			MaxAge:    getEnvAsDuration("NMEA_MAX_AGE", 5*time.Second),
+		},
+		Gpsd: GpsdConfig{
+			Enabled:   getEnvAsBool("GPSD_ENABLED", false),
+			Host:      getEnv("GPSD_HOST", "localhost"),
+			Port:      getEnvAsInt("GPSD_PORT", 2947),
+			StationID: uint(getEnvAsInt("GPSD_STATION_ID", 0)),
+			Interval:  getEnvAsDuration("GPSD_INTERVAL", time.Second),
+			MaxAge:    getEnvAsDuration("GPSD_MAX_AGE", 5*time.Second),
+		},
+		Presence: PresenceConfig{
+			OfflineTimeout: getEnvAsDuration("PRESENCE_OFFLINE_TIMEOUT", time.Minute),
+			CheckInterval:  getEnvAsDuration("PRESENCE_CHECK_INTERVAL", 5*time.Second),
+		},
+		ConfigSync: ConfigSyncConfig{
+			AckTimeout:    getEnvAsDuration("CONFIG_SYNC_ACK_TIMEOUT", 30*time.Second),
+			CheckInterval: getEnvAsDuration("CONFIG_SYNC_CHECK_INTERVAL", 5*time.Second),
+		},
+		Shadow: ShadowConfig{
+			ReconcileInterval: getEnvAsDuration("SHADOW_RECONCILE_INTERVAL", 30*time.Second),
+			ResendAfter:       getEnvAsDuration("SHADOW_RESEND_AFTER", 2*time.Minute),
+			MaxAttempts:       getEnvAsInt("SHADOW_MAX_ATTEMPTS", 5),
+		},
+		Rollout: RolloutConfig{
+			CheckInterval:    getEnvAsDuration("ROLLOUT_CHECK_INTERVAL", 10*time.Second),
+			Waves:            getEnvAsIntArray("ROLLOUT_WAVES", []int{10, 50, 100}),
+			SettleTime:       getEnvAsDuration("ROLLOUT_SETTLE_TIME", 5*time.Minute),
+			MaxRateDrop:      getEnvAsFloat("ROLLOUT_MAX_RATE_DROP", 0.3),
+			MaxErrorIncrease: getEnvAsFloat("ROLLOUT_MAX_ERROR_INCREASE", 0.1),
+			FailureAction:    getEnv("ROLLOUT_FAILURE_ACTION", "rollback"),
+		},
+		Planning: PlanningConfig{
+			Window:            getEnvAsDuration("PLANNING_WINDOW", 10*time.Minute),
+			InterferenceRange: getEnvAsFloat("PLANNING_INTERFERENCE_RANGE", 50),
+			Channels:          getEnvAsIntArray("PLANNING_CHANNELS", nil),
 		},
 	}
 
 	return config, nil
 }
 
-//nolint:unused
 func getEnvAsStringArray(key string, fallback []string) []string {
 	valueStr := getEnv(key, "")
 	if valueStr == "" {
@@ -116,6 +310,23 @@ func getEnvAsStringArray(key string, fallback []string) []string {
 	return values
 }
 
+func getEnvAsIntArray(key string, fallback []int) []int {
+	values := make([]int, 0)
+	for _, v := range getEnvAsStringArray(key, nil) {
+		value, err := strconv.Atoi(v)
+		if err != nil {
+			return fallback
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 {
+		return fallback
+	}
+
+	return values
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -133,6 +344,15 @@ func getEnvAsInt(key string, fallback int) int {
 	return fallback
 }
 
+func getEnvAsFloat(key string, fallback float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+
+	return fallback
+}
+
 func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
 	valueStr := getEnv(key, "")
 	if value, err := time.ParseDuration(valueStr); err == nil {
@@ -0,0 +1,129 @@
+package graph
+
+import "sort"
+
+// Edge is an undirected, weighted connection between two nodes.
+type Edge struct {
+	A      uint
+	B      uint
+	Weight float64
+}
+
+type adjacency map[uint]map[uint]float64
+
+func build(nodes []uint, edges []Edge) adjacency {
+	neighbours := make(adjacency, len(nodes))
+	for _, node := range nodes {
+		neighbours[node] = make(map[uint]float64)
+	}
+
+	for _, edge := range edges {
+		if edge.A == edge.B || neighbours[edge.A] == nil || neighbours[edge.B] == nil {
+			continue
+		}
+		neighbours[edge.A][edge.B] += edge.Weight
+		neighbours[edge.B][edge.A] += edge.Weight
+	}
+
+	return neighbours
+}
+
+// Components returns the connected components of the graph. Nodes within a
+// component and the components themselves are ordered by node ID.
+func Components(nodes []uint, edges []Edge) [][]uint {
+	neighbours := build(nodes, edges)
+	labels := make(map[uint]uint, len(nodes))
+
+	for _, node := range sorted(nodes) {
+		if _, visited := labels[node]; visited {
+			continue
+		}
+
+		labels[node] = node
+		queue := []uint{node}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for neighbour := range neighbours[current] {
+				if _, visited := labels[neighbour]; !visited {
+					labels[neighbour] = node
+					queue = append(queue, neighbour)
+				}
+			}
+		}
+	}
+
+	return group(labels)
+}
+
+// Communities splits the graph with weighted label propagation. Nodes are
+// visited in ID order and ties go to the smallest label, so the result is
+// deterministic for a given graph.
+func Communities(nodes []uint, edges []Edge, iterations int) [][]uint {
+	if iterations <= 0 {
+		iterations = 100
+	}
+
+	neighbours := build(nodes, edges)
+	order := sorted(nodes)
+
+	labels := make(map[uint]uint, len(nodes))
+	for _, node := range order {
+		labels[node] = node
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		changed := false
+
+		for _, node := range order {
+			if len(neighbours[node]) == 0 {
+				continue
+			}
+
+			weights := make(map[uint]float64)
+			for neighbour, weight := range neighbours[node] {
+				weights[labels[neighbour]] += weight
+			}
+
+			best, bestWeight := labels[node], weights[labels[node]]
+			for label, weight := range weights {
+				if weight > bestWeight || (weight == bestWeight && label < best) {
+					best, bestWeight = label, weight
+				}
+			}
+
+			if best != labels[node] {
+				labels[node] = best
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	return group(labels)
+}
+
+func group(labels map[uint]uint) [][]uint {
+	groups := make(map[uint][]uint)
+	for node, label := range labels {
+		groups[label] = append(groups[label], node)
+	}
+
+	result := make([][]uint, 0, len(groups))
+	for _, members := range groups {
+		result = append(result, sorted(members))
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i][0] < result[j][0] })
+	return result
+}
+
+func sorted(nodes []uint) []uint {
+	result := append([]uint(nil), nodes...)
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
@@ -0,0 +1,80 @@
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+type Marker struct {
+	Column float64
+	Row    float64
+}
+
+type Options struct {
+	// Min and Max bound the colour scale; values outside are clamped.
+	Min      float64
+	Max      float64
+	CellSize int
+	Markers  []Marker
+}
+
+// Render draws a row-major grid as a green-yellow-red heatmap. Row 0 is
+// drawn at the bottom so that the image matches a y-up coordinate frame.
+func Render(values [][]float64, options Options) *image.RGBA {
+	cellSize := options.CellSize
+	if cellSize <= 0 {
+		cellSize = 1
+	}
+
+	rows := len(values)
+	columns := 0
+	if rows > 0 {
+		columns = len(values[0])
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, columns*cellSize, rows*cellSize))
+
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			c := scale(values[row][column], options.Min, options.Max)
+			top := (rows - 1 - row) * cellSize
+
+			for y := top; y < top+cellSize; y++ {
+				for x := column * cellSize; x < (column+1)*cellSize; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	markerSize := 3
+	for _, marker := range options.Markers {
+		centerX := int(marker.Column * float64(cellSize))
+		centerY := int((float64(rows) - marker.Row) * float64(cellSize))
+
+		for y := centerY - markerSize; y <= centerY+markerSize; y++ {
+			for x := centerX - markerSize; x <= centerX+markerSize; x++ {
+				if image.Pt(x, y).In(img.Bounds()) {
+					img.SetRGBA(x, y, color.RGBA{A: 255})
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+func scale(value, minValue, maxValue float64) color.RGBA {
+	t := 0.0
+	if maxValue > minValue {
+		t = (value - minValue) / (maxValue - minValue)
+	}
+	t = math.Max(0, math.Min(1, t))
+
+	if t < 0.5 {
+		return color.RGBA{R: uint8(510 * t), G: 200, B: 0, A: 255}
+	}
+
+	return color.RGBA{R: 255, G: uint8(200 * (2 - 2*t)), B: 0, A: 255}
+}
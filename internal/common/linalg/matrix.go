@@ -0,0 +1,296 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var ErrSingularMatrix = errors.New("matrix is singular")
+
+type Matrix struct {
+	Rows int
+	Cols int
+	data []float64
+}
+
+func NewMatrix(rows, cols int) *Matrix {
+	return &Matrix{
+		Rows: rows,
+		Cols: cols,
+		data: make([]float64, rows*cols),
+	}
+}
+
+func NewMatrixFromRows(rows [][]float64) *Matrix {
+	if len(rows) == 0 {
+		return NewMatrix(0, 0)
+	}
+
+	m := NewMatrix(len(rows), len(rows[0]))
+	for i, row := range rows {
+		copy(m.data[i*m.Cols:(i+1)*m.Cols], row)
+	}
+
+	return m
+}
+
+func Identity(n int) *Matrix {
+	m := NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+
+	return m
+}
+
+func Diagonal(values ...float64) *Matrix {
+	m := NewMatrix(len(values), len(values))
+	for i, v := range values {
+		m.Set(i, i, v)
+	}
+
+	return m
+}
+
+func (m *Matrix) At(i, j int) float64 {
+	return m.data[i*m.Cols+j]
+}
+
+func (m *Matrix) Set(i, j int, v float64) {
+	m.data[i*m.Cols+j] = v
+}
+
+func (m *Matrix) Clone() *Matrix {
+	clone := NewMatrix(m.Rows, m.Cols)
+	copy(clone.data, m.data)
+	return clone
+}
+
+func (m *Matrix) Row(i int) []float64 {
+	row := make([]float64, m.Cols)
+	copy(row, m.data[i*m.Cols:(i+1)*m.Cols])
+	return row
+}
+
+func (m *Matrix) Col(j int) []float64 {
+	col := make([]float64, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		col[i] = m.At(i, j)
+	}
+	return col
+}
+
+func (m *Matrix) T() *Matrix {
+	t := NewMatrix(m.Cols, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		for j := 0; j < m.Cols; j++ {
+			t.Set(j, i, m.At(i, j))
+		}
+	}
+
+	return t
+}
+
+func (m *Matrix) Mul(other *Matrix) *Matrix {
+	if m.Cols != other.Rows {
+		panic("linalg: dimension mismatch in Mul")
+	}
+
+	result := NewMatrix(m.Rows, other.Cols)
+	for i := 0; i < m.Rows; i++ {
+		for k := 0; k < m.Cols; k++ {
+			a := m.At(i, k)
+			if a == 0 {
+				continue
+			}
+			for j := 0; j < other.Cols; j++ {
+				result.data[i*result.Cols+j] += a * other.At(k, j)
+			}
+		}
+	}
+
+	return result
+}
+
+func (m *Matrix) MulVec(v []float64) []float64 {
+	if m.Cols != len(v) {
+		panic("linalg: dimension mismatch in MulVec")
+	}
+
+	result := make([]float64, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		sum := 0.0
+		for j := 0; j < m.Cols; j++ {
+			sum += m.At(i, j) * v[j]
+		}
+		result[i] = sum
+	}
+
+	return result
+}
+
+func (m *Matrix) Add(other *Matrix) *Matrix {
+	result := m.Clone()
+	for i := range result.data {
+		result.data[i] += other.data[i]
+	}
+	return result
+}
+
+func (m *Matrix) Sub(other *Matrix) *Matrix {
+	result := m.Clone()
+	for i := range result.data {
+		result.data[i] -= other.data[i]
+	}
+	return result
+}
+
+func (m *Matrix) Scale(factor float64) *Matrix {
+	result := m.Clone()
+	for i := range result.data {
+		result.data[i] *= factor
+	}
+	return result
+}
+
+func (m *Matrix) Trace() float64 {
+	sum := 0.0
+	for i := 0; i < m.Rows && i < m.Cols; i++ {
+		sum += m.At(i, i)
+	}
+	return sum
+}
+
+// Inverse uses Gauss-Jordan elimination with partial pivoting.
+func (m *Matrix) Inverse() (*Matrix, error) {
+	if m.Rows != m.Cols {
+		return nil, errors.New("linalg: cannot invert non-square matrix")
+	}
+
+	n := m.Rows
+	a := m.Clone()
+	inv := Identity(n)
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a.At(row, col)) > math.Abs(a.At(pivot, col)) {
+				pivot = row
+			}
+		}
+
+		if math.Abs(a.At(pivot, col)) < 1e-12 {
+			return nil, ErrSingularMatrix
+		}
+
+		if pivot != col {
+			a.swapRows(pivot, col)
+			inv.swapRows(pivot, col)
+		}
+
+		diag := a.At(col, col)
+		for j := 0; j < n; j++ {
+			a.Set(col, j, a.At(col, j)/diag)
+			inv.Set(col, j, inv.At(col, j)/diag)
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col {
+				continue
+			}
+			factor := a.At(row, col)
+			if factor == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				a.Set(row, j, a.At(row, j)-factor*a.At(col, j))
+				inv.Set(row, j, inv.At(row, j)-factor*inv.At(col, j))
+			}
+		}
+	}
+
+	return inv, nil
+}
+
+func (m *Matrix) swapRows(i, j int) {
+	for k := 0; k < m.Cols; k++ {
+		m.data[i*m.Cols+k], m.data[j*m.Cols+k] = m.data[j*m.Cols+k], m.data[i*m.Cols+k]
+	}
+}
+
+// SymmetricEigen computes eigenvalues and eigenvectors of a symmetric matrix
+// with the cyclic Jacobi method. Results are sorted by descending eigenvalue
+// and eigenvectors are stored as columns.
+func (m *Matrix) SymmetricEigen() ([]float64, *Matrix) {
+	n := m.Rows
+	a := m.Clone()
+	vectors := Identity(n)
+
+	for sweep := 0; sweep < 100; sweep++ {
+		offDiagonal := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				offDiagonal += a.At(i, j) * a.At(i, j)
+			}
+		}
+		if offDiagonal < 1e-18 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a.At(p, q)
+				if math.Abs(apq) < 1e-15 {
+					continue
+				}
+
+				theta := (a.At(q, q) - a.At(p, p)) / (2 * apq)
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					akp := a.At(k, p)
+					akq := a.At(k, q)
+					a.Set(k, p, c*akp-s*akq)
+					a.Set(k, q, s*akp+c*akq)
+				}
+				for k := 0; k < n; k++ {
+					apk := a.At(p, k)
+					aqk := a.At(q, k)
+					a.Set(p, k, c*apk-s*aqk)
+					a.Set(q, k, s*apk+c*aqk)
+				}
+				for k := 0; k < n; k++ {
+					vkp := vectors.At(k, p)
+					vkq := vectors.At(k, q)
+					vectors.Set(k, p, c*vkp-s*vkq)
+					vectors.Set(k, q, s*vkp+c*vkq)
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return a.At(order[i], order[i]) > a.At(order[j], order[j])
+	})
+
+	values := make([]float64, n)
+	sorted := NewMatrix(n, n)
+	for col, index := range order {
+		values[col] = a.At(index, index)
+		for row := 0; row < n; row++ {
+			sorted.Set(row, col, vectors.At(row, index))
+		}
+	}
+
+	return values, sorted
+}
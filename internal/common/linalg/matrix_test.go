@@ -0,0 +1,98 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInverse(t *testing.T) {
+	tests := []struct {
+		name     string
+		matrix   [][]float64
+		expected [][]float64
+	}{
+		{
+			name:     "identity",
+			matrix:   [][]float64{{1, 0}, {0, 1}},
+			expected: [][]float64{{1, 0}, {0, 1}},
+		},
+		{
+			name:     "2x2",
+			matrix:   [][]float64{{4, 7}, {2, 6}},
+			expected: [][]float64{{0.6, -0.7}, {-0.2, 0.4}},
+		},
+		{
+			name:     "requires pivoting",
+			matrix:   [][]float64{{0, 1}, {1, 0}},
+			expected: [][]float64{{0, 1}, {1, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inverse, err := NewMatrixFromRows(test.matrix).Inverse()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i, row := range test.expected {
+				for j, value := range row {
+					if math.Abs(inverse.At(i, j)-value) > 1e-9 {
+						t.Errorf("inverse[%d][%d] = %f, want %f", i, j, inverse.At(i, j), value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInverseSingular(t *testing.T) {
+	if _, err := NewMatrixFromRows([][]float64{{1, 2}, {2, 4}}).Inverse(); err == nil {
+		t.Fatal("expected an error for a singular matrix")
+	}
+}
+
+func TestSymmetricEigen(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		values []float64
+	}{
+		{
+			name:   "diagonal",
+			matrix: [][]float64{{1, 0, 0}, {0, 3, 0}, {0, 0, 2}},
+			values: []float64{3, 2, 1},
+		},
+		{
+			name:   "2x2",
+			matrix: [][]float64{{2, 1}, {1, 2}},
+			values: []float64{3, 1},
+		},
+		{
+			name:   "3x3",
+			matrix: [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}},
+			values: []float64{2 + math.Sqrt2, 2, 2 - math.Sqrt2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matrix := NewMatrixFromRows(test.matrix)
+			values, vectors := matrix.SymmetricEigen()
+
+			for k, expected := range test.values {
+				if math.Abs(values[k]-expected) > 1e-9 {
+					t.Errorf("value[%d] = %f, want %f", k, values[k], expected)
+				}
+
+				vector := vectors.Col(k)
+				product := matrix.MulVec(vector)
+				for i := range vector {
+					if math.Abs(product[i]-values[k]*vector[i]) > 1e-9 {
+						t.Errorf("column %d is not an eigenvector: A·v[%d] = %f, λ·v[%d] = %f", k, i, product[i], i, values[k]*vector[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"gps-no-server/internal/core/interfaces"
+	"gps-no-server/internal/core/validation"
 	"reflect"
 	"strconv"
 )
@@ -117,6 +119,9 @@ func (c *BaseController[T, DTO]) Create(ctx *gin.Context) {
 	includeParam := ctx.Query("include")
 
 	createdEntity, err := c.Service.Create(ctx, entity, &includeParam)
+	if writeValidationErrors(ctx, response, err) {
+		return
+	}
 	if err != nil {
 		response["status"] = 500
 		response["message"] = "Failed to create: " + err.Error()
@@ -177,6 +182,9 @@ func (c *BaseController[T, DTO]) Update(ctx *gin.Context) {
 	includeParam := ctx.Query("include")
 
 	updatedEntity, err := c.Service.UpdateFields(ctx, entity, fields, &includeParam)
+	if writeValidationErrors(ctx, response, err) {
+		return
+	}
 	if err != nil {
 		response["status"] = 500
 		response["message"] = "Failed to update: " + err.Error()
@@ -224,3 +232,18 @@ func (c *BaseController[T, DTO]) Delete(ctx *gin.Context) {
 
 	ctx.JSON(200, response)
 }
+
+// writeValidationErrors answers with 422 and the individual field errors
+// when err carries validation errors.
+func writeValidationErrors(ctx *gin.Context, response map[string]interface{}, err error) bool {
+	var validationErrors validation.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return false
+	}
+
+	response["status"] = 422
+	response["message"] = "Validation failed"
+	response["errors"] = validationErrors
+	ctx.JSON(422, response)
+	return true
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/services"
+	"io"
+	"math"
+	"time"
+)
+
+type ChannelPlanController struct {
+	channelPlanService *services.ChannelPlanService
+}
+
+func NewChannelPlanController(channelPlanService *services.ChannelPlanService) *ChannelPlanController {
+	return &ChannelPlanController{
+		channelPlanService: channelPlanService,
+	}
+}
+
+func (c *ChannelPlanController) RegisterRoutes(router *gin.RouterGroup) {
+	api := router.Group("/clusters")
+	{
+		api.GET("/channel-plan", c.GetPlan)
+		api.POST("/channel-plan", c.Plan)
+		api.POST("/channel-plan/apply", c.Apply)
+	}
+}
+
+func (c *ChannelPlanController) GetPlan(ctx *gin.Context) {
+	plan, exists := c.channelPlanService.GetLatest()
+	if !exists {
+		ctx.JSON(404, map[string]interface{}{
+			"status":  404,
+			"message": services.ErrNoChannelPlan.Error(),
+			"payload": nil,
+		})
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved channel plan",
+		"payload": mappers.FromChannelPlan(plan),
+	})
+}
+
+func (c *ChannelPlanController) Plan(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully computed channel plan",
+		"payload": nil,
+	}
+
+	var request dtos.ChannelPlanRequestDto
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	var window time.Duration
+	if request.Window != "" {
+		var err error
+		window, err = time.ParseDuration(request.Window)
+		if err != nil || window <= 0 {
+			response["status"] = 400
+			response["message"] = "Invalid window"
+			ctx.JSON(400, response)
+			return
+		}
+	}
+
+	channels := make([]uint8, 0, len(request.Channels))
+	for _, channel := range request.Channels {
+		if channel > math.MaxUint8 {
+			response["status"] = 400
+			response["message"] = "Invalid channel"
+			ctx.JSON(400, response)
+			return
+		}
+		channels = append(channels, uint8(channel))
+	}
+
+	plan, err := c.channelPlanService.Plan(ctx, window, channels)
+	if err != nil {
+		status := channelPlanErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to compute channel plan: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromChannelPlan(plan)
+	ctx.JSON(200, response)
+}
+
+// Apply writes the proposed channel and preamble code of the selected
+// clusters, or of every changed cluster, and reports the outcome per
+// cluster. A failure still reports the clusters applied before it.
+func (c *ChannelPlanController) Apply(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully applied channel plan",
+		"payload": nil,
+	}
+
+	var request dtos.ChannelPlanApplyDto
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	reports, err := c.channelPlanService.Apply(ctx, request.Clusters)
+
+	payload := make([]*dtos.ConfigApplyReportDto, len(reports))
+	for i, report := range reports {
+		payload[i] = mappers.FromConfigApplyReport(report)
+	}
+
+	if err != nil {
+		status := channelPlanErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to apply channel plan: " + err.Error()
+		if len(reports) > 0 {
+			response["payload"] = payload
+		}
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = payload
+	ctx.JSON(200, response)
+}
+
+func channelPlanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNoChannelPlan),
+		errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	case errors.Is(err, services.ErrClusterNotInPlan),
+		errors.Is(err, services.ErrInvalidPlanningChannel):
+		return 422
+	default:
+		return 500
+	}
+}
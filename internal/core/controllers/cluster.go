@@ -1,19 +1,30 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/models/dtos"
 	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/positioning"
 	"gps-no-server/internal/core/services"
+	"io"
+	"strconv"
+	"time"
 )
 
 type ClusterController struct {
 	*BaseController[*models.Cluster, dtos.ClusterDto]
-	clusterService *services.ClusterService
+	clusterService          *services.ClusterService
+	selfLocalizationService *services.SelfLocalizationService
+	formationService        *services.ClusterFormationService
 }
 
-func NewClusterController(clusterService *services.ClusterService) *ClusterController {
+func NewClusterController(
+	clusterService *services.ClusterService,
+	selfLocalizationService *services.SelfLocalizationService,
+	formationService *services.ClusterFormationService,
+) *ClusterController {
 	baseController := NewBaseController[*models.Cluster, dtos.ClusterDto](
 		clusterService,
 		mappers.ToCluster,
@@ -22,11 +33,225 @@ func NewClusterController(clusterService *services.ClusterService) *ClusterContr
 	)
 
 	return &ClusterController{
-		BaseController: baseController,
-		clusterService: clusterService,
+		BaseController:          baseController,
+		clusterService:          clusterService,
+		selfLocalizationService: selfLocalizationService,
+		formationService:        formationService,
 	}
 }
 
 func (c *ClusterController) RegisterRoutes(router *gin.RouterGroup) {
 	c.BaseController.RegisterRoutes(router)
+
+	api := router.Group("/clusters")
+	{
+		api.POST("/:id/self-localize", c.SelfLocalize)
+		api.POST("/:id/self-localize/accept", c.AcceptSelfLocalization)
+		api.GET("/formation", c.GetFormation)
+		api.POST("/formation", c.ProposeFormation)
+		api.POST("/formation/accept", c.AcceptFormation)
+	}
+}
+
+func (c *ClusterController) SelfLocalize(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully computed anchor self-localization",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	var request dtos.SelfLocalizeRequestDto
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	var window time.Duration
+	if request.Window != "" {
+		window, err = time.ParseDuration(request.Window)
+		if err != nil || window <= 0 {
+			response["status"] = 400
+			response["message"] = "Invalid window"
+			ctx.JSON(400, response)
+			return
+		}
+	}
+
+	if request.Dimension != 0 && request.Dimension != 2 && request.Dimension != 3 {
+		response["status"] = 400
+		response["message"] = "Invalid dimension, expected 2 or 3"
+		ctx.JSON(400, response)
+		return
+	}
+
+	result, err := c.selfLocalizationService.Localize(ctx, uint(id), request.Pinned, request.Dimension, window)
+	if err != nil {
+		status := selfLocalizationErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to self-localize anchors: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromSelfLocalization(result)
+	ctx.JSON(200, response)
+}
+
+func (c *ClusterController) AcceptSelfLocalization(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully accepted anchor self-localization",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	var request dtos.SelfLocalizeAcceptDto
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	result, err := c.selfLocalizationService.Accept(ctx, uint(id), request.StationIDs)
+	if err != nil {
+		status := selfLocalizationErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to accept self-localization: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromSelfLocalization(result)
+	ctx.JSON(200, response)
+}
+
+func (c *ClusterController) GetFormation(ctx *gin.Context) {
+	proposal, exists := c.formationService.GetLatest()
+	if !exists {
+		ctx.JSON(404, map[string]interface{}{
+			"status":  404,
+			"message": services.ErrNoClusterProposal.Error(),
+			"payload": nil,
+		})
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved cluster proposal",
+		"payload": mappers.FromClusterProposal(proposal),
+	})
+}
+
+func (c *ClusterController) ProposeFormation(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully computed cluster proposal",
+		"payload": nil,
+	}
+
+	var request dtos.ClusterFormationRequestDto
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	var window time.Duration
+	if request.Window != "" {
+		var err error
+		window, err = time.ParseDuration(request.Window)
+		if err != nil || window <= 0 {
+			response["status"] = 400
+			response["message"] = "Invalid window"
+			ctx.JSON(400, response)
+			return
+		}
+	}
+
+	proposal, err := c.formationService.Propose(ctx, models.FormationMethod(request.Method), window)
+	if err != nil {
+		status := formationErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to propose clusters: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromClusterProposal(proposal)
+	ctx.JSON(200, response)
+}
+
+func (c *ClusterController) AcceptFormation(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully accepted cluster proposal",
+		"payload": nil,
+	}
+
+	var request dtos.ClusterFormationAcceptDto
+	if err := ctx.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	proposal, err := c.formationService.Accept(ctx, request.Clusters)
+	if err != nil {
+		status := formationErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to accept cluster proposal: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromClusterProposal(proposal)
+	ctx.JSON(200, response)
+}
+
+func formationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNoClusterProposal):
+		return 404
+	case errors.Is(err, services.ErrInvalidFormationMethod),
+		errors.Is(err, services.ErrInvalidProposalIndex):
+		return 422
+	default:
+		return 500
+	}
+}
+
+func selfLocalizationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNoSelfLocalization):
+		return 404
+	case errors.Is(err, services.ErrInvalidPinnedAnchors),
+		errors.Is(err, services.ErrStationNotInProposal),
+		errors.Is(err, positioning.ErrNotEnoughAnchors),
+		errors.Is(err, positioning.ErrDisconnectedAnchors),
+		errors.Is(err, positioning.ErrNoPinnedAnchors):
+		return 422
+	default:
+		return 500
+	}
 }
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/services"
+	"strconv"
+)
+
+type ConfigurationProfileController struct {
+	*BaseController[*models.ConfigurationProfile, dtos.ConfigurationProfileDto]
+	profileService *services.ConfigurationProfileService
+}
+
+func NewConfigurationProfileController(profileService *services.ConfigurationProfileService) *ConfigurationProfileController {
+	baseController := NewBaseController[*models.ConfigurationProfile, dtos.ConfigurationProfileDto](
+		profileService,
+		mappers.ToConfigurationProfile,
+		mappers.FromConfigurationProfile,
+		"/configuration-profiles",
+	)
+
+	return &ConfigurationProfileController{
+		BaseController: baseController,
+		profileService: profileService,
+	}
+}
+
+// RegisterRoutes replaces the generic delete so profiles in use are
+// answered with a conflict.
+func (c *ConfigurationProfileController) RegisterRoutes(router *gin.RouterGroup) {
+	c.Router = router.Group(c.Path)
+	{
+		c.Router.GET("", c.GetAll)
+		c.Router.GET("/:id", c.GetById)
+		c.Router.POST("", c.Create)
+		c.Router.PUT("/:id", c.Update)
+		c.Router.DELETE("/:id", c.Delete)
+	}
+
+	stations := router.Group("/stations")
+	{
+		stations.GET("/:id/effective-configuration", c.GetEffective)
+		stations.POST("/:id/configuration/apply", c.ApplyToStation)
+	}
+
+	clusters := router.Group("/clusters")
+	{
+		clusters.POST("/:id/configuration/apply", c.ApplyToCluster)
+	}
+}
+
+func (c *ConfigurationProfileController) Delete(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully deleted data",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	profile, err := c.profileService.GetById(ctx, uint(id), nil)
+	if err != nil {
+		status := profileErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Entity not found: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	if err := c.profileService.Delete(ctx, profile, nil); err != nil {
+		status := profileErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to delete: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = profile
+	ctx.JSON(200, response)
+}
+
+func (c *ConfigurationProfileController) GetEffective(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully computed effective configuration",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	effective, err := c.profileService.GetEffective(ctx, uint(id))
+	if err != nil {
+		status := profileErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to compute effective configuration: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromEffectiveConfiguration(effective)
+	ctx.JSON(200, response)
+}
+
+func (c *ConfigurationProfileController) ApplyToStation(ctx *gin.Context) {
+	c.apply(ctx, c.profileService.ApplyToStation)
+}
+
+// ApplyToCluster writes the effective configuration of every cluster member
+// in one transaction and reports the outcome per station.
+func (c *ConfigurationProfileController) ApplyToCluster(ctx *gin.Context) {
+	c.apply(ctx, c.profileService.ApplyToCluster)
+}
+
+func (c *ConfigurationProfileController) apply(ctx *gin.Context, apply func(ctx context.Context, id uint) (*models.ConfigApplyReport, error)) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully applied configuration",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	report, err := apply(ctx, uint(id))
+	if err != nil {
+		status := profileErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to apply configuration: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromConfigApplyReport(report)
+	ctx.JSON(200, response)
+}
+
+func profileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	case errors.Is(err, services.ErrProfileInUse):
+		return 409
+	default:
+		return 500
+	}
+}
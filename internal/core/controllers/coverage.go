@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/common/heatmap"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/positioning"
+	"gps-no-server/internal/core/services"
+	"image"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// coverageImageSize is the approximate edge length in pixels of rendered
+// coverage maps.
+const coverageImageSize = 800
+
+type CoverageController struct {
+	coverageService *services.CoverageService
+}
+
+func NewCoverageController(coverageService *services.CoverageService) *CoverageController {
+	return &CoverageController{
+		coverageService: coverageService,
+	}
+}
+
+func (c *CoverageController) RegisterRoutes(router *gin.RouterGroup) {
+	clusters := router.Group("/clusters")
+	{
+		clusters.POST("/:id/coverage", c.Compute)
+	}
+}
+
+func (c *CoverageController) Compute(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully computed coverage map",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	var request dtos.CoverageRequestDto
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	if request.Dimension != 0 && request.Dimension != 2 && request.Dimension != 3 {
+		response["status"] = 400
+		response["message"] = "Invalid dimension, expected 2 or 3"
+		ctx.JSON(400, response)
+		return
+	}
+
+	if request.Resolution < 0 || (request.MaxRange != nil && *request.MaxRange < 0) {
+		response["status"] = 400
+		response["message"] = "Resolution and max range must not be negative"
+		ctx.JSON(400, response)
+		return
+	}
+
+	coverage, err := c.coverageService.Compute(ctx, uint(id), mappers.ToCoverageRequest(&request))
+	if err != nil {
+		status := coverageErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to compute coverage map: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	if wantsPNG(ctx) {
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, renderCoverage(coverage)); err != nil {
+			response["status"] = 500
+			response["message"] = "Failed to render coverage map: " + err.Error()
+			ctx.JSON(500, response)
+			return
+		}
+
+		ctx.Data(200, "image/png", buffer.Bytes())
+		return
+	}
+
+	response["payload"] = mappers.FromCoverageMap(coverage)
+	ctx.JSON(200, response)
+}
+
+func wantsPNG(ctx *gin.Context) bool {
+	if format := ctx.Query("format"); format != "" {
+		return format == "png"
+	}
+
+	return strings.Contains(ctx.GetHeader("Accept"), "image/png")
+}
+
+// renderCoverage colours HDOP from 1 (green) to 10 (red) and marks every
+// anchor of the map.
+func renderCoverage(coverage *models.CoverageMap) *image.RGBA {
+	cellSize := max(1, coverageImageSize/max(coverage.Columns, coverage.Rows))
+
+	markers := make([]heatmap.Marker, 0, len(coverage.Anchors))
+	for _, anchor := range coverage.Anchors {
+		markers = append(markers, heatmap.Marker{
+			Column: (anchor.X - coverage.MinX) / coverage.Resolution,
+			Row:    (anchor.Y - coverage.MinY) / coverage.Resolution,
+		})
+	}
+
+	return heatmap.Render(coverage.HDOP, heatmap.Options{
+		Min:      1,
+		Max:      10,
+		CellSize: cellSize,
+		Markers:  markers,
+	})
+}
+
+func coverageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	case errors.Is(err, positioning.ErrInvalidCoverageArea),
+		errors.Is(err, positioning.ErrCoverageGridTooLarge),
+		errors.Is(err, services.ErrCoverageTooLarge):
+		return 422
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/positioning"
+	"gps-no-server/internal/core/services"
+	"strconv"
+)
+
+type PositionController struct {
+	positionService *services.PositionService
+	trackingService *services.TrackingService
+	eventService    *services.EventStreamService
+}
+
+func NewPositionController(positionService *services.PositionService, trackingService *services.TrackingService, eventService *services.EventStreamService) *PositionController {
+	return &PositionController{
+		positionService: positionService,
+		trackingService: trackingService,
+		eventService:    eventService,
+	}
+}
+
+func (c *PositionController) RegisterRoutes(router *gin.RouterGroup) {
+	api := router.Group("/stations")
+	{
+		api.GET("/:id/position", c.GetByStationId)
+		api.GET("/:id/position/stream", c.StreamByStationId)
+	}
+
+	positions := router.Group("/positions")
+	{
+		positions.GET("", c.GetAll)
+		positions.GET("/stream", c.StreamAllPositionEvents)
+	}
+}
+
+func (c *PositionController) GetAll(ctx *gin.Context) {
+	includeParam := ctx.Query("include")
+
+	ctx.JSON(200, map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved positions",
+		"payload": mappers.FromPositionFixList(c.positionService.GetAll(), &includeParam),
+	})
+}
+
+func (c *PositionController) StreamAllPositionEvents(ctx *gin.Context) {
+	c.eventService.HandleSSERequest(ctx, services.PositionEventType)
+}
+
+func (c *PositionController) StreamByStationId(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	c.eventService.HandleSSERequest(ctx, services.PositionEventType, uint(id))
+}
+
+func (c *PositionController) GetByStationId(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved position",
+		"payload": nil,
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	mode := ctx.DefaultQuery("mode", "raw")
+	if mode != "raw" && mode != "filtered" {
+		response["status"] = 400
+		response["message"] = "Invalid mode, expected raw or filtered"
+		ctx.JSON(400, response)
+		return
+	}
+
+	includeParam := ctx.Query("include")
+	refresh, _ := strconv.ParseBool(ctx.Query("refresh"))
+
+	if mode == "filtered" {
+		if refresh {
+			response["status"] = 400
+			response["message"] = "Refresh is only supported for raw positions"
+			ctx.JSON(400, response)
+			return
+		}
+
+		tracked, exists := c.trackingService.GetLatest(uint(id))
+		if !exists {
+			response["status"] = 404
+			response["message"] = "No filtered position available"
+			ctx.JSON(404, response)
+			return
+		}
+
+		response["payload"] = mappers.FromTrackedPosition(tracked, &includeParam)
+		ctx.JSON(200, response)
+		return
+	}
+
+	fix, exists := c.positionService.GetLatest(uint(id))
+	if !exists || refresh {
+		fix, err = c.positionService.Solve(ctx, uint(id))
+		if err != nil {
+			status := positionErrorStatus(err)
+			response["status"] = status
+			response["message"] = "Failed to compute position: " + err.Error()
+			ctx.JSON(status, response)
+			return
+		}
+	}
+
+	response["payload"] = mappers.FromPositionFix(fix, &includeParam)
+	ctx.JSON(200, response)
+}
+
+func positionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	case errors.Is(err, services.ErrStationNotTag),
+		errors.Is(err, services.ErrStationNotInCluster),
+		errors.Is(err, positioning.ErrNotEnoughAnchors),
+		errors.Is(err, positioning.ErrNoConvergence),
+		errors.Is(err, positioning.ErrSingularGeometry):
+		return 422
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+func parseOptionalUint(ctx *gin.Context, name string) (*uint, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+
+	result := uint(parsed)
+	return &result, nil
+}
+
+func parseTimeRange(ctx *gin.Context, defaultWindow time.Duration) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := ctx.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to timestamp, expected RFC3339")
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultWindow)
+	if value := ctx.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from timestamp, expected RFC3339")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+
+	return from, to, nil
+}
+
+func parseOptionalDuration(ctx *gin.Context, name string) (time.Duration, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+
+	return parsed, nil
+}
@@ -1,12 +1,22 @@
 package controllers
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/models/dtos"
 	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/repositories"
 	"gps-no-server/internal/core/services"
 	"strconv"
+	"time"
+)
+
+const (
+	defaultHistoryWindow = time.Hour
+	defaultStatsWindow   = 5 * time.Minute
+	defaultHistoryLimit  = 1000
+	maxHistoryLimit      = 10000
 )
 
 type RangingController struct {
@@ -37,7 +47,165 @@ func (c *RangingController) RegisterRoutes(router *gin.RouterGroup) {
 	{
 		api.GET("/stream", c.StreamAllRangingEvents)
 		api.GET("/stream/:id", c.StreamRangingById)
+		api.GET("/history", c.GetHistory)
+		api.GET("/stats", c.GetStats)
+		api.GET("/rejections", c.GetRejections)
+	}
+}
+
+func (c *RangingController) GetRejections(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved ranging rejections",
+		"payload": nil,
+	}
+
+	filter, err := parseRangingSampleFilter(ctx)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = err.Error()
+		ctx.JSON(400, response)
+		return
+	}
+	filter.Status = repositories.RejectedSamples
+	filter.Interval = 0
+	filter.Order = "desc"
+
+	includeParam := ctx.Query("include")
+	samples, _, err := c.rangingService.GetHistory(ctx, filter, &includeParam)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	statistics := mappers.FromRangingFilterStatistics(c.rangingService.GetFilterStatistics(), &includeParam)
+	statistics.RecentSamples = mappers.FromRangingSamplePointList(samples)
+
+	response["payload"] = statistics
+	ctx.JSON(200, response)
+}
+
+func (c *RangingController) GetStats(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved ranging statistics",
+		"payload": []interface{}{},
+	}
+
+	var filter repositories.RangingStatsFilter
+	var err error
+
+	filter.ClusterID, err = parseOptionalUint(ctx, "cluster_id")
+	if err == nil {
+		filter.From, filter.To, err = parseTimeRange(ctx, defaultStatsWindow)
+	}
+
+	expectedRate := 0.0
+	if value := ctx.Query("expected_rate"); err == nil && value != "" {
+		expectedRate, err = strconv.ParseFloat(value, 64)
+		if err != nil || expectedRate <= 0 {
+			err = fmt.Errorf("invalid expected_rate")
+		}
+	}
+
+	if err != nil {
+		response["status"] = 400
+		response["message"] = err.Error()
+		ctx.JSON(400, response)
+		return
+	}
+
+	includeParam := ctx.Query("include")
+	stats, err := c.rangingService.GetStats(ctx, filter, expectedRate, &includeParam)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	response["payload"] = mappers.FromRangingPairStatsList(stats)
+	ctx.JSON(200, response)
+}
+
+func (c *RangingController) GetHistory(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved ranging history",
+		"payload": []interface{}{},
+	}
+
+	filter, err := parseRangingSampleFilter(ctx)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = err.Error()
+		ctx.JSON(400, response)
+		return
+	}
+
+	includeParam := ctx.Query("include")
+	points, truncated, err := c.rangingService.GetHistory(ctx, filter, &includeParam)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	if truncated {
+		response["message"] = fmt.Sprintf("Successfully retrieved ranging history, limited to the newest %d entries", filter.Limit)
+	}
+	response["truncated"] = truncated
+	response["payload"] = mappers.FromRangingSamplePointList(points)
+	ctx.JSON(200, response)
+}
+
+func parseRangingSampleFilter(ctx *gin.Context) (repositories.RangingSampleFilter, error) {
+	var filter repositories.RangingSampleFilter
+	var err error
+
+	if filter.SourceID, err = parseOptionalUint(ctx, "source_id"); err != nil {
+		return filter, err
+	}
+
+	if filter.DestinationID, err = parseOptionalUint(ctx, "destination_id"); err != nil {
+		return filter, err
+	}
+
+	if filter.StationID, err = parseOptionalUint(ctx, "station_id"); err != nil {
+		return filter, err
+	}
+
+	if filter.From, filter.To, err = parseTimeRange(ctx, defaultHistoryWindow); err != nil {
+		return filter, err
+	}
+
+	if filter.Interval, err = parseOptionalDuration(ctx, "interval"); err != nil {
+		return filter, err
+	}
+
+	filter.Status = repositories.RangingSampleStatus(ctx.DefaultQuery("status", string(repositories.AcceptedSamples)))
+	if filter.Status != repositories.AcceptedSamples && filter.Status != repositories.RejectedSamples && filter.Status != repositories.AllSamples {
+		return filter, fmt.Errorf("invalid status, expected accepted, rejected or all")
+	}
+
+	filter.Order = ctx.DefaultQuery("order", "asc")
+	if filter.Order != "asc" && filter.Order != "desc" {
+		return filter, fmt.Errorf("invalid order, expected asc or desc")
+	}
+
+	filter.Limit = defaultHistoryLimit
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit")
+		}
+		filter.Limit = min(limit, maxHistoryLimit)
 	}
+
+	return filter, nil
 }
 
 func (c *RangingController) StreamAllRangingEvents(ctx *gin.Context) {
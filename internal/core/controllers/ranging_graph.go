@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/services"
+	"strconv"
+	"strings"
+)
+
+const dotContentType = "text/vnd.graphviz"
+
+type RangingGraphController struct {
+	rangingGraphService *services.RangingGraphService
+}
+
+func NewRangingGraphController(rangingGraphService *services.RangingGraphService) *RangingGraphController {
+	return &RangingGraphController{
+		rangingGraphService: rangingGraphService,
+	}
+}
+
+func (c *RangingGraphController) RegisterRoutes(router *gin.RouterGroup) {
+	clusters := router.Group("/clusters")
+	{
+		clusters.GET("/:id/ranging-graph", c.GetByCluster)
+	}
+}
+
+func (c *RangingGraphController) GetByCluster(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved ranging graph",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	from, to, err := parseTimeRange(ctx, defaultStatsWindow)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = err.Error()
+		ctx.JSON(400, response)
+		return
+	}
+
+	graph, err := c.rangingGraphService.GetGraph(ctx, uint(id), from, to)
+	if err != nil {
+		status := 500
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = 404
+		}
+		response["status"] = status
+		response["message"] = "Failed to build ranging graph: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	if wantsDOT(ctx) {
+		ctx.Data(200, dotContentType, []byte(renderRangingGraphDOT(graph)))
+		return
+	}
+
+	response["payload"] = mappers.FromRangingGraph(graph)
+	ctx.JSON(200, response)
+}
+
+func wantsDOT(ctx *gin.Context) bool {
+	if format := ctx.Query("format"); format != "" {
+		return format == "dot"
+	}
+
+	return strings.Contains(ctx.GetHeader("Accept"), dotContentType)
+}
+
+// renderRangingGraphDOT writes the graph as a Graphviz digraph. Positioned
+// stations are pinned so neato reproduces the floor layout; stale pairs are
+// drawn dashed.
+func renderRangingGraphDOT(graph *models.RangingGraph) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "digraph ranging_cluster_%d {\n", graph.ClusterID)
+	builder.WriteString("\tnode [fontname=\"Helvetica\"];\n")
+	builder.WriteString("\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, node := range graph.Nodes {
+		shape := "ellipse"
+		if node.Mode == models.AnchorMode {
+			shape = "box"
+		}
+
+		attributes := fmt.Sprintf("label=%s, shape=%s", strconv.Quote(fmt.Sprintf("%s\n%s", node.Name, node.Mode)), shape)
+		if node.Position.IsSet() {
+			attributes += fmt.Sprintf(", pos=\"%g,%g!\"", *node.Position.X, *node.Position.Y)
+		}
+		if node.Degree == 0 {
+			attributes += ", color=red"
+		}
+
+		fmt.Fprintf(&builder, "\t%d [%s];\n", node.StationID, attributes)
+	}
+
+	for _, edge := range graph.Edges {
+		attributes := fmt.Sprintf("label=\"%.2f m\\n%.1f Hz\"", edge.Distance, edge.SampleRate)
+		if edge.Stale {
+			attributes += ", style=dashed, color=gray"
+		}
+
+		fmt.Fprintf(&builder, "\t%d -> %d [%s];\n", edge.SourceID, edge.DestinationID, attributes)
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/services"
+	"strconv"
+	"time"
+)
+
+type RolloutController struct {
+	rolloutService *services.RolloutService
+}
+
+func NewRolloutController(rolloutService *services.RolloutService) *RolloutController {
+	return &RolloutController{
+		rolloutService: rolloutService,
+	}
+}
+
+func (c *RolloutController) RegisterRoutes(router *gin.RouterGroup) {
+	api := router.Group("/rollouts")
+	{
+		api.GET("", c.GetAll)
+		api.POST("", c.Create)
+		api.GET("/:id", c.GetById)
+		api.POST("/:id/pause", c.Pause)
+		api.POST("/:id/resume", c.Resume)
+		api.POST("/:id/rollback", c.Rollback)
+		api.POST("/:id/abort", c.Abort)
+	}
+}
+
+func (c *RolloutController) GetAll(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved rollouts",
+		"payload": nil,
+	}
+
+	var rollouts []*models.Rollout
+	var err error
+	if status := ctx.Query("status"); status != "" {
+		rollouts, err = c.rolloutService.GetByStatus(ctx, models.RolloutStatus(status))
+	} else {
+		rollouts, err = c.rolloutService.GetAll(ctx, nil)
+	}
+	if err != nil {
+		response["status"] = 500
+		response["message"] = "Failed to retrieve rollouts: " + err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	response["payload"] = mappers.FromRolloutList(rollouts)
+	ctx.JSON(200, response)
+}
+
+func (c *RolloutController) GetById(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved rollout",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	rollout, err := c.rolloutService.GetById(ctx, uint(id), nil)
+	if err != nil {
+		status := rolloutErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to retrieve rollout: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromRollout(rollout)
+	ctx.JSON(200, response)
+}
+
+// Create starts a rollout and applies its first wave right away. Waves,
+// limits and failure action default to the server configuration.
+func (c *RolloutController) Create(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  201,
+		"message": "Successfully started rollout",
+		"payload": nil,
+	}
+
+	var request dtos.RolloutRequestDto
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid request body"
+		ctx.JSON(400, response)
+		return
+	}
+
+	rollout := mappers.ToRollout(&request)
+	if request.SettleTime != "" {
+		settleTime, err := time.ParseDuration(request.SettleTime)
+		if err != nil || settleTime <= 0 {
+			response["status"] = 400
+			response["message"] = "Invalid settle time"
+			ctx.JSON(400, response)
+			return
+		}
+		rollout.SettleTime = settleTime
+	}
+
+	rollout, err := c.rolloutService.Create(ctx, rollout, nil)
+	if err != nil {
+		status := rolloutErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to start rollout: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromRollout(rollout)
+	ctx.JSON(201, response)
+}
+
+func (c *RolloutController) Pause(ctx *gin.Context) {
+	c.transition(ctx, "paused", c.rolloutService.Pause)
+}
+
+func (c *RolloutController) Resume(ctx *gin.Context) {
+	c.transition(ctx, "resumed", c.rolloutService.Resume)
+}
+
+func (c *RolloutController) Rollback(ctx *gin.Context) {
+	c.transition(ctx, "rolled back", c.rolloutService.Rollback)
+}
+
+func (c *RolloutController) Abort(ctx *gin.Context) {
+	c.transition(ctx, "aborted", c.rolloutService.Abort)
+}
+
+func (c *RolloutController) transition(ctx *gin.Context, action string, transition func(ctx context.Context, id uint) (*models.Rollout, error)) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully " + action + " rollout",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	rollout, err := transition(ctx, uint(id))
+	if err != nil {
+		status := rolloutErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to update rollout: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromRollout(rollout)
+	ctx.JSON(200, response)
+}
+
+func rolloutErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	case errors.Is(err, services.ErrRolloutInProgress),
+		errors.Is(err, services.ErrRolloutNotRunning),
+		errors.Is(err, services.ErrRolloutNotPaused),
+		errors.Is(err, services.ErrRolloutNotActive):
+		return 409
+	case errors.Is(err, services.ErrRolloutNoStations),
+		errors.Is(err, services.ErrRolloutTooFewStations),
+		errors.Is(err, services.ErrInvalidRolloutWaves),
+		errors.Is(err, services.ErrInvalidFailureAction),
+		errors.Is(err, services.ErrInvalidRolloutLimits):
+		return 422
+	default:
+		return 500
+	}
+}
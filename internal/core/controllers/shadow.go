@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/services"
+	"strconv"
+)
+
+type ShadowController struct {
+	shadowService *services.ShadowService
+}
+
+func NewShadowController(shadowService *services.ShadowService) *ShadowController {
+	return &ShadowController{
+		shadowService: shadowService,
+	}
+}
+
+func (c *ShadowController) RegisterRoutes(router *gin.RouterGroup) {
+	api := router.Group("/stations")
+	{
+		api.GET("/:id/shadow", c.GetByStationId)
+	}
+
+	shadows := router.Group("/shadows")
+	{
+		shadows.GET("", c.GetAll)
+		shadows.POST("/reconcile", c.Reconcile)
+	}
+}
+
+func (c *ShadowController) GetByStationId(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved station shadow",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	shadow, err := c.shadowService.GetByStationId(ctx, uint(id))
+	if err != nil {
+		status := shadowErrorStatus(err)
+		response["status"] = status
+		response["message"] = err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromStationShadow(shadow)
+	ctx.JSON(200, response)
+}
+
+func (c *ShadowController) GetAll(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved station shadows",
+		"payload": []interface{}{},
+	}
+
+	drifted, _ := strconv.ParseBool(ctx.Query("drifted"))
+
+	shadows, err := c.shadowService.GetAll(ctx, drifted)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	response["payload"] = mappers.FromStationShadowList(shadows)
+	ctx.JSON(200, response)
+}
+
+// Reconcile runs a reconciliation pass immediately instead of waiting for
+// the next interval and returns the shadows that were re-sent.
+func (c *ShadowController) Reconcile(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully reconciled station shadows",
+		"payload": []interface{}{},
+	}
+
+	shadows, err := c.shadowService.Reconcile(ctx)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	response["payload"] = mappers.FromStationShadowList(shadows)
+	ctx.JSON(200, response)
+}
+
+func shadowErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	default:
+		return 500
+	}
+}
@@ -6,14 +6,17 @@ import (
 	"gps-no-server/internal/core/models/dtos"
 	"gps-no-server/internal/core/models/mappers"
 	"gps-no-server/internal/core/services"
+	"strconv"
 )
 
 type StationController struct {
 	*BaseController[*models.Station, dtos.StationDto]
-	stationService *services.StationService
+	stationService  *services.StationService
+	presenceService *services.PresenceService
+	eventService    *services.EventStreamService
 }
 
-func NewStationController(stationService *services.StationService) *StationController {
+func NewStationController(stationService *services.StationService, presenceService *services.PresenceService, eventService *services.EventStreamService) *StationController {
 	baseController := NewBaseController[*models.Station, dtos.StationDto](
 		stationService,
 		mappers.ToStation,
@@ -22,14 +25,80 @@ func NewStationController(stationService *services.StationService) *StationContr
 	)
 
 	return &StationController{
-		BaseController: baseController,
-		stationService: stationService,
+		BaseController:  baseController,
+		stationService:  stationService,
+		presenceService: presenceService,
+		eventService:    eventService,
 	}
 }
 
+// RegisterRoutes replaces the generic listing so it can filter by status.
 func (c *StationController) RegisterRoutes(router *gin.RouterGroup) {
-	c.BaseController.RegisterRoutes(router)
-	c.Router.GET("/mac/:mac", c.GetByMacAddress)
+	c.Router = router.Group(c.Path)
+	{
+		c.Router.GET("", c.GetAll)
+		c.Router.GET("/:id", c.GetById)
+		c.Router.POST("", c.Create)
+		c.Router.PUT("/:id", c.Update)
+		c.Router.DELETE("/:id", c.Delete)
+		c.Router.GET("/mac/:mac", c.GetByMacAddress)
+		c.Router.GET("/presence/stream", c.StreamAllPresenceEvents)
+		c.Router.GET("/:id/presence/stream", c.StreamPresenceByStationId)
+	}
+}
+
+func (c *StationController) GetAll(ctx *gin.Context) {
+	status := ctx.Query("status")
+	if status == "" {
+		c.BaseController.GetAll(ctx)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved data",
+		"payload": []interface{}{},
+	}
+
+	if !models.StationStatus(status).IsValid() {
+		response["status"] = 400
+		response["message"] = "Invalid status, expected online, offline or unknown"
+		ctx.JSON(400, response)
+		return
+	}
+
+	stations, err := c.presenceService.GetByStatus(ctx, models.StationStatus(status))
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	includeParam := ctx.Query("include")
+	payload := make([]*dtos.StationDto, 0, len(stations))
+	for _, station := range stations {
+		payload = append(payload, mappers.FromStation(station, &includeParam))
+	}
+
+	response["payload"] = payload
+	ctx.JSON(200, response)
+}
+
+func (c *StationController) StreamAllPresenceEvents(ctx *gin.Context) {
+	c.eventService.HandleSSERequest(ctx, services.PresenceEventType)
+}
+
+func (c *StationController) StreamPresenceByStationId(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	c.eventService.HandleSSERequest(ctx, services.PresenceEventType, uint(id))
 }
 
 func (c *StationController) GetByMacAddress(ctx *gin.Context) {
@@ -1,19 +1,24 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/models/dtos"
 	"gps-no-server/internal/core/models/mappers"
 	"gps-no-server/internal/core/services"
+	"strconv"
 )
 
 type StationConfigController struct {
 	*BaseController[*models.StationConfiguration, dtos.StationConfigurationDto]
 	StationConfigService *services.StationConfigurationService
+	configSyncService    *services.ConfigSyncService
+	eventService         *services.EventStreamService
 }
 
-func NewStationConfigController(stationConfigService *services.StationConfigurationService) *StationConfigController {
+func NewStationConfigController(stationConfigService *services.StationConfigurationService, configSyncService *services.ConfigSyncService, eventService *services.EventStreamService) *StationConfigController {
 	baseController := NewBaseController[*models.StationConfiguration, dtos.StationConfigurationDto](
 		stationConfigService,
 		mappers.ToStationConfig,
@@ -24,9 +29,237 @@ func NewStationConfigController(stationConfigService *services.StationConfigurat
 	return &StationConfigController{
 		BaseController:       baseController,
 		StationConfigService: stationConfigService,
+		configSyncService:    configSyncService,
+		eventService:         eventService,
 	}
 }
 
 func (c *StationConfigController) RegisterRoutes(router *gin.RouterGroup) {
 	c.BaseController.RegisterRoutes(router)
+
+	c.Router.GET("/sync", c.GetSyncByStatus)
+	c.Router.GET("/sync/stream", c.StreamAllSyncEvents)
+	c.Router.GET("/:id/sync", c.GetSync)
+	c.Router.POST("/:id/sync", c.Push)
+	c.Router.GET("/:id/sync/stream", c.StreamSyncById)
+	c.Router.GET("/:id/revisions", c.GetRevisions)
+	c.Router.GET("/:id/revisions/:version", c.GetRevision)
+	c.Router.POST("/:id/revisions/:version/rollback", c.Rollback)
+}
+
+func (c *StationConfigController) GetSyncByStatus(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved configuration sync states",
+		"payload": []interface{}{},
+	}
+
+	status := models.ConfigSyncStatus(ctx.DefaultQuery("status", string(models.SyncPending)))
+	if !status.IsValid() {
+		response["status"] = 400
+		response["message"] = "Invalid status, expected pending, applied, failed or timed_out"
+		ctx.JSON(400, response)
+		return
+	}
+
+	stationConfigs, err := c.configSyncService.GetByStatus(ctx, status)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	response["payload"] = mappers.FromConfigSyncList(stationConfigs)
+	ctx.JSON(200, response)
+}
+
+func (c *StationConfigController) GetSync(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved configuration sync state",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	stationConfig, err := c.StationConfigService.GetById(ctx, uint(id), nil)
+	if err != nil {
+		status := stationConfigErrorStatus(err)
+		response["status"] = status
+		response["message"] = err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromConfigSync(stationConfig)
+	ctx.JSON(200, response)
+}
+
+// Push publishes the stored configuration again under a new revision.
+func (c *StationConfigController) Push(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  202,
+		"message": "Successfully pushed configuration",
+		"payload": nil,
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	stationConfig, err := c.StationConfigService.GetById(ctx, uint(id), nil)
+	if err == nil {
+		stationConfig, err = c.configSyncService.Push(ctx, stationConfig)
+	}
+	if err != nil {
+		status := stationConfigErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to push configuration: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromConfigSync(stationConfig)
+	ctx.JSON(202, response)
+}
+
+func (c *StationConfigController) StreamAllSyncEvents(ctx *gin.Context) {
+	c.eventService.HandleSSERequest(ctx, services.ConfigSyncEventType)
+}
+
+func (c *StationConfigController) StreamSyncById(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	c.eventService.HandleSSERequest(ctx, services.ConfigSyncEventType, uint(id))
+}
+
+func (c *StationConfigController) GetRevisions(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved configuration revisions",
+		"payload": []interface{}{},
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	revisions, err := c.StationConfigService.GetRevisions(ctx, uint(id))
+	if err != nil {
+		status := stationConfigErrorStatus(err)
+		response["status"] = status
+		response["message"] = err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromConfigurationRevisionList(revisions)
+	ctx.JSON(200, response)
+}
+
+func (c *StationConfigController) GetRevision(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved configuration revision",
+		"payload": nil,
+	}
+
+	id, version, ok := parseRevisionParams(ctx, response)
+	if !ok {
+		return
+	}
+
+	revision, err := c.StationConfigService.GetRevision(ctx, id, version)
+	if err != nil {
+		status := stationConfigErrorStatus(err)
+		response["status"] = status
+		response["message"] = err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromConfigurationRevision(revision)
+	ctx.JSON(200, response)
+}
+
+// Rollback restores a revision through the regular update path, which
+// validates it, records a new revision and pushes it to the device.
+func (c *StationConfigController) Rollback(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully rolled back configuration",
+		"payload": nil,
+	}
+
+	id, version, ok := parseRevisionParams(ctx, response)
+	if !ok {
+		return
+	}
+
+	includeParam := ctx.Query("include")
+
+	stationConfig, err := c.StationConfigService.Rollback(ctx, id, version, &includeParam)
+	if writeValidationErrors(ctx, response, err) {
+		return
+	}
+	if err != nil {
+		status := stationConfigErrorStatus(err)
+		response["status"] = status
+		response["message"] = "Failed to roll back configuration: " + err.Error()
+		ctx.JSON(status, response)
+		return
+	}
+
+	response["payload"] = mappers.FromStationConfig(stationConfig, &includeParam)
+	ctx.JSON(200, response)
+}
+
+func parseRevisionParams(ctx *gin.Context, response map[string]interface{}) (uint, uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return 0, 0, false
+	}
+
+	version, err := strconv.ParseUint(ctx.Param("version"), 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid version format"
+		ctx.JSON(400, response)
+		return 0, 0, false
+	}
+
+	return uint(id), uint(version), true
+}
+
+func stationConfigErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return 404
+	default:
+		return 500
+	}
 }
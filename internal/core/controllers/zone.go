@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/services"
+	"strconv"
+)
+
+type ZoneController struct {
+	*BaseController[*models.Zone, dtos.ZoneDto]
+	zoneService  *services.ZoneService
+	eventService *services.EventStreamService
+}
+
+func NewZoneController(zoneService *services.ZoneService, eventService *services.EventStreamService) *ZoneController {
+	baseController := NewBaseController[*models.Zone, dtos.ZoneDto](
+		zoneService,
+		mappers.ToZone,
+		mappers.FromZone,
+		"/zones",
+	)
+
+	return &ZoneController{
+		BaseController: baseController,
+		zoneService:    zoneService,
+		eventService:   eventService,
+	}
+}
+
+func (c *ZoneController) RegisterRoutes(router *gin.RouterGroup) {
+	c.BaseController.RegisterRoutes(router)
+
+	api := router.Group("/zones")
+	{
+		api.GET("/stream", c.StreamAllZoneEvents)
+		api.GET("/stream/:id", c.StreamZoneById)
+	}
+
+	clusters := router.Group("/clusters")
+	{
+		clusters.GET("/:id/zones", c.GetByCluster)
+	}
+}
+
+func (c *ZoneController) GetByCluster(ctx *gin.Context) {
+	response := map[string]interface{}{
+		"status":  200,
+		"message": "Successfully retrieved zones",
+		"payload": []interface{}{},
+	}
+
+	idParam := ctx.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response["status"] = 400
+		response["message"] = "Invalid ID format"
+		ctx.JSON(400, response)
+		return
+	}
+
+	includeParam := ctx.Query("include")
+	zones, err := c.zoneService.GetByCluster(ctx, uint(id), &includeParam)
+	if err != nil {
+		response["status"] = 500
+		response["message"] = err.Error()
+		ctx.JSON(500, response)
+		return
+	}
+
+	payload := make([]*dtos.ZoneDto, 0, len(zones))
+	for _, zone := range zones {
+		payload = append(payload, mappers.FromZone(zone, &includeParam))
+	}
+
+	response["payload"] = payload
+	ctx.JSON(200, response)
+}
+
+func (c *ZoneController) StreamAllZoneEvents(ctx *gin.Context) {
+	c.eventService.HandleSSERequest(ctx, services.ZoneEventType)
+}
+
+func (c *ZoneController) StreamZoneById(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	c.eventService.HandleSSERequest(ctx, services.ZoneEventType, uint(id))
+}
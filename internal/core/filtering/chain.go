@@ -0,0 +1,109 @@
+package filtering
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Pair struct {
+	SourceID      uint
+	DestinationID uint
+}
+
+func (p Pair) Reverse() Pair {
+	return Pair{SourceID: p.DestinationID, DestinationID: p.SourceID}
+}
+
+type Sample struct {
+	Pair      Pair
+	Timestamp time.Time
+	Distance  float64
+}
+
+type Result struct {
+	Accepted bool
+	Distance float64
+	Stage    string
+	Reason   string
+}
+
+type Stage interface {
+	Name() string
+	// Process returns an empty reason when the sample passes. Stages may
+	// adjust sample.Distance for later stages.
+	Process(sample *Sample) string
+}
+
+type Chain struct {
+	stages []Stage
+	lock   sync.Mutex
+}
+
+func NewChain(stages ...Stage) *Chain {
+	return &Chain{
+		stages: stages,
+	}
+}
+
+func (c *Chain) Stages() []string {
+	names := make([]string, len(c.stages))
+	for i, stage := range c.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+func (c *Chain) Process(sample Sample) Result {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, stage := range c.stages {
+		if reason := stage.Process(&sample); reason != "" {
+			return Result{
+				Accepted: false,
+				Distance: sample.Distance,
+				Stage:    stage.Name(),
+				Reason:   reason,
+			}
+		}
+	}
+
+	return Result{Accepted: true, Distance: sample.Distance}
+}
+
+type Options struct {
+	MinDistance         float64
+	MaxDistance         float64
+	MaxSpeed            float64
+	RateResetAfter      int
+	MedianWindow        int
+	HampelWindow        int
+	HampelThreshold     float64
+	HampelMinDeviation  float64
+	ReciprocalTolerance float64
+	ReciprocalMaxAge    time.Duration
+}
+
+func BuildChain(names []string, options Options) (*Chain, error) {
+	stages := make([]Stage, 0, len(names))
+
+	for _, name := range names {
+		switch name {
+		case BoundsStageName:
+			stages = append(stages, NewBoundsStage(options.MinDistance, options.MaxDistance))
+		case RateOfChangeStageName:
+			stages = append(stages, NewRateOfChangeStage(options.MaxSpeed, options.RateResetAfter))
+		case MedianStageName:
+			stages = append(stages, NewMedianStage(options.MedianWindow))
+		case HampelStageName:
+			stages = append(stages, NewHampelStage(options.HampelWindow, options.HampelThreshold, options.HampelMinDeviation))
+		case ReciprocalStageName:
+			stages = append(stages, NewReciprocalStage(options.ReciprocalTolerance, options.ReciprocalMaxAge))
+		default:
+			return nil, fmt.Errorf("unknown ranging filter stage: %s", name)
+		}
+	}
+
+	return NewChain(stages...), nil
+}
@@ -0,0 +1,83 @@
+package filtering
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChainProcess(t *testing.T) {
+	chain := NewChain(NewBoundsStage(0.1, 100), NewMedianStage(3))
+
+	tests := []struct {
+		name     string
+		distance float64
+		expected Result
+	}{
+		{name: "first sample", distance: 4, expected: Result{Accepted: true, Distance: 4}},
+		{name: "smoothed", distance: 6, expected: Result{Accepted: true, Distance: 5}},
+		{
+			name:     "rejected before smoothing",
+			distance: 150,
+			expected: Result{Accepted: false, Distance: 150, Stage: BoundsStageName, Reason: "distance 150.000 outside [0.100, 100.000]"},
+		},
+		{name: "rejected sample left no trace", distance: 5, expected: Result{Accepted: true, Distance: 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := chain.Process(Sample{Pair: testPair, Timestamp: testStart, Distance: test.distance})
+			if result != test.expected {
+				t.Errorf("result = %+v, want %+v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestBuildChain(t *testing.T) {
+	options := Options{
+		MinDistance:         0.1,
+		MaxDistance:         100,
+		MaxSpeed:            3,
+		MedianWindow:        5,
+		HampelWindow:        7,
+		HampelThreshold:     3,
+		ReciprocalTolerance: 0.3,
+		ReciprocalMaxAge:    time.Second,
+	}
+
+	tests := []struct {
+		name    string
+		stages  []string
+		wantErr bool
+	}{
+		{name: "empty", stages: nil},
+		{name: "all stages", stages: []string{BoundsStageName, RateOfChangeStageName, MedianStageName, HampelStageName, ReciprocalStageName}},
+		{name: "keeps order", stages: []string{ReciprocalStageName, BoundsStageName}},
+		{name: "unknown stage", stages: []string{BoundsStageName, "kalman"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain, err := BuildChain(test.stages, options)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			names := chain.Stages()
+			if len(names) != len(test.stages) {
+				t.Fatalf("stages = %v, want %v", names, test.stages)
+			}
+			for i := range names {
+				if names[i] != test.stages[i] {
+					t.Errorf("stage %d = %s, want %s", i, names[i], test.stages[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package filtering
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	BoundsStageName       = "bounds"
+	RateOfChangeStageName = "rate"
+	MedianStageName       = "median"
+	HampelStageName       = "hampel"
+	ReciprocalStageName   = "reciprocal"
+)
+
+type BoundsStage struct {
+	min float64
+	max float64
+}
+
+func NewBoundsStage(min, max float64) *BoundsStage {
+	return &BoundsStage{min: min, max: max}
+}
+
+func (s *BoundsStage) Name() string {
+	return BoundsStageName
+}
+
+func (s *BoundsStage) Process(sample *Sample) string {
+	if math.IsNaN(sample.Distance) || math.IsInf(sample.Distance, 0) {
+		return "distance is not a finite number"
+	}
+
+	if sample.Distance < s.min || sample.Distance > s.max {
+		return fmt.Sprintf("distance %.3f outside [%.3f, %.3f]", sample.Distance, s.min, s.max)
+	}
+
+	return ""
+}
+
+type rateState struct {
+	distance   float64
+	timestamp  time.Time
+	rejections int
+}
+
+// RateOfChangeStage gates samples whose implied speed between consecutive
+// measurements of a pair is physically implausible.
+type RateOfChangeStage struct {
+	maxSpeed    float64
+	resetAfter  int
+	lastSamples map[Pair]*rateState
+}
+
+func NewRateOfChangeStage(maxSpeed float64, resetAfter int) *RateOfChangeStage {
+	return &RateOfChangeStage{
+		maxSpeed:    maxSpeed,
+		resetAfter:  resetAfter,
+		lastSamples: make(map[Pair]*rateState),
+	}
+}
+
+func (s *RateOfChangeStage) Name() string {
+	return RateOfChangeStageName
+}
+
+func (s *RateOfChangeStage) Process(sample *Sample) string {
+	last, exists := s.lastSamples[sample.Pair]
+	if !exists {
+		s.lastSamples[sample.Pair] = &rateState{distance: sample.Distance, timestamp: sample.Timestamp}
+		return ""
+	}
+
+	dt := sample.Timestamp.Sub(last.timestamp).Seconds()
+	if dt > 0 {
+		speed := math.Abs(sample.Distance-last.distance) / dt
+		if speed > s.maxSpeed && (s.resetAfter <= 0 || last.rejections < s.resetAfter) {
+			last.rejections++
+			return fmt.Sprintf("rate of change %.2f m/s exceeds %.2f m/s", speed, s.maxSpeed)
+		}
+	}
+
+	last.distance = sample.Distance
+	last.timestamp = sample.Timestamp
+	last.rejections = 0
+	return ""
+}
+
+type window struct {
+	values []float64
+	size   int
+}
+
+func (w *window) push(value float64) {
+	w.values = append(w.values, value)
+	if len(w.values) > w.size {
+		w.values = w.values[len(w.values)-w.size:]
+	}
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+type MedianStage struct {
+	size    int
+	windows map[Pair]*window
+}
+
+func NewMedianStage(size int) *MedianStage {
+	return &MedianStage{
+		size:    size,
+		windows: make(map[Pair]*window),
+	}
+}
+
+func (s *MedianStage) Name() string {
+	return MedianStageName
+}
+
+func (s *MedianStage) Process(sample *Sample) string {
+	w, exists := s.windows[sample.Pair]
+	if !exists {
+		w = &window{size: s.size}
+		s.windows[sample.Pair] = w
+	}
+
+	w.push(sample.Distance)
+	sample.Distance = median(w.values)
+	return ""
+}
+
+// HampelStage rejects samples that deviate from the rolling median by more
+// than threshold times the scaled median absolute deviation.
+type HampelStage struct {
+	size         int
+	threshold    float64
+	minDeviation float64
+	windows      map[Pair]*window
+}
+
+func NewHampelStage(size int, threshold, minDeviation float64) *HampelStage {
+	return &HampelStage{
+		size:         size,
+		threshold:    threshold,
+		minDeviation: minDeviation,
+		windows:      make(map[Pair]*window),
+	}
+}
+
+func (s *HampelStage) Name() string {
+	return HampelStageName
+}
+
+func (s *HampelStage) Process(sample *Sample) string {
+	w, exists := s.windows[sample.Pair]
+	if !exists {
+		w = &window{size: s.size}
+		s.windows[sample.Pair] = w
+	}
+	defer w.push(sample.Distance)
+
+	if len(w.values) < 3 {
+		return ""
+	}
+
+	center := median(w.values)
+	deviations := make([]float64, len(w.values))
+	for i, value := range w.values {
+		deviations[i] = math.Abs(value - center)
+	}
+
+	sigma := math.Max(1.4826*median(deviations), s.minDeviation)
+	if math.Abs(sample.Distance-center) > s.threshold*sigma {
+		return fmt.Sprintf("distance %.3f deviates from median %.3f by more than %.1f sigma", sample.Distance, center, s.threshold)
+	}
+
+	return ""
+}
+
+type ReciprocalStage struct {
+	tolerance float64
+	maxAge    time.Duration
+	latest    map[Pair]Sample
+}
+
+func NewReciprocalStage(tolerance float64, maxAge time.Duration) *ReciprocalStage {
+	return &ReciprocalStage{
+		tolerance: tolerance,
+		maxAge:    maxAge,
+		latest:    make(map[Pair]Sample),
+	}
+}
+
+func (s *ReciprocalStage) Name() string {
+	return ReciprocalStageName
+}
+
+func (s *ReciprocalStage) Process(sample *Sample) string {
+	s.latest[sample.Pair] = *sample
+
+	reverse, exists := s.latest[sample.Pair.Reverse()]
+	if !exists || sample.Timestamp.Sub(reverse.Timestamp) > s.maxAge {
+		return ""
+	}
+
+	if difference := math.Abs(sample.Distance - reverse.Distance); difference > s.tolerance {
+		return fmt.Sprintf("distance differs from reverse direction by %.3f m", difference)
+	}
+
+	return ""
+}
@@ -0,0 +1,218 @@
+package filtering
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var (
+	testPair  = Pair{SourceID: 1, DestinationID: 2}
+	testStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+)
+
+type step struct {
+	offset   time.Duration
+	pair     Pair
+	distance float64
+	rejected bool
+	output   float64
+}
+
+func runSteps(t *testing.T, stage Stage, steps []step) {
+	t.Helper()
+
+	for i, s := range steps {
+		pair := s.pair
+		if pair == (Pair{}) {
+			pair = testPair
+		}
+
+		sample := Sample{Pair: pair, Timestamp: testStart.Add(s.offset), Distance: s.distance}
+		reason := stage.Process(&sample)
+
+		if rejected := reason != ""; rejected != s.rejected {
+			t.Errorf("step %d: rejected = %t (%q), want %t", i, rejected, reason, s.rejected)
+		}
+		if s.output != 0 && math.Abs(sample.Distance-s.output) > 1e-9 {
+			t.Errorf("step %d: distance = %f, want %f", i, sample.Distance, s.output)
+		}
+	}
+}
+
+func TestBoundsStage(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance float64
+		rejected bool
+	}{
+		{name: "inside", distance: 5},
+		{name: "lower bound", distance: 0.1},
+		{name: "upper bound", distance: 100},
+		{name: "below minimum", distance: 0.05, rejected: true},
+		{name: "above maximum", distance: 100.5, rejected: true},
+		{name: "negative", distance: -1, rejected: true},
+		{name: "not a number", distance: math.NaN(), rejected: true},
+		{name: "infinite", distance: math.Inf(1), rejected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runSteps(t, NewBoundsStage(0.1, 100), []step{{distance: test.distance, rejected: test.rejected}})
+		})
+	}
+}
+
+func TestRateOfChangeStage(t *testing.T) {
+	tests := []struct {
+		name       string
+		resetAfter int
+		steps      []step
+	}{
+		{
+			name: "plausible movement",
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, distance: 6},
+				{offset: 2 * time.Second, distance: 7.5},
+			},
+		},
+		{
+			name: "jump is rejected and compared against the last accepted sample",
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, distance: 15, rejected: true},
+				{offset: 2 * time.Second, distance: 6},
+			},
+		},
+		{
+			name: "pairs are tracked independently",
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, pair: testPair.Reverse(), distance: 20},
+				{offset: 2 * time.Second, distance: 5.5},
+			},
+		},
+		{
+			name:       "accepts the new distance after repeated rejections",
+			resetAfter: 2,
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, distance: 20, rejected: true},
+				{offset: 2 * time.Second, distance: 20, rejected: true},
+				{offset: 3 * time.Second, distance: 20},
+				{offset: 4 * time.Second, distance: 20.5},
+			},
+		},
+		{
+			name: "samples without elapsed time are not gated",
+			steps: []step{
+				{distance: 5},
+				{distance: 9},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runSteps(t, NewRateOfChangeStage(3, test.resetAfter), test.steps)
+		})
+	}
+}
+
+func TestMedianStage(t *testing.T) {
+	runSteps(t, NewMedianStage(3), []step{
+		{distance: 5, output: 5},
+		{distance: 7, output: 6},
+		{distance: 6, output: 6},
+		{distance: 20, output: 7},
+		{distance: 8, output: 8},
+		{pair: testPair.Reverse(), distance: 1, output: 1},
+	})
+}
+
+func TestHampelStage(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "needs three samples before judging",
+			steps: []step{
+				{distance: 5},
+				{distance: 50},
+				{distance: 5},
+			},
+		},
+		{
+			name: "rejects a spike",
+			steps: []step{
+				{distance: 5},
+				{distance: 5.01},
+				{distance: 4.99},
+				{distance: 5.02},
+				{distance: 7, rejected: true},
+				{distance: 5.03},
+			},
+		},
+		{
+			name: "minimum deviation tolerates noise on a flat window",
+			steps: []step{
+				{distance: 5},
+				{distance: 5},
+				{distance: 5},
+				{distance: 5.2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runSteps(t, NewHampelStage(5, 3, 0.1), test.steps)
+		})
+	}
+}
+
+func TestReciprocalStage(t *testing.T) {
+	reverse := testPair.Reverse()
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "matching reverse distance",
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, pair: reverse, distance: 5.2},
+			},
+		},
+		{
+			name: "mismatching reverse distance",
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, pair: reverse, distance: 6, rejected: true},
+			},
+		},
+		{
+			name: "reverse sample too old",
+			steps: []step{
+				{distance: 5},
+				{offset: 10 * time.Second, pair: reverse, distance: 6},
+			},
+		},
+		{
+			name: "no reverse sample",
+			steps: []step{
+				{distance: 5},
+				{offset: time.Second, distance: 9},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runSteps(t, NewReciprocalStage(0.3, 5*time.Second), test.steps)
+		})
+	}
+}
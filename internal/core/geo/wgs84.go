@@ -0,0 +1,159 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// WGS84 ellipsoid parameters.
+const (
+	SemiMajorAxis = 6378137.0
+	Flattening    = 1 / 298.257223563
+	SemiMinorAxis = SemiMajorAxis * (1 - Flattening)
+	eccentricity2 = Flattening * (2 - Flattening)
+)
+
+type Geodetic struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+}
+
+type ECEF struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+func GeodeticToECEF(position Geodetic) ECEF {
+	lat := radians(position.Latitude)
+	lon := radians(position.Longitude)
+
+	n := SemiMajorAxis / math.Sqrt(1-eccentricity2*math.Sin(lat)*math.Sin(lat))
+
+	return ECEF{
+		X: (n + position.Altitude) * math.Cos(lat) * math.Cos(lon),
+		Y: (n + position.Altitude) * math.Cos(lat) * math.Sin(lon),
+		Z: (n*(1-eccentricity2) + position.Altitude) * math.Sin(lat),
+	}
+}
+
+// ECEFToGeodetic converts with Bowring's method followed by a few fixed-point
+// iterations, which is accurate to well below a millimetre near the surface.
+func ECEFToGeodetic(position ECEF) Geodetic {
+	p := math.Hypot(position.X, position.Y)
+	lon := math.Atan2(position.Y, position.X)
+
+	secondEccentricity2 := (SemiMajorAxis*SemiMajorAxis - SemiMinorAxis*SemiMinorAxis) / (SemiMinorAxis * SemiMinorAxis)
+	theta := math.Atan2(position.Z*SemiMajorAxis, p*SemiMinorAxis)
+	lat := math.Atan2(
+		position.Z+secondEccentricity2*SemiMinorAxis*math.Pow(math.Sin(theta), 3),
+		p-eccentricity2*SemiMajorAxis*math.Pow(math.Cos(theta), 3),
+	)
+
+	var alt float64
+	for i := 0; i < 3; i++ {
+		n := SemiMajorAxis / math.Sqrt(1-eccentricity2*math.Sin(lat)*math.Sin(lat))
+		alt = p/math.Cos(lat) - n
+		lat = math.Atan2(position.Z, p*(1-eccentricity2*n/(n+alt)))
+	}
+
+	n := SemiMajorAxis / math.Sqrt(1-eccentricity2*math.Sin(lat)*math.Sin(lat))
+	if math.Abs(math.Cos(lat)) > 1e-12 {
+		alt = p/math.Cos(lat) - n
+	} else {
+		alt = math.Abs(position.Z) - SemiMinorAxis
+	}
+
+	return Geodetic{Latitude: degrees(lat), Longitude: degrees(lon), Altitude: alt}
+}
+
+// Frame describes a local cartesian frame anchored at a geodetic origin.
+// Rotation is the counter-clockwise angle in degrees from east to the local
+// x axis; local z points up. Scale converts local units to metres.
+type Frame struct {
+	Origin   Geodetic
+	Rotation float64
+	Scale    float64
+}
+
+// ToENU converts a local coordinate in frame units to east/north/up metres.
+func (f Frame) ToENU(x, y, z float64) (float64, float64, float64) {
+	scale := f.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	angle := radians(f.Rotation)
+	east := (x*math.Cos(angle) - y*math.Sin(angle)) * scale
+	north := (x*math.Sin(angle) + y*math.Cos(angle)) * scale
+
+	return east, north, z * scale
+}
+
+func (f Frame) FromENU(east, north, up float64) (float64, float64, float64) {
+	scale := f.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	angle := radians(f.Rotation)
+	x := east*math.Cos(angle) + north*math.Sin(angle)
+	y := -east*math.Sin(angle) + north*math.Cos(angle)
+
+	return x / scale, y / scale, up / scale
+}
+
+func (f Frame) ToGeodetic(x, y, z float64) Geodetic {
+	east, north, up := f.ToENU(x, y, z)
+
+	lat := radians(f.Origin.Latitude)
+	lon := radians(f.Origin.Longitude)
+	origin := GeodeticToECEF(f.Origin)
+
+	return ECEFToGeodetic(ECEF{
+		X: origin.X - math.Sin(lon)*east - math.Sin(lat)*math.Cos(lon)*north + math.Cos(lat)*math.Cos(lon)*up,
+		Y: origin.Y + math.Cos(lon)*east - math.Sin(lat)*math.Sin(lon)*north + math.Cos(lat)*math.Sin(lon)*up,
+		Z: origin.Z + math.Cos(lat)*north + math.Sin(lat)*up,
+	})
+}
+
+func (f Frame) FromGeodetic(position Geodetic) (float64, float64, float64) {
+	lat := radians(f.Origin.Latitude)
+	lon := radians(f.Origin.Longitude)
+	origin := GeodeticToECEF(f.Origin)
+	target := GeodeticToECEF(position)
+
+	dx, dy, dz := target.X-origin.X, target.Y-origin.Y, target.Z-origin.Z
+	east := -math.Sin(lon)*dx + math.Cos(lon)*dy
+	north := -math.Sin(lat)*math.Cos(lon)*dx - math.Sin(lat)*math.Sin(lon)*dy + math.Cos(lat)*dz
+	up := math.Cos(lat)*math.Cos(lon)*dx + math.Cos(lat)*math.Sin(lon)*dy + math.Sin(lat)*dz
+
+	return f.FromENU(east, north, up)
+}
+
+// UnitScale returns the number of metres in one unit of the given length unit.
+func UnitScale(units string) (float64, error) {
+	switch units {
+	case "", "m":
+		return 1, nil
+	case "cm":
+		return 0.01, nil
+	case "mm":
+		return 0.001, nil
+	case "ft":
+		return 0.3048, nil
+	case "in":
+		return 0.0254, nil
+	default:
+		return 0, fmt.Errorf("unknown unit: %s", units)
+	}
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeodeticToECEF(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Geodetic
+		expected ECEF
+	}{
+		{name: "equator at prime meridian", position: Geodetic{}, expected: ECEF{X: SemiMajorAxis}},
+		{name: "equator at 90 degrees east", position: Geodetic{Longitude: 90}, expected: ECEF{Y: SemiMajorAxis}},
+		{name: "north pole", position: Geodetic{Latitude: 90}, expected: ECEF{Z: SemiMinorAxis}},
+		{name: "south pole above ellipsoid", position: Geodetic{Latitude: -90, Altitude: 100}, expected: ECEF{Z: -SemiMinorAxis - 100}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position := GeodeticToECEF(test.position)
+			if math.Abs(position.X-test.expected.X) > 1e-6 ||
+				math.Abs(position.Y-test.expected.Y) > 1e-6 ||
+				math.Abs(position.Z-test.expected.Z) > 1e-6 {
+				t.Errorf("ecef = %+v, want %+v", position, test.expected)
+			}
+		})
+	}
+}
+
+func TestECEFRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Geodetic
+	}{
+		{name: "origin", position: Geodetic{}},
+		{name: "munich", position: Geodetic{Latitude: 48.137154, Longitude: 11.576124, Altitude: 519}},
+		{name: "sydney", position: Geodetic{Latitude: -33.8688, Longitude: 151.2093, Altitude: 58}},
+		{name: "below sea level", position: Geodetic{Latitude: 31.5, Longitude: 35.5, Altitude: -430}},
+		{name: "high altitude", position: Geodetic{Latitude: 27.9881, Longitude: 86.925, Altitude: 8848}},
+		{name: "near the pole", position: Geodetic{Latitude: 89.999, Longitude: -45, Altitude: 10}},
+		{name: "date line", position: Geodetic{Latitude: -10, Longitude: -179.9999, Altitude: 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position := ECEFToGeodetic(GeodeticToECEF(test.position))
+
+			if math.Abs(position.Latitude-test.position.Latitude) > 1e-9 ||
+				math.Abs(position.Longitude-test.position.Longitude) > 1e-9 ||
+				math.Abs(position.Altitude-test.position.Altitude) > 1e-4 {
+				t.Errorf("geodetic = %+v, want %+v", position, test.position)
+			}
+		})
+	}
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	frame := Frame{
+		Origin:   Geodetic{Latitude: 48.137154, Longitude: 11.576124, Altitude: 519},
+		Rotation: 30,
+		Scale:    0.01,
+	}
+
+	tests := []struct {
+		name    string
+		x, y, z float64
+	}{
+		{name: "origin"},
+		{name: "on the x axis", x: 1000},
+		{name: "in the plane", x: -2500, y: 4000},
+		{name: "above the plane", x: 1200, y: -800, z: 350},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x, y, z := frame.FromGeodetic(frame.ToGeodetic(test.x, test.y, test.z))
+
+			// A millimetre is 0.1 frame units at this scale.
+			if math.Abs(x-test.x) > 0.1 || math.Abs(y-test.y) > 0.1 || math.Abs(z-test.z) > 0.1 {
+				t.Errorf("local = (%f, %f, %f), want (%f, %f, %f)", x, y, z, test.x, test.y, test.z)
+			}
+		})
+	}
+}
+
+func TestFrameToENU(t *testing.T) {
+	frame := Frame{Rotation: 90, Scale: 2}
+
+	east, north, up := frame.ToENU(1, 0, 3)
+	if math.Abs(east) > 1e-12 || math.Abs(north-2) > 1e-12 || math.Abs(up-6) > 1e-12 {
+		t.Errorf("enu = (%f, %f, %f), want (0, 2, 6)", east, north, up)
+	}
+}
@@ -0,0 +1,6 @@
+package interfaces
+
+// MessagePublisher sends a payload to a broker topic.
+type MessagePublisher interface {
+	Publish(topic string, qos int, retained bool, payload []byte) error
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type AdjacencyReason string
+
+const (
+	// NeighbourAdjacency comes from the configured neighbour list.
+	NeighbourAdjacency AdjacencyReason = "neighbour"
+	// RangingAdjacency means stations of both clusters ranged with each
+	// other within the planning window.
+	RangingAdjacency AdjacencyReason = "ranging"
+	// OverlapAdjacency means anchors of both clusters are within the
+	// interference range of each other.
+	OverlapAdjacency AdjacencyReason = "overlap"
+)
+
+type ClusterAdjacency struct {
+	ClusterID   uint
+	NeighbourID uint
+	Reasons     []AdjacencyReason
+}
+
+type ChannelAssignment struct {
+	Channel      uint8
+	PreambleCode uint8
+	PRF          uint8
+}
+
+// ClusterChannelPlan is the proposed assignment of one cluster. Conflicts
+// lists neighbours left on the same channel and code, SharedChannel those
+// on the same channel with another code.
+type ClusterChannelPlan struct {
+	ClusterID     uint
+	Name          string
+	Stations      int
+	Current       ChannelAssignment
+	Proposed      ChannelAssignment
+	Changed       bool
+	Neighbours    []uint
+	Conflicts     []uint
+	SharedChannel []uint
+}
+
+type ChannelPlan struct {
+	Clusters        []*ClusterChannelPlan
+	Adjacencies     []*ClusterAdjacency
+	ConflictsBefore int
+	ConflictsAfter  int
+	Warnings        []string
+	From            time.Time
+	To              time.Time
+	Timestamp       time.Time
+}
@@ -6,9 +6,46 @@ import (
 
 type Cluster struct {
 	gorm.Model
-	Name        string    `gorm:"size:100;unique;not null"`
-	Description string    `gorm:"type:text"`
-	Stations    []Station `gorm:"foreignKey:ClusterID"`
+	Name        string           `gorm:"size:100;unique;not null"`
+	Description string           `gorm:"type:text"`
+	Stations    []Station        `gorm:"foreignKey:ClusterID"`
+	Tracking    TrackingSettings `gorm:"embedded;embeddedPrefix:tracking_"`
+	Frame       ClusterFrame     `gorm:"embedded;embeddedPrefix:frame_"`
+	// ProfileID is the configuration profile of stations without their own.
+	ProfileID *uint                 `gorm:"index"`
+	Profile   *ConfigurationProfile `gorm:"foreignKey:ProfileID"`
+	// NeighbourIDs are clusters close enough to interfere with this one.
+	// The relation is symmetric, listing it on either side is enough.
+	NeighbourIDs []uint `gorm:"serializer:json;type:jsonb"`
+}
+
+// ClusterFrame defines the local coordinate frame of a cluster. Rotation is
+// the counter-clockwise angle in degrees from east to the local x axis.
+type ClusterFrame struct {
+	OriginLatitude  *float64
+	OriginLongitude *float64
+	OriginAltitude  *float64
+	Rotation        *float64
+	Units           string `gorm:"type:varchar(10)"`
+	Level           *int
+}
+
+func (f ClusterFrame) IsSet() bool {
+	return f.IsGeoreferenced() || f.Rotation != nil || f.Units != "" || f.Level != nil
+}
+
+func (f ClusterFrame) IsGeoreferenced() bool {
+	return f.OriginLatitude != nil && f.OriginLongitude != nil
+}
+
+type TrackingSettings struct {
+	Model            string `gorm:"type:varchar(30)"`
+	ProcessNoise     *float64
+	MeasurementNoise *float64
+}
+
+func (t TrackingSettings) IsSet() bool {
+	return t.Model != "" || t.ProcessNoise != nil || t.MeasurementNoise != nil
 }
 
 func (c Cluster) SetID(id uint) {
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type FormationMethod string
+
+const (
+	ComponentsFormation  FormationMethod = "components"
+	CommunitiesFormation FormationMethod = "communities"
+)
+
+func (m FormationMethod) IsValid() bool {
+	return m == ComponentsFormation || m == CommunitiesFormation
+}
+
+type ClusterProposal struct {
+	Method   FormationMethod
+	Clusters []*ProposedCluster
+	// Unassigned stations have no usable ranging link and keep their cluster.
+	Unassigned []uint
+	From       time.Time
+	To         time.Time
+	Timestamp  time.Time
+}
+
+// ProposedCluster reuses the existing cluster sharing most of its stations,
+// ClusterID is nil when a new cluster would be created. Added lists the
+// stations whose assignment changes on accept.
+type ProposedCluster struct {
+	ClusterID  *uint
+	Name       string
+	StationIDs []uint
+	Added      []uint
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ConfigurationProfile is a reusable set of UWB radio parameters. Station
+// owned settings such as the mode, chip and antenna delay are not part of it.
+type ConfigurationProfile struct {
+	gorm.Model
+	Name        string `gorm:"size:100;unique;not null"`
+	Description string `gorm:"type:text"`
+
+	UWBChannel      uint8  `gorm:"not null;default:5"`
+	UWBPreambleCode uint8  `gorm:"not null;default:9"`
+	UWBPreambleLen  string `gorm:"type:varchar(20);not null;default:'128'"`
+	UWBDataRate     uint   `gorm:"not null;default:6800"`
+	UWBPRF          uint8  `gorm:"column:uwb_prf;not null;default:64"`
+	UWBSFD          UWBSFD `gorm:"column:uwb_sfd;type:varchar(15);not null;default:'standard'"`
+	UWBTxPower      *float64
+}
+
+func (p *ConfigurationProfile) ApplyDefaults() {
+	if p.UWBChannel == 0 {
+		p.UWBChannel = DefaultUWBChannel
+	}
+	if p.UWBPreambleCode == 0 {
+		p.UWBPreambleCode = DefaultUWBPreambleCode
+	}
+	if p.UWBPreambleLen == "" {
+		p.UWBPreambleLen = DefaultUWBPreambleLen
+	}
+	if p.UWBDataRate == 0 {
+		p.UWBDataRate = DefaultUWBDataRate
+	}
+	if p.UWBPRF == 0 {
+		p.UWBPRF = DefaultUWBPRF
+	}
+	if p.UWBSFD == "" {
+		p.UWBSFD = StandardSFD
+	}
+}
+
+func (p ConfigurationProfile) SetID(id uint) {
+	p.ID = id
+}
+
+func (p ConfigurationProfile) GetID() uint {
+	return p.ID
+}
+
+func (p ConfigurationProfile) TableName() string {
+	return "configuration_profiles"
+}
+
+// ConfigOverrides are station-level exceptions to the assigned profile. Only
+// the set fields take precedence.
+type ConfigOverrides struct {
+	UWBChannel      *uint8   `json:"uwb_channel,omitempty"`
+	UWBPreambleCode *uint8   `json:"uwb_preamble_code,omitempty"`
+	UWBPreambleLen  *string  `json:"uwb_preamble_len,omitempty"`
+	UWBDataRate     *uint    `json:"uwb_data_rate,omitempty"`
+	UWBPRF          *uint8   `json:"uwb_prf,omitempty"`
+	UWBSFD          *UWBSFD  `json:"uwb_sfd,omitempty"`
+	UWBTxPower      *float64 `json:"uwb_tx_power,omitempty"`
+}
+
+// ApplyProfile copies the radio parameters of the profile onto s.
+func (s *StationConfiguration) ApplyProfile(profile *ConfigurationProfile) {
+	s.UWBChannel = profile.UWBChannel
+	s.UWBPreambleCode = profile.UWBPreambleCode
+	s.UWBPreambleLen = profile.UWBPreambleLen
+	s.UWBDataRate = profile.UWBDataRate
+	s.UWBPRF = profile.UWBPRF
+	s.UWBSFD = profile.UWBSFD
+	s.UWBTxPower = profile.UWBTxPower
+}
+
+// ApplyOverrides copies the set overrides onto s and returns the names of
+// the overridden fields.
+func (s *StationConfiguration) ApplyOverrides(overrides ConfigOverrides) []string {
+	fields := make([]string, 0)
+
+	if overrides.UWBChannel != nil {
+		s.UWBChannel = *overrides.UWBChannel
+		fields = append(fields, "uwb_channel")
+	}
+	if overrides.UWBPreambleCode != nil {
+		s.UWBPreambleCode = *overrides.UWBPreambleCode
+		fields = append(fields, "uwb_preamble_code")
+	}
+	if overrides.UWBPreambleLen != nil {
+		s.UWBPreambleLen = *overrides.UWBPreambleLen
+		fields = append(fields, "uwb_preamble_len")
+	}
+	if overrides.UWBDataRate != nil {
+		s.UWBDataRate = *overrides.UWBDataRate
+		fields = append(fields, "uwb_data_rate")
+	}
+	if overrides.UWBPRF != nil {
+		s.UWBPRF = *overrides.UWBPRF
+		fields = append(fields, "uwb_prf")
+	}
+	if overrides.UWBSFD != nil {
+		s.UWBSFD = *overrides.UWBSFD
+		fields = append(fields, "uwb_sfd")
+	}
+	if overrides.UWBTxPower != nil {
+		power := *overrides.UWBTxPower
+		s.UWBTxPower = &power
+		fields = append(fields, "uwb_tx_power")
+	}
+
+	return fields
+}
+
+// ProfileFields are the configuration fields a profile provides.
+var ProfileFields = []string{"uwb_channel", "uwb_preamble_code", "uwb_preamble_len", "uwb_data_rate", "uwb_prf", "uwb_sfd", "uwb_tx_power"}
+
+type ConfigSource string
+
+const (
+	StationSource        ConfigSource = "station"
+	ProfileSource        ConfigSource = "profile"
+	ClusterProfileSource ConfigSource = "cluster_profile"
+	OverrideSource       ConfigSource = "override"
+)
+
+// EffectiveConfiguration is the configuration a station should run once its
+// profile and overrides are resolved. Sources names the layer every field
+// was taken from, Masked the profile fields an override replaces with a
+// different value.
+type EffectiveConfiguration struct {
+	StationID     uint
+	ProfileID     *uint
+	ProfileSource ConfigSource
+	Configuration *StationConfiguration
+	Sources       map[string]ConfigSource
+	Masked        []string
+}
+
+type ConfigApplyStatus string
+
+const (
+	ConfigApplied   ConfigApplyStatus = "applied"
+	ConfigUnchanged ConfigApplyStatus = "unchanged"
+	ConfigInvalid   ConfigApplyStatus = "invalid"
+	// ConfigSkipped marks stations that could not be applied at all, e.g.
+	// because they have no configuration yet. Effective is nil for them.
+	ConfigSkipped ConfigApplyStatus = "skipped"
+)
+
+type ConfigApplyResult struct {
+	StationID uint
+	Status    ConfigApplyStatus
+	Error     error
+	Effective *EffectiveConfiguration
+}
+
+// ConfigApplyReport counts the results by status. Masked counts the
+// stations whose overrides keep part of the profile from taking effect.
+type ConfigApplyReport struct {
+	ClusterID *uint
+	Applied   int
+	Unchanged int
+	Invalid   int
+	Skipped   int
+	Masked    int
+	Results   []*ConfigApplyResult
+}
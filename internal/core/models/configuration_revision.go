@@ -0,0 +1,130 @@
+package models
+
+import (
+	"reflect"
+	"time"
+)
+
+type RevisionSource string
+
+const (
+	BaselineRevision RevisionSource = "baseline"
+	CreateRevision   RevisionSource = "create"
+	UpdateRevision   RevisionSource = "update"
+	ProfileRevision  RevisionSource = "profile"
+	RollbackRevision RevisionSource = "rollback"
+	RolloutRevision  RevisionSource = "rollout"
+	PlanRevision     RevisionSource = "channel_plan"
+)
+
+// ConfigurationRevision is an immutable record of a station configuration
+// after a change. Versions are numbered per configuration starting at one.
+type ConfigurationRevision struct {
+	ID                     uint           `gorm:"primarykey"`
+	StationConfigurationID uint           `gorm:"not null;uniqueIndex:idx_configuration_revisions_version,priority:1"`
+	StationID              uint           `gorm:"not null;index"`
+	Version                uint           `gorm:"not null;uniqueIndex:idx_configuration_revisions_version,priority:2"`
+	Actor                  string         `gorm:"type:varchar(100);not null"`
+	Source                 RevisionSource `gorm:"type:varchar(20);not null"`
+	RestoredVersion        *uint
+	Snapshot               ConfigSnapshot `gorm:"serializer:json;type:jsonb"`
+	Changes                []ConfigChange `gorm:"serializer:json;type:jsonb"`
+	CreatedAt              time.Time      `gorm:"not null;index"`
+
+	// Previous seeds a baseline revision when the configuration has no
+	// history yet, so the state before the first tracked change can be
+	// restored.
+	Previous *ConfigSnapshot `gorm:"-"`
+}
+
+func (r ConfigurationRevision) TableName() string {
+	return "configuration_revisions"
+}
+
+// ConfigSnapshot holds every user-controlled field of a station
+// configuration.
+type ConfigSnapshot struct {
+	UWBMode         UWBMode         `json:"uwb_mode"`
+	UWBChip         UWBChip         `json:"uwb_chip"`
+	UWBChannel      uint8           `json:"uwb_channel"`
+	UWBPreambleCode uint8           `json:"uwb_preamble_code"`
+	UWBPreambleLen  string          `json:"uwb_preamble_len"`
+	UWBDataRate     uint            `json:"uwb_data_rate"`
+	UWBPRF          uint8           `json:"uwb_prf"`
+	UWBSFD          UWBSFD          `json:"uwb_sfd"`
+	UWBTxPower      *float64        `json:"uwb_tx_power"`
+	UWBAntennaDelay uint16          `json:"uwb_antenna_delay"`
+	ProfileID       *uint           `json:"profile_id"`
+	Overrides       ConfigOverrides `json:"overrides"`
+}
+
+// SnapshotFields are the columns covered by a snapshot.
+var SnapshotFields = []string{
+	"uwb_mode", "uwb_chip", "uwb_channel", "uwb_preamble_code", "uwb_preamble_len", "uwb_data_rate",
+	"uwb_prf", "uwb_sfd", "uwb_tx_power", "uwb_antenna_delay", "profile_id", "overrides",
+}
+
+type ConfigChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+func (s StationConfiguration) Snapshot() ConfigSnapshot {
+	return ConfigSnapshot{
+		UWBMode:         s.UWBMode,
+		UWBChip:         s.UWBChip,
+		UWBChannel:      s.UWBChannel,
+		UWBPreambleCode: s.UWBPreambleCode,
+		UWBPreambleLen:  s.UWBPreambleLen,
+		UWBDataRate:     s.UWBDataRate,
+		UWBPRF:          s.UWBPRF,
+		UWBSFD:          s.UWBSFD,
+		UWBTxPower:      s.UWBTxPower,
+		UWBAntennaDelay: s.UWBAntennaDelay,
+		ProfileID:       s.ProfileID,
+		Overrides:       s.Overrides,
+	}
+}
+
+// Restore copies the snapshot back onto s.
+func (s *StationConfiguration) Restore(snapshot ConfigSnapshot) {
+	s.UWBMode = snapshot.UWBMode
+	s.UWBChip = snapshot.UWBChip
+	s.UWBChannel = snapshot.UWBChannel
+	s.UWBPreambleCode = snapshot.UWBPreambleCode
+	s.UWBPreambleLen = snapshot.UWBPreambleLen
+	s.UWBDataRate = snapshot.UWBDataRate
+	s.UWBPRF = snapshot.UWBPRF
+	s.UWBSFD = snapshot.UWBSFD
+	s.UWBTxPower = snapshot.UWBTxPower
+	s.UWBAntennaDelay = snapshot.UWBAntennaDelay
+	s.ProfileID = snapshot.ProfileID
+	s.Overrides = snapshot.Overrides
+}
+
+// Diff lists the fields that changed from s to next. Pointer fields are
+// compared by value.
+func (s ConfigSnapshot) Diff(next ConfigSnapshot) []ConfigChange {
+	changes := make([]ConfigChange, 0)
+	compare := func(field string, from interface{}, to interface{}) {
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, ConfigChange{Field: field, From: from, To: to})
+		}
+	}
+
+	compare("uwb_mode", s.UWBMode, next.UWBMode)
+	compare("uwb_chip", s.UWBChip, next.UWBChip)
+	compare("uwb_channel", s.UWBChannel, next.UWBChannel)
+	compare("uwb_preamble_code", s.UWBPreambleCode, next.UWBPreambleCode)
+	compare("uwb_preamble_len", s.UWBPreambleLen, next.UWBPreambleLen)
+	compare("uwb_data_rate", s.UWBDataRate, next.UWBDataRate)
+	compare("uwb_prf", s.UWBPRF, next.UWBPRF)
+	compare("uwb_sfd", s.UWBSFD, next.UWBSFD)
+	compare("uwb_tx_power", s.UWBTxPower, next.UWBTxPower)
+	compare("uwb_antenna_delay", s.UWBAntennaDelay, next.UWBAntennaDelay)
+	compare("profile_id", s.ProfileID, next.ProfileID)
+	compare("overrides", s.Overrides, next.Overrides)
+
+	return changes
+}
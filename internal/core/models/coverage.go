@@ -0,0 +1,42 @@
+package models
+
+type CoverageAnchor struct {
+	StationID    *uint
+	X            float64
+	Y            float64
+	Z            float64
+	Hypothetical bool
+}
+
+// CoverageRequest describes a planning run. Coordinates use the cluster
+// units; unset area bounds default to the anchor extent plus a margin.
+type CoverageRequest struct {
+	Anchors        []CoverageAnchor
+	ReplaceAnchors bool
+	MinX           *float64
+	MinY           *float64
+	MaxX           *float64
+	MaxY           *float64
+	Resolution     float64
+	Height         float64
+	MaxRange       *float64
+	Dimension      int
+}
+
+type CoverageMap struct {
+	ClusterID  uint
+	Units      string
+	MinX       float64
+	MinY       float64
+	MaxX       float64
+	MaxY       float64
+	Resolution float64
+	Height     float64
+	MaxRange   float64
+	Dimension  int
+	Columns    int
+	Rows       int
+	HDOP       [][]float64
+	Visible    [][]int
+	Anchors    []CoverageAnchor
+}
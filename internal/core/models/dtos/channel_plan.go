@@ -0,0 +1,47 @@
+package dtos
+
+import "time"
+
+type ChannelPlanRequestDto struct {
+	Window   string `json:"window"`
+	Channels []uint `json:"channels"`
+}
+
+type ChannelPlanApplyDto struct {
+	Clusters []uint `json:"clusters"`
+}
+
+type ChannelPlanDto struct {
+	Clusters        []*ClusterChannelPlanDto `json:"clusters"`
+	Adjacencies     []*ClusterAdjacencyDto   `json:"adjacencies"`
+	ConflictsBefore int                      `json:"conflicts_before"`
+	ConflictsAfter  int                      `json:"conflicts_after"`
+	Warnings        []string                 `json:"warnings"`
+	From            time.Time                `json:"from"`
+	To              time.Time                `json:"to"`
+	Timestamp       time.Time                `json:"timestamp"`
+}
+
+type ClusterChannelPlanDto struct {
+	ClusterID     uint                  `json:"cluster_id"`
+	Name          string                `json:"name"`
+	Stations      int                   `json:"stations"`
+	Current       *ChannelAssignmentDto `json:"current"`
+	Proposed      *ChannelAssignmentDto `json:"proposed"`
+	Changed       bool                  `json:"changed"`
+	Neighbours    []uint                `json:"neighbours"`
+	Conflicts     []uint                `json:"conflicts"`
+	SharedChannel []uint                `json:"shared_channel"`
+}
+
+type ChannelAssignmentDto struct {
+	Channel      uint8 `json:"channel"`
+	PreambleCode uint8 `json:"preamble_code"`
+	PRF          uint8 `json:"prf"`
+}
+
+type ClusterAdjacencyDto struct {
+	ClusterID   uint     `json:"cluster_id"`
+	NeighbourID uint     `json:"neighbour_id"`
+	Reasons     []string `json:"reasons"`
+}
@@ -6,12 +6,31 @@ import (
 )
 
 type ClusterDto struct {
-	gorm.Model  `json:"-"`
-	ID          uint            `json:"id"`
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	Stations    []*StationDto   `json:"stations,omitempty"`
-	CreatedAt   *time.Time      `json:"created_at,omitempty"`
-	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
-	DeletedAt   *gorm.DeletedAt `json:"deleted_at,omitempty"`
+	gorm.Model   `json:"-"`
+	ID           uint                `json:"id"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description"`
+	Stations     []*StationDto       `json:"stations,omitempty"`
+	CreatedAt    *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time          `json:"updated_at,omitempty"`
+	DeletedAt    *gorm.DeletedAt     `json:"deleted_at,omitempty"`
+	Tracking     *ClusterTrackingDto `json:"tracking,omitempty"`
+	Frame        *ClusterFrameDto    `json:"frame,omitempty"`
+	ProfileID    *uint               `json:"profile_id,omitempty"`
+	NeighbourIDs []uint              `json:"neighbour_ids,omitempty"`
+}
+
+type ClusterFrameDto struct {
+	OriginLatitude  *float64 `json:"origin_latitude,omitempty"`
+	OriginLongitude *float64 `json:"origin_longitude,omitempty"`
+	OriginAltitude  *float64 `json:"origin_altitude,omitempty"`
+	Rotation        *float64 `json:"rotation,omitempty"`
+	Units           string   `json:"units,omitempty"`
+	Level           *int     `json:"level,omitempty"`
+}
+
+type ClusterTrackingDto struct {
+	Model            string   `json:"model,omitempty"`
+	ProcessNoise     *float64 `json:"process_noise,omitempty"`
+	MeasurementNoise *float64 `json:"measurement_noise,omitempty"`
 }
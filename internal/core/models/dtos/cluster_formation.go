@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+type ClusterFormationRequestDto struct {
+	Method string `json:"method"`
+	Window string `json:"window"`
+}
+
+type ClusterFormationAcceptDto struct {
+	Clusters []int `json:"clusters"`
+}
+
+type ClusterProposalDto struct {
+	Method     string                `json:"method"`
+	Clusters   []*ProposedClusterDto `json:"clusters"`
+	Unassigned []uint                `json:"unassigned"`
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	Timestamp  time.Time             `json:"timestamp"`
+}
+
+type ProposedClusterDto struct {
+	Index      int    `json:"index"`
+	ClusterID  *uint  `json:"cluster_id"`
+	Name       string `json:"name"`
+	StationIDs []uint `json:"station_ids"`
+	Added      []uint `json:"added"`
+}
@@ -0,0 +1,66 @@
+package dtos
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type ConfigurationProfileDto struct {
+	gorm.Model      `json:"-"`
+	ID              uint            `json:"id"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	UWBChannel      uint8           `json:"uwb_channel"`
+	UWBPreambleCode uint8           `json:"uwb_preamble_code"`
+	UWBPreambleLen  string          `json:"uwb_preamble_len"`
+	UWBDataRate     uint            `json:"uwb_data_rate"`
+	UWBPRF          uint8           `json:"uwb_prf"`
+	UWBSFD          string          `json:"uwb_sfd"`
+	UWBTxPower      *float64        `json:"uwb_tx_power"`
+	CreatedAt       *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time      `json:"updated_at,omitempty"`
+	DeletedAt       *gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
+
+type ConfigOverridesDto struct {
+	UWBChannel      *uint8   `json:"uwb_channel,omitempty"`
+	UWBPreambleCode *uint8   `json:"uwb_preamble_code,omitempty"`
+	UWBPreambleLen  *string  `json:"uwb_preamble_len,omitempty"`
+	UWBDataRate     *uint    `json:"uwb_data_rate,omitempty"`
+	UWBPRF          *uint8   `json:"uwb_prf,omitempty"`
+	UWBSFD          *string  `json:"uwb_sfd,omitempty"`
+	UWBTxPower      *float64 `json:"uwb_tx_power,omitempty"`
+}
+
+type EffectiveConfigurationDto struct {
+	StationID     uint                     `json:"station_id"`
+	ProfileID     *uint                    `json:"profile_id"`
+	ProfileSource string                   `json:"profile_source"`
+	Configuration *StationConfigurationDto `json:"configuration"`
+	Sources       map[string]string        `json:"sources"`
+	Masked        []string                 `json:"masked,omitempty"`
+}
+
+type ValidationErrorDto struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ConfigApplyResultDto struct {
+	StationID     uint                     `json:"station_id"`
+	Status        string                   `json:"status"`
+	Errors        []ValidationErrorDto     `json:"errors,omitempty"`
+	ProfileID     *uint                    `json:"profile_id,omitempty"`
+	Configuration *StationConfigurationDto `json:"configuration,omitempty"`
+	Masked        []string                 `json:"masked,omitempty"`
+}
+
+type ConfigApplyReportDto struct {
+	ClusterID *uint                   `json:"cluster_id,omitempty"`
+	Applied   int                     `json:"applied"`
+	Unchanged int                     `json:"unchanged"`
+	Invalid   int                     `json:"invalid"`
+	Skipped   int                     `json:"skipped"`
+	Masked    int                     `json:"masked"`
+	Results   []*ConfigApplyResultDto `json:"results"`
+}
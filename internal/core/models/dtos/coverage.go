@@ -0,0 +1,40 @@
+package dtos
+
+type CoverageRequestDto struct {
+	Anchors        []*CoverageAnchorDto `json:"anchors"`
+	ReplaceAnchors bool                 `json:"replace_anchors"`
+	MinX           *float64             `json:"min_x"`
+	MinY           *float64             `json:"min_y"`
+	MaxX           *float64             `json:"max_x"`
+	MaxY           *float64             `json:"max_y"`
+	Resolution     float64              `json:"resolution"`
+	Height         float64              `json:"height"`
+	MaxRange       *float64             `json:"max_range"`
+	Dimension      int                  `json:"dimension"`
+}
+
+type CoverageAnchorDto struct {
+	StationID    *uint   `json:"station_id,omitempty"`
+	X            float64 `json:"x"`
+	Y            float64 `json:"y"`
+	Z            float64 `json:"z"`
+	Hypothetical bool    `json:"hypothetical"`
+}
+
+type CoverageMapDto struct {
+	ClusterID  uint                 `json:"cluster_id"`
+	Units      string               `json:"units"`
+	MinX       float64              `json:"min_x"`
+	MinY       float64              `json:"min_y"`
+	MaxX       float64              `json:"max_x"`
+	MaxY       float64              `json:"max_y"`
+	Resolution float64              `json:"resolution"`
+	Height     float64              `json:"height"`
+	MaxRange   float64              `json:"max_range"`
+	Dimension  int                  `json:"dimension"`
+	Columns    int                  `json:"columns"`
+	Rows       int                  `json:"rows"`
+	HDOP       [][]float64          `json:"hdop"`
+	Visible    [][]int              `json:"visible"`
+	Anchors    []*CoverageAnchorDto `json:"anchors"`
+}
@@ -0,0 +1,40 @@
+package dtos
+
+import "time"
+
+type PositionDto struct {
+	StationID   uint            `json:"station_id"`
+	ClusterID   *uint           `json:"cluster_id,omitempty"`
+	X           float64         `json:"x"`
+	Y           float64         `json:"y"`
+	Z           float64         `json:"z"`
+	Dimension   int             `json:"dimension"`
+	Residual    float64         `json:"residual"`
+	Quality     float64         `json:"quality"`
+	AnchorCount int             `json:"anchor_count"`
+	AnchorIDs   []uint          `json:"anchor_ids,omitempty"`
+	GDOP        float64         `json:"gdop"`
+	HDOP        float64         `json:"hdop"`
+	VDOP        float64         `json:"vdop"`
+	FixType     string          `json:"fix_type"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Mode        string          `json:"mode"`
+	Velocity    *VectorDto      `json:"velocity,omitempty"`
+	Covariance  [][]float64     `json:"covariance,omitempty"`
+	Frame       string          `json:"frame"`
+	Units       string          `json:"units"`
+	Level       *int            `json:"level,omitempty"`
+	WGS84       *GeoPositionDto `json:"wgs84,omitempty"`
+}
+
+type GeoPositionDto struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+}
+
+type VectorDto struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
@@ -6,13 +6,67 @@ import (
 )
 
 type RangingDto struct {
-	ID            uint            `json:"id"`
-	SourceID      *uint           `json:"source_id,omitempty"`
-	Source        *StationDto     `json:"source,omitempty"`
-	DestinationID *uint           `json:"destination_id,omitempty"`
-	Destination   *StationDto     `json:"destination,omitempty"`
-	RawDistance   float64         `json:"raw_distance" validate:"required"`
-	CreatedAt     *time.Time      `json:"created_at,omitempty"`
-	UpdatedAt     *time.Time      `json:"updated_at,omitempty"`
-	DeletedAt     *gorm.DeletedAt `json:"deleted_at,omitempty"`
+	ID               uint            `json:"id"`
+	SourceID         *uint           `json:"source_id,omitempty"`
+	Source           *StationDto     `json:"source,omitempty"`
+	DestinationID    *uint           `json:"destination_id,omitempty"`
+	Destination      *StationDto     `json:"destination,omitempty"`
+	RawDistance      float64         `json:"raw_distance" validate:"required"`
+	ScaledDistance   *float64        `json:"scaled_distance,omitempty"`
+	FilteredDistance *float64        `json:"filtered_distance,omitempty"`
+	CreatedAt        *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt        *time.Time      `json:"updated_at,omitempty"`
+	DeletedAt        *gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
+
+type RangingSampleDto struct {
+	SourceID       uint      `json:"source_id"`
+	DestinationID  uint      `json:"destination_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	RawDistance    float64   `json:"raw_distance"`
+	ScaledDistance *float64  `json:"scaled_distance,omitempty"`
+	RSSI           *float64  `json:"rssi,omitempty"`
+	FirstPathPower *float64  `json:"first_path_power,omitempty"`
+	SignalQuality  *float64  `json:"signal_quality,omitempty"`
+	Rejected       bool      `json:"rejected,omitempty"`
+	RejectStage    string    `json:"reject_stage,omitempty"`
+	RejectReason   string    `json:"reject_reason,omitempty"`
+	SampleCount    int       `json:"sample_count"`
+}
+
+type RangingPairStatsDto struct {
+	SourceID      uint      `json:"source_id"`
+	DestinationID uint      `json:"destination_id"`
+	Count         int64     `json:"count"`
+	Mean          float64   `json:"mean"`
+	Median        float64   `json:"median"`
+	StdDev        float64   `json:"stddev"`
+	Min           float64   `json:"min"`
+	Max           float64   `json:"max"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+	MaxGap        float64   `json:"max_gap"`
+	UpdateRate    float64   `json:"update_rate"`
+	ExpectedRate  float64   `json:"expected_rate"`
+	LossRatio     float64   `json:"loss_ratio"`
+}
+
+type RangingFilterStatisticsDto struct {
+	Stages          []string                          `json:"stages"`
+	Processed       uint64                            `json:"processed"`
+	Accepted        uint64                            `json:"accepted"`
+	Rejected        uint64                            `json:"rejected"`
+	RejectedByStage map[string]uint64                 `json:"rejected_by_stage"`
+	Pairs           []*RangingPairFilterStatisticsDto `json:"pairs,omitempty"`
+	RecentSamples   []*RangingSampleDto               `json:"recent_samples,omitempty"`
+}
+
+type RangingPairFilterStatisticsDto struct {
+	SourceID        uint              `json:"source_id"`
+	DestinationID   uint              `json:"destination_id"`
+	Processed       uint64            `json:"processed"`
+	Rejected        uint64            `json:"rejected"`
+	RejectedByStage map[string]uint64 `json:"rejected_by_stage"`
+	LastRejectedAt  *time.Time        `json:"last_rejected_at,omitempty"`
+	LastReason      string            `json:"last_reason,omitempty"`
 }
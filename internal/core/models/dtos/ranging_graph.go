@@ -0,0 +1,30 @@
+package dtos
+
+import "time"
+
+type RangingGraphDto struct {
+	ClusterID uint                   `json:"cluster_id"`
+	From      time.Time              `json:"from"`
+	To        time.Time              `json:"to"`
+	Nodes     []*RangingGraphNodeDto `json:"nodes"`
+	Edges     []*RangingGraphEdgeDto `json:"edges"`
+}
+
+type RangingGraphNodeDto struct {
+	StationID  uint                `json:"station_id"`
+	Name       string              `json:"name"`
+	MacAddress string              `json:"mac_address"`
+	Mode       string              `json:"mode"`
+	Position   *StationPositionDto `json:"position,omitempty"`
+	Degree     int                 `json:"degree"`
+}
+
+type RangingGraphEdgeDto struct {
+	SourceID      uint      `json:"source_id"`
+	DestinationID uint      `json:"destination_id"`
+	Distance      float64   `json:"distance"`
+	LastSeen      time.Time `json:"last_seen"`
+	SampleCount   int64     `json:"sample_count"`
+	SampleRate    float64   `json:"sample_rate"`
+	Stale         bool      `json:"stale"`
+}
@@ -0,0 +1,57 @@
+package dtos
+
+import "time"
+
+type RolloutRequestDto struct {
+	ProfileID        uint    `json:"profile_id"`
+	ClusterID        uint    `json:"cluster_id"`
+	Waves            []uint  `json:"waves"`
+	FailureAction    string  `json:"failure_action"`
+	SettleTime       string  `json:"settle_time"`
+	MaxRateDrop      float64 `json:"max_rate_drop"`
+	MaxErrorIncrease float64 `json:"max_error_increase"`
+}
+
+type RolloutDto struct {
+	ID               uint              `json:"id"`
+	ProfileID        uint              `json:"profile_id"`
+	ClusterID        uint              `json:"cluster_id"`
+	Status           string            `json:"status"`
+	FailureAction    string            `json:"failure_action"`
+	SettleTime       string            `json:"settle_time"`
+	MaxRateDrop      float64           `json:"max_rate_drop"`
+	MaxErrorIncrease float64           `json:"max_error_increase"`
+	CurrentWave      int               `json:"current_wave"`
+	Waves            []*RolloutWaveDto `json:"waves"`
+	NextCheckAt      *time.Time        `json:"next_check_at,omitempty"`
+	Actor            string            `json:"actor"`
+	Message          string            `json:"message,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+type RolloutWaveDto struct {
+	Percent    uint              `json:"percent"`
+	StationIDs []uint            `json:"station_ids"`
+	Status     string            `json:"status"`
+	Baseline   *RolloutHealthDto `json:"baseline,omitempty"`
+	Health     *RolloutHealthDto `json:"health,omitempty"`
+	AppliedAt  *time.Time        `json:"applied_at,omitempty"`
+	CheckedAt  *time.Time        `json:"checked_at,omitempty"`
+	Restore    map[uint]uint     `json:"restore,omitempty"`
+	Invalid    []uint            `json:"invalid,omitempty"`
+	Masked     []uint            `json:"masked,omitempty"`
+}
+
+type RolloutHealthDto struct {
+	Stations   int       `json:"stations"`
+	Pairs      int       `json:"pairs"`
+	Samples    int64     `json:"samples"`
+	Rejected   int64     `json:"rejected"`
+	SampleRate float64   `json:"sample_rate"`
+	ErrorRate  float64   `json:"error_rate"`
+	Degraded   bool      `json:"degraded"`
+	Reasons    []string  `json:"reasons,omitempty"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+}
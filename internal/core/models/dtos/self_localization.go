@@ -0,0 +1,36 @@
+package dtos
+
+import "time"
+
+type SelfLocalizeRequestDto struct {
+	Pinned    []uint `json:"pinned"`
+	Dimension int    `json:"dimension"`
+	Window    string `json:"window"`
+}
+
+type SelfLocalizeAcceptDto struct {
+	StationIDs []uint `json:"station_ids"`
+}
+
+type SelfLocalizationDto struct {
+	ClusterID uint                      `json:"cluster_id"`
+	Dimension int                       `json:"dimension"`
+	Residual  float64                   `json:"residual"`
+	Mirrored  bool                      `json:"mirrored"`
+	Ambiguous bool                      `json:"ambiguous"`
+	Anchors   []*SelfLocalizedAnchorDto `json:"anchors"`
+	Warnings  []string                  `json:"warnings,omitempty"`
+	From      time.Time                 `json:"from"`
+	To        time.Time                 `json:"to"`
+	Timestamp time.Time                 `json:"timestamp"`
+}
+
+type SelfLocalizedAnchorDto struct {
+	StationID  uint    `json:"station_id"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Z          float64 `json:"z"`
+	Error      float64 `json:"error"`
+	Pinned     bool    `json:"pinned"`
+	Neighbours int     `json:"neighbours"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+import (
+	"time"
+)
+
+type StationShadowDto struct {
+	StationID       uint                  `json:"station_id"`
+	ConfigurationID uint                  `json:"configuration_id"`
+	Desired         UWBConfigurationDto   `json:"desired"`
+	Reported        *UWBConfigurationDto  `json:"reported"`
+	ReportedAt      *time.Time            `json:"reported_at"`
+	Delta           []ShadowDifferenceDto `json:"delta"`
+	Drifted         bool                  `json:"drifted"`
+	Stale           bool                  `json:"stale"`
+	Sync            *ConfigSyncDto        `json:"sync,omitempty"`
+	Timestamp       time.Time             `json:"timestamp"`
+}
+
+type ShadowDifferenceDto struct {
+	Field    string      `json:"field"`
+	Desired  interface{} `json:"desired"`
+	Reported interface{} `json:"reported"`
+}
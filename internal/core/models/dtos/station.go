@@ -7,13 +7,36 @@ import (
 
 type StationDto struct {
 	gorm.Model `json:"-"`
-	ID         uint            `json:"id"`
-	MacAddress string          `json:"mac_address" gorm:"unique;not null"`
-	Name       string          `json:"name" gorm:"not null"`
-	ClusterID  *uint           `json:"cluster_id,omitempty"`
-	Cluster    *ClusterDto     `json:"cluster,omitempty"`
-	CreatedAt  *time.Time      `json:"created_at,omitempty"`
-	UpdatedAt  *time.Time      `json:"updated_at,omitempty"`
-	DeletedAt  *gorm.DeletedAt `json:"deleted_at,omitempty"`
-	LastSeen   *time.Time      `json:"last_seen,omitempty"`
+	ID         uint                `json:"id"`
+	MacAddress string              `json:"mac_address" gorm:"unique;not null"`
+	Name       string              `json:"name" gorm:"not null"`
+	ClusterID  *uint               `json:"cluster_id,omitempty"`
+	Cluster    *ClusterDto         `json:"cluster,omitempty"`
+	CreatedAt  *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty"`
+	DeletedAt  *gorm.DeletedAt     `json:"deleted_at,omitempty"`
+	LastSeen   *time.Time          `json:"last_seen,omitempty"`
+	StartedAt  *time.Time          `json:"started_at,omitempty"`
+	Uptime     *int64              `json:"uptime_seconds,omitempty"`
+	Status     string              `json:"status"`
+	Position   *StationPositionDto `json:"position,omitempty"`
+
+	ReportedCluster *string  `json:"reported_cluster,omitempty"`
+	ReportedPeers   []string `json:"reported_peers,omitempty"`
+	ClusterMismatch bool     `json:"cluster_mismatch"`
+}
+
+type StationPositionDto struct {
+	X      *float64 `json:"x"`
+	Y      *float64 `json:"y"`
+	Z      *float64 `json:"z,omitempty"`
+	Frame  string   `json:"frame,omitempty"`
+	Source string   `json:"source,omitempty"`
+}
+
+type PresenceEventDto struct {
+	StationID uint      `json:"station_id"`
+	ClusterID uint      `json:"cluster_id,omitempty"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
 }
@@ -6,12 +6,74 @@ import (
 )
 
 type StationConfigurationDto struct {
-	gorm.Model `json:"-"`
-	ID         uint            `json:"id"`
-	StationID  uint            `json:"station_id"`
-	UWBMode    string          `json:"uwb_mode"`
-	UWBChannel uint8           `json:"uwb_channel"`
-	CreatedAt  *time.Time      `json:"created_at,omitempty"`
-	UpdatedAt  *time.Time      `json:"updated_at,omitempty"`
-	DeletedAt  *gorm.DeletedAt `json:"deleted_at,omitempty"`
+	gorm.Model      `json:"-"`
+	ID              uint                `json:"id"`
+	StationID       uint                `json:"station_id"`
+	UWBMode         string              `json:"uwb_mode"`
+	UWBChannel      uint8               `json:"uwb_channel"`
+	UWBPreambleCode uint8               `json:"uwb_preamble_code"`
+	UWBPreambleLen  string              `json:"uwb_preamble_len"`
+	UWBChip         string              `json:"uwb_chip"`
+	UWBDataRate     uint                `json:"uwb_data_rate"`
+	UWBPRF          uint8               `json:"uwb_prf"`
+	UWBSFD          string              `json:"uwb_sfd"`
+	UWBTxPower      *float64            `json:"uwb_tx_power"`
+	UWBAntennaDelay uint16              `json:"uwb_antenna_delay"`
+	ProfileID       *uint               `json:"profile_id"`
+	Overrides       *ConfigOverridesDto `json:"overrides,omitempty"`
+	CreatedAt       *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time          `json:"updated_at,omitempty"`
+	DeletedAt       *gorm.DeletedAt     `json:"deleted_at,omitempty"`
+	Sync            *ConfigSyncDto      `json:"sync,omitempty"`
+}
+
+type ConfigSyncDto struct {
+	ConfigurationID uint       `json:"configuration_id"`
+	StationID       uint       `json:"station_id"`
+	Status          string     `json:"status"`
+	Revision        uint       `json:"revision"`
+	Attempts        uint       `json:"attempts"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// UWBConfigurationDto is the UWB part of the configuration as exchanged with
+// devices.
+type UWBConfigurationDto struct {
+	Mode           string   `json:"mode"`
+	Channel        uint8    `json:"channel"`
+	PreambleCode   uint8    `json:"preamble_code"`
+	PreambleLength string   `json:"preamble_length"`
+	DataRate       uint     `json:"data_rate"`
+	PRF            uint8    `json:"prf"`
+	SFD            string   `json:"sfd"`
+	TxPower        *float64 `json:"tx_power,omitempty"`
+	AntennaDelay   uint16   `json:"antenna_delay"`
+}
+
+type ConfigurationMessageDto struct {
+	ConfigurationID uint                `json:"configuration_id"`
+	Revision        uint                `json:"revision"`
+	UWB             UWBConfigurationDto `json:"uwb"`
+	Timestamp       time.Time           `json:"timestamp"`
+}
+
+type ConfigurationRevisionDto struct {
+	ID                     uint                     `json:"id"`
+	StationConfigurationID uint                     `json:"station_configuration_id"`
+	StationID              uint                     `json:"station_id"`
+	Version                uint                     `json:"version"`
+	Actor                  string                   `json:"actor"`
+	Source                 string                   `json:"source"`
+	RestoredVersion        *uint                    `json:"restored_version,omitempty"`
+	Configuration          *StationConfigurationDto `json:"configuration"`
+	Changes                []ConfigChangeDto        `json:"changes"`
+	CreatedAt              time.Time                `json:"created_at"`
+}
+
+type ConfigChangeDto struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
 }
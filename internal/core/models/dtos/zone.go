@@ -0,0 +1,42 @@
+package dtos
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type ZoneDto struct {
+	ID          uint            `json:"id"`
+	ClusterID   *uint           `json:"cluster_id"`
+	Cluster     *ClusterDto     `json:"cluster,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Type        string          `json:"type"`
+	Polygon     []*ZonePointDto `json:"polygon"`
+	MinZ        *float64        `json:"min_z,omitempty"`
+	MaxZ        *float64        `json:"max_z,omitempty"`
+	DwellLimit  *int            `json:"dwell_limit,omitempty"`
+	CreatedAt   *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
+	DeletedAt   *gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
+
+type ZonePointDto struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type ZoneEventDto struct {
+	Type         string    `json:"type"`
+	ClusterID    uint      `json:"cluster_id"`
+	ZoneID       uint      `json:"zone_id"`
+	ZoneName     string    `json:"zone_name"`
+	ZoneType     string    `json:"zone_type"`
+	StationID    uint      `json:"station_id"`
+	X            float64   `json:"x"`
+	Y            float64   `json:"y"`
+	Z            float64   `json:"z"`
+	EnteredAt    time.Time `json:"entered_at"`
+	DwellSeconds float64   `json:"dwell_seconds"`
+	Timestamp    time.Time `json:"timestamp"`
+}
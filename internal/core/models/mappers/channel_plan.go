@@ -0,0 +1,56 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func FromChannelPlan(plan *models.ChannelPlan) *dtos.ChannelPlanDto {
+	response := &dtos.ChannelPlanDto{
+		Clusters:        make([]*dtos.ClusterChannelPlanDto, len(plan.Clusters)),
+		Adjacencies:     make([]*dtos.ClusterAdjacencyDto, len(plan.Adjacencies)),
+		ConflictsBefore: plan.ConflictsBefore,
+		ConflictsAfter:  plan.ConflictsAfter,
+		Warnings:        plan.Warnings,
+		From:            plan.From,
+		To:              plan.To,
+		Timestamp:       plan.Timestamp,
+	}
+
+	for i, cluster := range plan.Clusters {
+		response.Clusters[i] = &dtos.ClusterChannelPlanDto{
+			ClusterID:     cluster.ClusterID,
+			Name:          cluster.Name,
+			Stations:      cluster.Stations,
+			Current:       fromChannelAssignment(cluster.Current),
+			Proposed:      fromChannelAssignment(cluster.Proposed),
+			Changed:       cluster.Changed,
+			Neighbours:    cluster.Neighbours,
+			Conflicts:     cluster.Conflicts,
+			SharedChannel: cluster.SharedChannel,
+		}
+	}
+
+	for i, adjacency := range plan.Adjacencies {
+		reasons := make([]string, len(adjacency.Reasons))
+		for j, reason := range adjacency.Reasons {
+			reasons[j] = string(reason)
+		}
+
+		response.Adjacencies[i] = &dtos.ClusterAdjacencyDto{
+			ClusterID:   adjacency.ClusterID,
+			NeighbourID: adjacency.NeighbourID,
+			Reasons:     reasons,
+		}
+	}
+
+	return response
+}
+
+func fromChannelAssignment(assignment models.ChannelAssignment) *dtos.ChannelAssignmentDto {
+	return &dtos.ChannelAssignmentDto{
+		Channel:      assignment.Channel,
+		PreambleCode: assignment.PreambleCode,
+		PRF:          assignment.PRF,
+	}
+}
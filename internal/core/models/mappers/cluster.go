@@ -10,9 +10,30 @@ func FromCluster(cluster *models.Cluster, includeParam *string) *dtos.ClusterDto
 	includes := dto.ParseIncludes(includeParam)
 
 	response := &dtos.ClusterDto{
-		ID:          cluster.ID,
-		Name:        cluster.Name,
-		Description: cluster.Description,
+		ID:           cluster.ID,
+		Name:         cluster.Name,
+		Description:  cluster.Description,
+		ProfileID:    cluster.ProfileID,
+		NeighbourIDs: cluster.NeighbourIDs,
+	}
+
+	if cluster.Tracking.IsSet() {
+		response.Tracking = &dtos.ClusterTrackingDto{
+			Model:            cluster.Tracking.Model,
+			ProcessNoise:     cluster.Tracking.ProcessNoise,
+			MeasurementNoise: cluster.Tracking.MeasurementNoise,
+		}
+	}
+
+	if cluster.Frame.IsSet() {
+		response.Frame = &dtos.ClusterFrameDto{
+			OriginLatitude:  cluster.Frame.OriginLatitude,
+			OriginLongitude: cluster.Frame.OriginLongitude,
+			OriginAltitude:  cluster.Frame.OriginAltitude,
+			Rotation:        cluster.Frame.Rotation,
+			Units:           cluster.Frame.Units,
+			Level:           cluster.Frame.Level,
+		}
 	}
 
 	if includes["meta"] {
@@ -25,10 +46,33 @@ func FromCluster(cluster *models.Cluster, includeParam *string) *dtos.ClusterDto
 }
 
 func ToCluster(dto *dtos.ClusterDto) *models.Cluster {
-	return &models.Cluster{
-		Name:        dto.Name,
-		Description: dto.Description,
+	cluster := &models.Cluster{
+		Name:         dto.Name,
+		Description:  dto.Description,
+		ProfileID:    dto.ProfileID,
+		NeighbourIDs: dto.NeighbourIDs,
 	}
+
+	if dto.Tracking != nil {
+		cluster.Tracking = models.TrackingSettings{
+			Model:            dto.Tracking.Model,
+			ProcessNoise:     dto.Tracking.ProcessNoise,
+			MeasurementNoise: dto.Tracking.MeasurementNoise,
+		}
+	}
+
+	if dto.Frame != nil {
+		cluster.Frame = models.ClusterFrame{
+			OriginLatitude:  dto.Frame.OriginLatitude,
+			OriginLongitude: dto.Frame.OriginLongitude,
+			OriginAltitude:  dto.Frame.OriginAltitude,
+			Rotation:        dto.Frame.Rotation,
+			Units:           dto.Frame.Units,
+			Level:           dto.Frame.Level,
+		}
+	}
+
+	return cluster
 }
 
 func FromClusterList(clusters []*models.Cluster, includeParam *string) []*dtos.ClusterDto {
@@ -0,0 +1,29 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func FromClusterProposal(proposal *models.ClusterProposal) *dtos.ClusterProposalDto {
+	response := &dtos.ClusterProposalDto{
+		Method:     string(proposal.Method),
+		Clusters:   make([]*dtos.ProposedClusterDto, 0, len(proposal.Clusters)),
+		Unassigned: proposal.Unassigned,
+		From:       proposal.From,
+		To:         proposal.To,
+		Timestamp:  proposal.Timestamp,
+	}
+
+	for i, cluster := range proposal.Clusters {
+		response.Clusters = append(response.Clusters, &dtos.ProposedClusterDto{
+			Index:      i,
+			ClusterID:  cluster.ClusterID,
+			Name:       cluster.Name,
+			StationIDs: cluster.StationIDs,
+			Added:      cluster.Added,
+		})
+	}
+
+	return response
+}
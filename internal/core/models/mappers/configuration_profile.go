@@ -0,0 +1,111 @@
+package mappers
+
+import (
+	"errors"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/core/validation"
+	"gps-no-server/internal/infrastructure/http/dto"
+)
+
+func FromConfigurationProfile(profile *models.ConfigurationProfile, includeParam *string) *dtos.ConfigurationProfileDto {
+	includes := dto.ParseIncludes(includeParam)
+
+	response := &dtos.ConfigurationProfileDto{
+		ID:              profile.ID,
+		Name:            profile.Name,
+		Description:     profile.Description,
+		UWBChannel:      profile.UWBChannel,
+		UWBPreambleCode: profile.UWBPreambleCode,
+		UWBPreambleLen:  profile.UWBPreambleLen,
+		UWBDataRate:     profile.UWBDataRate,
+		UWBPRF:          profile.UWBPRF,
+		UWBSFD:          string(profile.UWBSFD),
+		UWBTxPower:      profile.UWBTxPower,
+	}
+
+	if includes["meta"] {
+		response.CreatedAt = &profile.CreatedAt
+		response.UpdatedAt = &profile.UpdatedAt
+		response.DeletedAt = &profile.DeletedAt
+	}
+
+	return response
+}
+
+func ToConfigurationProfile(dto *dtos.ConfigurationProfileDto) *models.ConfigurationProfile {
+	return &models.ConfigurationProfile{
+		Name:            dto.Name,
+		Description:     dto.Description,
+		UWBChannel:      dto.UWBChannel,
+		UWBPreambleCode: dto.UWBPreambleCode,
+		UWBPreambleLen:  dto.UWBPreambleLen,
+		UWBDataRate:     dto.UWBDataRate,
+		UWBPRF:          dto.UWBPRF,
+		UWBSFD:          models.UWBSFD(dto.UWBSFD),
+		UWBTxPower:      dto.UWBTxPower,
+	}
+}
+
+func FromEffectiveConfiguration(effective *models.EffectiveConfiguration) *dtos.EffectiveConfigurationDto {
+	response := &dtos.EffectiveConfigurationDto{
+		StationID:     effective.StationID,
+		ProfileID:     effective.ProfileID,
+		ProfileSource: string(effective.ProfileSource),
+		Configuration: FromStationConfig(effective.Configuration, nil),
+		Sources:       make(map[string]string, len(effective.Sources)),
+		Masked:        effective.Masked,
+	}
+
+	for field, source := range effective.Sources {
+		response.Sources[field] = string(source)
+	}
+
+	return response
+}
+
+func FromConfigApplyReport(report *models.ConfigApplyReport) *dtos.ConfigApplyReportDto {
+	response := &dtos.ConfigApplyReportDto{
+		ClusterID: report.ClusterID,
+		Applied:   report.Applied,
+		Unchanged: report.Unchanged,
+		Invalid:   report.Invalid,
+		Skipped:   report.Skipped,
+		Masked:    report.Masked,
+		Results:   make([]*dtos.ConfigApplyResultDto, len(report.Results)),
+	}
+
+	for i, result := range report.Results {
+		response.Results[i] = &dtos.ConfigApplyResultDto{
+			StationID: result.StationID,
+			Status:    string(result.Status),
+			Errors:    fromValidationError(result.Error),
+		}
+
+		if result.Effective != nil {
+			response.Results[i].ProfileID = result.Effective.ProfileID
+			response.Results[i].Configuration = FromStationConfig(result.Effective.Configuration, nil)
+			response.Results[i].Masked = result.Effective.Masked
+		}
+	}
+
+	return response
+}
+
+func fromValidationError(err error) []dtos.ValidationErrorDto {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validation.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []dtos.ValidationErrorDto{{Message: err.Error()}}
+	}
+
+	response := make([]dtos.ValidationErrorDto, len(validationErrors))
+	for i, validationError := range validationErrors {
+		response[i] = dtos.ValidationErrorDto{Field: validationError.Field, Message: validationError.Message}
+	}
+
+	return response
+}
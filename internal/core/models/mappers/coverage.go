@@ -0,0 +1,65 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func ToCoverageRequest(dto *dtos.CoverageRequestDto) *models.CoverageRequest {
+	request := &models.CoverageRequest{
+		Anchors:        make([]models.CoverageAnchor, 0, len(dto.Anchors)),
+		ReplaceAnchors: dto.ReplaceAnchors,
+		MinX:           dto.MinX,
+		MinY:           dto.MinY,
+		MaxX:           dto.MaxX,
+		MaxY:           dto.MaxY,
+		Resolution:     dto.Resolution,
+		Height:         dto.Height,
+		MaxRange:       dto.MaxRange,
+		Dimension:      dto.Dimension,
+	}
+
+	for _, anchor := range dto.Anchors {
+		request.Anchors = append(request.Anchors, models.CoverageAnchor{
+			StationID:    anchor.StationID,
+			X:            anchor.X,
+			Y:            anchor.Y,
+			Z:            anchor.Z,
+			Hypothetical: true,
+		})
+	}
+
+	return request
+}
+
+func FromCoverageMap(coverage *models.CoverageMap) *dtos.CoverageMapDto {
+	response := &dtos.CoverageMapDto{
+		ClusterID:  coverage.ClusterID,
+		Units:      coverage.Units,
+		MinX:       coverage.MinX,
+		MinY:       coverage.MinY,
+		MaxX:       coverage.MaxX,
+		MaxY:       coverage.MaxY,
+		Resolution: coverage.Resolution,
+		Height:     coverage.Height,
+		MaxRange:   coverage.MaxRange,
+		Dimension:  coverage.Dimension,
+		Columns:    coverage.Columns,
+		Rows:       coverage.Rows,
+		HDOP:       coverage.HDOP,
+		Visible:    coverage.Visible,
+		Anchors:    make([]*dtos.CoverageAnchorDto, 0, len(coverage.Anchors)),
+	}
+
+	for _, anchor := range coverage.Anchors {
+		response.Anchors = append(response.Anchors, &dtos.CoverageAnchorDto{
+			StationID:    anchor.StationID,
+			X:            anchor.X,
+			Y:            anchor.Y,
+			Z:            anchor.Z,
+			Hypothetical: anchor.Hypothetical,
+		})
+	}
+
+	return response
+}
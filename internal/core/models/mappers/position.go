@@ -0,0 +1,86 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/infrastructure/http/dto"
+)
+
+func FromPositionFix(fix *models.PositionFix, includeParam *string) *dtos.PositionDto {
+	includes := dto.ParseIncludes(includeParam)
+
+	response := &dtos.PositionDto{
+		StationID:   fix.StationID,
+		ClusterID:   fix.ClusterID,
+		X:           fix.X,
+		Y:           fix.Y,
+		Z:           fix.Z,
+		Dimension:   fix.Dimension,
+		Residual:    fix.Residual,
+		Quality:     fix.Quality,
+		AnchorCount: fix.AnchorCount,
+		GDOP:        fix.GDOP,
+		HDOP:        fix.HDOP,
+		VDOP:        fix.VDOP,
+		FixType:     string(fix.FixType),
+		Timestamp:   fix.Timestamp,
+		Mode:        "raw",
+		Frame:       models.LocalFrame,
+		Units:       "m",
+		Level:       fix.Level,
+		WGS84:       fromGeoPosition(fix.Geo),
+	}
+
+	if includes["anchors"] {
+		response.AnchorIDs = fix.AnchorIDs
+	}
+
+	return response
+}
+
+func FromTrackedPosition(tracked *models.TrackedPosition, includeParam *string) *dtos.PositionDto {
+	response := &dtos.PositionDto{
+		StationID: tracked.StationID,
+		ClusterID: tracked.ClusterID,
+		Timestamp: tracked.Timestamp,
+	}
+
+	if tracked.Fix != nil {
+		response = FromPositionFix(tracked.Fix, includeParam)
+	}
+
+	response.X = tracked.X
+	response.Y = tracked.Y
+	response.Z = tracked.Z
+	response.Timestamp = tracked.Timestamp
+	response.Mode = "filtered"
+	response.Velocity = &dtos.VectorDto{X: tracked.VX, Y: tracked.VY, Z: tracked.VZ}
+	response.Covariance = tracked.Covariance
+	response.Frame = models.LocalFrame
+	response.Units = "m"
+	response.Level = tracked.Level
+	response.WGS84 = fromGeoPosition(tracked.Geo)
+
+	return response
+}
+
+func FromPositionFixList(fixes []*models.PositionFix, includeParam *string) []*dtos.PositionDto {
+	response := make([]*dtos.PositionDto, len(fixes))
+	for i, fix := range fixes {
+		response[i] = FromPositionFix(fix, includeParam)
+	}
+
+	return response
+}
+
+func fromGeoPosition(position *models.GeoPosition) *dtos.GeoPositionDto {
+	if position == nil {
+		return nil
+	}
+
+	return &dtos.GeoPositionDto{
+		Latitude:  position.Latitude,
+		Longitude: position.Longitude,
+		Altitude:  position.Altitude,
+	}
+}
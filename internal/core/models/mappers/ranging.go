@@ -10,8 +10,10 @@ func FromRanging(ranging *models.Ranging, includeParam *string) *dtos.RangingDto
 	includes := dto.ParseIncludes(includeParam)
 
 	response := &dtos.RangingDto{
-		ID:          ranging.ID,
-		RawDistance: ranging.RawDistance,
+		ID:               ranging.ID,
+		RawDistance:      ranging.RawDistance,
+		ScaledDistance:   ranging.ScaledDistance,
+		FilteredDistance: ranging.FilteredDistance,
 	}
 
 	if includes["stations"] {
@@ -38,9 +40,10 @@ func FromRanging(ranging *models.Ranging, includeParam *string) *dtos.RangingDto
 
 func ToRanging(rangingDto *dtos.RangingDto) *models.Ranging {
 	return &models.Ranging{
-		RawDistance:   rangingDto.RawDistance,
-		SourceID:      rangingDto.SourceID,
-		DestinationID: rangingDto.DestinationID,
+		RawDistance:    rangingDto.RawDistance,
+		ScaledDistance: rangingDto.ScaledDistance,
+		SourceID:       rangingDto.SourceID,
+		DestinationID:  rangingDto.DestinationID,
 	}
 }
 
@@ -53,3 +56,86 @@ func FromRangingList(rangingList []*models.Ranging, includeParam *string) []*dto
 
 	return response
 }
+
+func FromRangingSamplePoint(point *models.RangingSamplePoint) *dtos.RangingSampleDto {
+	return &dtos.RangingSampleDto{
+		SourceID:       point.SourceID,
+		DestinationID:  point.DestinationID,
+		Timestamp:      point.Timestamp,
+		RawDistance:    point.RawDistance,
+		ScaledDistance: point.ScaledDistance,
+		RSSI:           point.RSSI,
+		FirstPathPower: point.FirstPathPower,
+		SignalQuality:  point.SignalQuality,
+		Rejected:       point.Rejected,
+		RejectStage:    point.RejectStage,
+		RejectReason:   point.RejectReason,
+		SampleCount:    point.SampleCount,
+	}
+}
+
+func FromRangingSamplePointList(points []*models.RangingSamplePoint) []*dtos.RangingSampleDto {
+	response := make([]*dtos.RangingSampleDto, len(points))
+	for i, point := range points {
+		response[i] = FromRangingSamplePoint(point)
+	}
+
+	return response
+}
+
+func FromRangingPairStats(stats *models.RangingPairStats) *dtos.RangingPairStatsDto {
+	return &dtos.RangingPairStatsDto{
+		SourceID:      stats.SourceID,
+		DestinationID: stats.DestinationID,
+		Count:         stats.Count,
+		Mean:          stats.Mean,
+		Median:        stats.Median,
+		StdDev:        stats.StdDev,
+		Min:           stats.Min,
+		Max:           stats.Max,
+		FirstSeen:     stats.FirstSeen,
+		LastSeen:      stats.LastSeen,
+		MaxGap:        stats.MaxGap,
+		UpdateRate:    stats.UpdateRate,
+		ExpectedRate:  stats.ExpectedRate,
+		LossRatio:     stats.LossRatio,
+	}
+}
+
+func FromRangingPairStatsList(statsList []*models.RangingPairStats) []*dtos.RangingPairStatsDto {
+	response := make([]*dtos.RangingPairStatsDto, len(statsList))
+	for i, stats := range statsList {
+		response[i] = FromRangingPairStats(stats)
+	}
+
+	return response
+}
+
+func FromRangingFilterStatistics(statistics *models.RangingFilterStatistics, includeParam *string) *dtos.RangingFilterStatisticsDto {
+	includes := dto.ParseIncludes(includeParam)
+
+	response := &dtos.RangingFilterStatisticsDto{
+		Stages:          statistics.Stages,
+		Processed:       statistics.Processed,
+		Accepted:        statistics.Accepted,
+		Rejected:        statistics.Rejected,
+		RejectedByStage: statistics.RejectedByStage,
+	}
+
+	if includes["pairs"] {
+		response.Pairs = make([]*dtos.RangingPairFilterStatisticsDto, len(statistics.Pairs))
+		for i, pairStats := range statistics.Pairs {
+			response.Pairs[i] = &dtos.RangingPairFilterStatisticsDto{
+				SourceID:        pairStats.SourceID,
+				DestinationID:   pairStats.DestinationID,
+				Processed:       pairStats.Processed,
+				Rejected:        pairStats.Rejected,
+				RejectedByStage: pairStats.RejectedByStage,
+				LastRejectedAt:  pairStats.LastRejectedAt,
+				LastReason:      pairStats.LastReason,
+			}
+		}
+	}
+
+	return response
+}
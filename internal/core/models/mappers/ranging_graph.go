@@ -0,0 +1,46 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func FromRangingGraph(graph *models.RangingGraph) *dtos.RangingGraphDto {
+	response := &dtos.RangingGraphDto{
+		ClusterID: graph.ClusterID,
+		From:      graph.From,
+		To:        graph.To,
+		Nodes:     make([]*dtos.RangingGraphNodeDto, 0, len(graph.Nodes)),
+		Edges:     make([]*dtos.RangingGraphEdgeDto, 0, len(graph.Edges)),
+	}
+
+	for _, node := range graph.Nodes {
+		nodeDto := &dtos.RangingGraphNodeDto{
+			StationID:  node.StationID,
+			Name:       node.Name,
+			MacAddress: node.MacAddress,
+			Mode:       string(node.Mode),
+			Degree:     node.Degree,
+		}
+
+		if node.Position.IsSet() {
+			nodeDto.Position = FromStationPosition(node.Position)
+		}
+
+		response.Nodes = append(response.Nodes, nodeDto)
+	}
+
+	for _, edge := range graph.Edges {
+		response.Edges = append(response.Edges, &dtos.RangingGraphEdgeDto{
+			SourceID:      edge.SourceID,
+			DestinationID: edge.DestinationID,
+			Distance:      edge.Distance,
+			LastSeen:      edge.LastSeen,
+			SampleCount:   edge.SampleCount,
+			SampleRate:    edge.SampleRate,
+			Stale:         edge.Stale,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,89 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func FromRollout(rollout *models.Rollout) *dtos.RolloutDto {
+	response := &dtos.RolloutDto{
+		ID:               rollout.ID,
+		ProfileID:        rollout.ProfileID,
+		ClusterID:        rollout.ClusterID,
+		Status:           string(rollout.Status),
+		FailureAction:    string(rollout.FailureAction),
+		SettleTime:       rollout.SettleTime.String(),
+		MaxRateDrop:      rollout.MaxRateDrop,
+		MaxErrorIncrease: rollout.MaxErrorIncrease,
+		CurrentWave:      rollout.CurrentWave,
+		Waves:            make([]*dtos.RolloutWaveDto, len(rollout.Waves)),
+		NextCheckAt:      rollout.NextCheckAt,
+		Actor:            rollout.Actor,
+		Message:          rollout.Message,
+		CreatedAt:        rollout.CreatedAt,
+		UpdatedAt:        rollout.UpdatedAt,
+	}
+
+	for i, wave := range rollout.Waves {
+		response.Waves[i] = &dtos.RolloutWaveDto{
+			Percent:    wave.Percent,
+			StationIDs: wave.StationIDs,
+			Status:     string(wave.Status),
+			Baseline:   fromRolloutHealth(wave.Baseline),
+			Health:     fromRolloutHealth(wave.Health),
+			AppliedAt:  wave.AppliedAt,
+			CheckedAt:  wave.CheckedAt,
+			Restore:    wave.Restore,
+			Invalid:    wave.Invalid,
+			Masked:     wave.Masked,
+		}
+	}
+
+	return response
+}
+
+func FromRolloutList(rollouts []*models.Rollout) []*dtos.RolloutDto {
+	response := make([]*dtos.RolloutDto, len(rollouts))
+	for i, rollout := range rollouts {
+		response[i] = FromRollout(rollout)
+	}
+
+	return response
+}
+
+// ToRollout maps a rollout request. The settle time is parsed by the
+// controller.
+func ToRollout(request *dtos.RolloutRequestDto) *models.Rollout {
+	rollout := &models.Rollout{
+		ProfileID:        request.ProfileID,
+		ClusterID:        request.ClusterID,
+		FailureAction:    models.RolloutFailureAction(request.FailureAction),
+		MaxRateDrop:      request.MaxRateDrop,
+		MaxErrorIncrease: request.MaxErrorIncrease,
+	}
+
+	for _, percent := range request.Waves {
+		rollout.Waves = append(rollout.Waves, models.RolloutWave{Percent: percent})
+	}
+
+	return rollout
+}
+
+func fromRolloutHealth(health *models.RolloutHealth) *dtos.RolloutHealthDto {
+	if health == nil {
+		return nil
+	}
+
+	return &dtos.RolloutHealthDto{
+		Stations:   health.Stations,
+		Pairs:      len(health.Pairs),
+		Samples:    health.Samples,
+		Rejected:   health.Rejected,
+		SampleRate: health.SampleRate,
+		ErrorRate:  health.ErrorRate,
+		Degraded:   health.Degraded,
+		Reasons:    health.Reasons,
+		From:       health.From,
+		To:         health.To,
+	}
+}
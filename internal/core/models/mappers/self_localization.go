@@ -0,0 +1,35 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func FromSelfLocalization(result *models.SelfLocalization) *dtos.SelfLocalizationDto {
+	response := &dtos.SelfLocalizationDto{
+		ClusterID: result.ClusterID,
+		Dimension: result.Dimension,
+		Residual:  result.Residual,
+		Mirrored:  result.Mirrored,
+		Ambiguous: result.Ambiguous,
+		Anchors:   make([]*dtos.SelfLocalizedAnchorDto, 0, len(result.Anchors)),
+		Warnings:  result.Warnings,
+		From:      result.From,
+		To:        result.To,
+		Timestamp: result.Timestamp,
+	}
+
+	for _, anchor := range result.Anchors {
+		response.Anchors = append(response.Anchors, &dtos.SelfLocalizedAnchorDto{
+			StationID:  anchor.StationID,
+			X:          anchor.X,
+			Y:          anchor.Y,
+			Z:          anchor.Z,
+			Error:      anchor.Error,
+			Pinned:     anchor.Pinned,
+			Neighbours: anchor.Neighbours,
+		})
+	}
+
+	return response
+}
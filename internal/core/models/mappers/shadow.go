@@ -0,0 +1,70 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+)
+
+func FromStationShadow(shadow *models.StationShadow) *dtos.StationShadowDto {
+	response := &dtos.StationShadowDto{
+		StationID:       shadow.StationID,
+		ConfigurationID: shadow.ConfigurationID,
+		Desired:         fromShadowState(shadow.Desired),
+		ReportedAt:      shadow.ReportedAt,
+		Delta:           make([]dtos.ShadowDifferenceDto, len(shadow.Delta)),
+		Drifted:         shadow.Drifted,
+		Stale:           shadow.Stale,
+		Timestamp:       shadow.Timestamp,
+	}
+
+	if shadow.Reported != nil {
+		reported := fromShadowState(*shadow.Reported)
+		response.Reported = &reported
+	}
+
+	for i, difference := range shadow.Delta {
+		response.Delta[i] = dtos.ShadowDifferenceDto{
+			Field:    difference.Field,
+			Desired:  difference.Desired,
+			Reported: difference.Reported,
+		}
+	}
+
+	if shadow.Sync.Status != "" {
+		response.Sync = &dtos.ConfigSyncDto{
+			ConfigurationID: shadow.ConfigurationID,
+			StationID:       shadow.StationID,
+			Status:          string(shadow.Sync.Status),
+			Revision:        shadow.Sync.Revision,
+			Attempts:        shadow.Sync.Attempts,
+			PublishedAt:     shadow.Sync.PublishedAt,
+			AcknowledgedAt:  shadow.Sync.AcknowledgedAt,
+			Error:           shadow.Sync.Error,
+		}
+	}
+
+	return response
+}
+
+func FromStationShadowList(shadows []*models.StationShadow) []*dtos.StationShadowDto {
+	response := make([]*dtos.StationShadowDto, len(shadows))
+	for i, shadow := range shadows {
+		response[i] = FromStationShadow(shadow)
+	}
+
+	return response
+}
+
+func fromShadowState(state models.ShadowState) dtos.UWBConfigurationDto {
+	return dtos.UWBConfigurationDto{
+		Mode:           string(state.UWBMode),
+		Channel:        state.UWBChannel,
+		PreambleCode:   state.UWBPreambleCode,
+		PreambleLength: state.UWBPreambleLen,
+		DataRate:       state.UWBDataRate,
+		PRF:            state.UWBPRF,
+		SFD:            string(state.UWBSFD),
+		TxPower:        state.UWBTxPower,
+		AntennaDelay:   state.UWBAntennaDelay,
+	}
+}
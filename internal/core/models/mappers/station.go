@@ -3,6 +3,7 @@ package mappers
 import (
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/events"
 	"gps-no-server/internal/infrastructure/http/dto"
 )
 
@@ -10,9 +11,22 @@ func FromStation(station *models.Station, includeParam *string) *dtos.StationDto
 	includes := dto.ParseIncludes(includeParam)
 
 	response := &dtos.StationDto{
-		ID:         station.ID,
-		MacAddress: station.MacAddress,
-		Name:       station.Name,
+		ID:              station.ID,
+		MacAddress:      station.MacAddress,
+		Name:            station.Name,
+		ReportedCluster: station.ReportedCluster,
+		ReportedPeers:   station.ReportedPeers,
+		ClusterMismatch: station.ClusterMismatch,
+		Status:          string(station.Status),
+		LastSeen:        station.LastSeenAt,
+	}
+
+	if response.Status == "" {
+		response.Status = string(models.StationUnknown)
+	}
+
+	if station.Position.IsSet() {
+		response.Position = FromStationPosition(station.Position)
 	}
 
 	if includes["cluster"] && station.Cluster != nil {
@@ -25,18 +39,55 @@ func FromStation(station *models.Station, includeParam *string) *dtos.StationDto
 		response.CreatedAt = &station.CreatedAt
 		response.UpdatedAt = &station.UpdatedAt
 		response.DeletedAt = &station.DeletedAt
-		response.LastSeen = &station.Uptime
+		response.StartedAt = station.StartedAt
+		response.Uptime = &station.UptimeSeconds
 	}
 
 	return response
 }
 
 func ToStation(dto *dtos.StationDto) *models.Station {
-	return &models.Station{
+	station := &models.Station{
 		MacAddress: dto.MacAddress,
 		Name:       dto.Name,
 		ClusterID:  dto.ClusterID,
 	}
+
+	if dto.Position != nil {
+		station.Position = ToStationPosition(dto.Position)
+	}
+
+	return station
+}
+
+func FromStationPosition(position models.Position) *dtos.StationPositionDto {
+	return &dtos.StationPositionDto{
+		X:      position.X,
+		Y:      position.Y,
+		Z:      position.Z,
+		Frame:  position.Frame,
+		Source: string(position.Source),
+	}
+}
+
+func ToStationPosition(dto *dtos.StationPositionDto) models.Position {
+	position := models.Position{
+		X:      dto.X,
+		Y:      dto.Y,
+		Z:      dto.Z,
+		Frame:  dto.Frame,
+		Source: models.PositionSource(dto.Source),
+	}
+
+	if position.Frame == "" {
+		position.Frame = models.LocalFrame
+	}
+
+	if position.Source == "" {
+		position.Source = models.SurveyedPosition
+	}
+
+	return position
 }
 
 func FromStationList(stations []*models.Station, includeParam *string) []*dtos.StationDto {
@@ -48,3 +99,17 @@ func FromStationList(stations []*models.Station, includeParam *string) []*dtos.S
 
 	return response
 }
+
+func FromPresenceEvent(event *events.StationEvent) *dtos.PresenceEventDto {
+	status := models.StationOffline
+	if event.Type == events.StationWentOnline {
+		status = models.StationOnline
+	}
+
+	return &dtos.PresenceEventDto{
+		StationID: event.StationId,
+		ClusterID: event.ClusterId,
+		Status:    string(status),
+		Timestamp: event.Timestamp,
+	}
+}
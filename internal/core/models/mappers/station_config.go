@@ -4,16 +4,34 @@ import (
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/models/dtos"
 	"gps-no-server/internal/infrastructure/http/dto"
+	"time"
 )
 
 func FromStationConfig(config *models.StationConfiguration, includeParam *string) *dtos.StationConfigurationDto {
 	includes := dto.ParseIncludes(includeParam)
 
 	response := &dtos.StationConfigurationDto{
-		ID:         config.ID,
-		StationID:  config.StationID,
-		UWBMode:    string(config.UWBMode),
-		UWBChannel: config.UWBChannel,
+		ID:              config.ID,
+		StationID:       config.StationID,
+		UWBMode:         string(config.UWBMode),
+		UWBChannel:      config.UWBChannel,
+		UWBPreambleCode: config.UWBPreambleCode,
+		UWBPreambleLen:  config.UWBPreambleLen,
+		UWBChip:         string(config.UWBChip),
+		UWBDataRate:     config.UWBDataRate,
+		UWBPRF:          config.UWBPRF,
+		UWBSFD:          string(config.UWBSFD),
+		UWBTxPower:      config.UWBTxPower,
+		UWBAntennaDelay: config.UWBAntennaDelay,
+		ProfileID:       config.ProfileID,
+	}
+
+	if config.Overrides != (models.ConfigOverrides{}) {
+		response.Overrides = fromConfigOverrides(config.Overrides)
+	}
+
+	if config.Sync.Status != "" {
+		response.Sync = FromConfigSync(config)
 	}
 
 	if includes["meta"] {
@@ -26,11 +44,38 @@ func FromStationConfig(config *models.StationConfiguration, includeParam *string
 }
 
 func ToStationConfig(dto *dtos.StationConfigurationDto) *models.StationConfiguration {
-	return &models.StationConfiguration{
-		StationID:  dto.StationID,
-		UWBMode:    models.UWBMode(dto.UWBMode),
-		UWBChannel: dto.UWBChannel,
+	config := &models.StationConfiguration{
+		StationID:       dto.StationID,
+		UWBMode:         models.UWBMode(dto.UWBMode),
+		UWBChannel:      dto.UWBChannel,
+		UWBPreambleCode: dto.UWBPreambleCode,
+		UWBPreambleLen:  dto.UWBPreambleLen,
+		UWBChip:         models.UWBChip(dto.UWBChip),
+		UWBDataRate:     dto.UWBDataRate,
+		UWBPRF:          dto.UWBPRF,
+		UWBSFD:          models.UWBSFD(dto.UWBSFD),
+		UWBTxPower:      dto.UWBTxPower,
+		UWBAntennaDelay: dto.UWBAntennaDelay,
+		ProfileID:       dto.ProfileID,
+	}
+
+	if dto.Overrides != nil {
+		config.Overrides = models.ConfigOverrides{
+			UWBChannel:      dto.Overrides.UWBChannel,
+			UWBPreambleCode: dto.Overrides.UWBPreambleCode,
+			UWBPreambleLen:  dto.Overrides.UWBPreambleLen,
+			UWBDataRate:     dto.Overrides.UWBDataRate,
+			UWBPRF:          dto.Overrides.UWBPRF,
+			UWBTxPower:      dto.Overrides.UWBTxPower,
+		}
+
+		if dto.Overrides.UWBSFD != nil {
+			sfd := models.UWBSFD(*dto.Overrides.UWBSFD)
+			config.Overrides.UWBSFD = &sfd
+		}
 	}
+
+	return config
 }
 
 func FromStationConfigList(configs []*models.StationConfiguration, includeParam *string) []*dtos.StationConfigurationDto {
@@ -41,3 +86,86 @@ func FromStationConfigList(configs []*models.StationConfiguration, includeParam
 
 	return response
 }
+
+func FromConfigSync(config *models.StationConfiguration) *dtos.ConfigSyncDto {
+	return &dtos.ConfigSyncDto{
+		ConfigurationID: config.ID,
+		StationID:       config.StationID,
+		Status:          string(config.Sync.Status),
+		Revision:        config.Sync.Revision,
+		Attempts:        config.Sync.Attempts,
+		PublishedAt:     config.Sync.PublishedAt,
+		AcknowledgedAt:  config.Sync.AcknowledgedAt,
+		Error:           config.Sync.Error,
+	}
+}
+
+func FromConfigSyncList(configs []*models.StationConfiguration) []*dtos.ConfigSyncDto {
+	response := make([]*dtos.ConfigSyncDto, len(configs))
+	for i, config := range configs {
+		response[i] = FromConfigSync(config)
+	}
+
+	return response
+}
+
+func ToConfigurationMessage(config *models.StationConfiguration, timestamp time.Time) *dtos.ConfigurationMessageDto {
+	return &dtos.ConfigurationMessageDto{
+		ConfigurationID: config.ID,
+		Revision:        config.Sync.Revision,
+		UWB:             fromShadowState(config.DesiredState()),
+		Timestamp:       timestamp,
+	}
+}
+
+func fromConfigOverrides(overrides models.ConfigOverrides) *dtos.ConfigOverridesDto {
+	response := &dtos.ConfigOverridesDto{
+		UWBChannel:      overrides.UWBChannel,
+		UWBPreambleCode: overrides.UWBPreambleCode,
+		UWBPreambleLen:  overrides.UWBPreambleLen,
+		UWBDataRate:     overrides.UWBDataRate,
+		UWBPRF:          overrides.UWBPRF,
+		UWBTxPower:      overrides.UWBTxPower,
+	}
+
+	if overrides.UWBSFD != nil {
+		sfd := string(*overrides.UWBSFD)
+		response.UWBSFD = &sfd
+	}
+
+	return response
+}
+
+func FromConfigurationRevision(revision *models.ConfigurationRevision) *dtos.ConfigurationRevisionDto {
+	snapshot := &models.StationConfiguration{StationID: revision.StationID}
+	snapshot.ID = revision.StationConfigurationID
+	snapshot.Restore(revision.Snapshot)
+
+	response := &dtos.ConfigurationRevisionDto{
+		ID:                     revision.ID,
+		StationConfigurationID: revision.StationConfigurationID,
+		StationID:              revision.StationID,
+		Version:                revision.Version,
+		Actor:                  revision.Actor,
+		Source:                 string(revision.Source),
+		RestoredVersion:        revision.RestoredVersion,
+		Configuration:          FromStationConfig(snapshot, nil),
+		Changes:                make([]dtos.ConfigChangeDto, len(revision.Changes)),
+		CreatedAt:              revision.CreatedAt,
+	}
+
+	for i, change := range revision.Changes {
+		response.Changes[i] = dtos.ConfigChangeDto{Field: change.Field, From: change.From, To: change.To}
+	}
+
+	return response
+}
+
+func FromConfigurationRevisionList(revisions []*models.ConfigurationRevision) []*dtos.ConfigurationRevisionDto {
+	response := make([]*dtos.ConfigurationRevisionDto, len(revisions))
+	for i, revision := range revisions {
+		response[i] = FromConfigurationRevision(revision)
+	}
+
+	return response
+}
@@ -0,0 +1,78 @@
+package mappers
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/dtos"
+	"gps-no-server/internal/events"
+	"gps-no-server/internal/infrastructure/http/dto"
+)
+
+func FromZone(zone *models.Zone, includeParam *string) *dtos.ZoneDto {
+	includes := dto.ParseIncludes(includeParam)
+
+	response := &dtos.ZoneDto{
+		ID:          zone.ID,
+		ClusterID:   zone.ClusterID,
+		Name:        zone.Name,
+		Description: zone.Description,
+		Type:        string(zone.Type),
+		Polygon:     make([]*dtos.ZonePointDto, 0, len(zone.Polygon)),
+		MinZ:        zone.MinZ,
+		MaxZ:        zone.MaxZ,
+		DwellLimit:  zone.DwellLimit,
+	}
+
+	for _, point := range zone.Polygon {
+		response.Polygon = append(response.Polygon, &dtos.ZonePointDto{X: point.X, Y: point.Y})
+	}
+
+	if includes["cluster"] && zone.Cluster != nil {
+		response.Cluster = FromCluster(zone.Cluster, nil)
+	}
+
+	if includes["meta"] {
+		response.CreatedAt = &zone.CreatedAt
+		response.UpdatedAt = &zone.UpdatedAt
+		response.DeletedAt = &zone.DeletedAt
+	}
+
+	return response
+}
+
+func ToZone(dto *dtos.ZoneDto) *models.Zone {
+	zone := &models.Zone{
+		ClusterID:   dto.ClusterID,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Type:        models.ZoneType(dto.Type),
+		MinZ:        dto.MinZ,
+		MaxZ:        dto.MaxZ,
+		DwellLimit:  dto.DwellLimit,
+	}
+
+	if dto.Polygon != nil {
+		zone.Polygon = make([]models.ZonePoint, 0, len(dto.Polygon))
+		for _, point := range dto.Polygon {
+			zone.Polygon = append(zone.Polygon, models.ZonePoint{X: point.X, Y: point.Y})
+		}
+	}
+
+	return zone
+}
+
+func FromZoneEvent(event *events.ZoneEvent) *dtos.ZoneEventDto {
+	return &dtos.ZoneEventDto{
+		Type:         string(event.Type),
+		ClusterID:    event.ClusterId,
+		ZoneID:       event.ZoneId,
+		ZoneName:     event.ZoneName,
+		ZoneType:     event.ZoneType,
+		StationID:    event.StationId,
+		X:            event.X,
+		Y:            event.Y,
+		Z:            event.Z,
+		EnteredAt:    event.EnteredAt,
+		DwellSeconds: event.DwellSeconds,
+		Timestamp:    event.Timestamp,
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+type PositionSource string
+
+const (
+	SurveyedPosition  PositionSource = "surveyed"
+	ReportedPosition  PositionSource = "reported"
+	EstimatedPosition PositionSource = "estimated"
+)
+
+const LocalFrame = "local"
+
+func (p PositionSource) IsValid() bool {
+	switch p {
+	case SurveyedPosition, ReportedPosition, EstimatedPosition:
+		return true
+	default:
+		return false
+	}
+}
+
+type Position struct {
+	X      *float64
+	Y      *float64
+	Z      *float64
+	Frame  string         `gorm:"type:varchar(50)"`
+	Source PositionSource `gorm:"type:varchar(20)"`
+}
+
+func (p Position) IsSet() bool {
+	return p.X != nil && p.Y != nil
+}
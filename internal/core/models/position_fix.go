@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+type FixType string
+
+const (
+	FixNone     FixType = "none"
+	Fix2D       FixType = "2d"
+	Fix3D       FixType = "3d"
+	FixDegraded FixType = "degraded"
+)
+
+type PositionFix struct {
+	StationID   uint
+	ClusterID   *uint
+	X           float64
+	Y           float64
+	Z           float64
+	Dimension   int
+	Residual    float64
+	Quality     float64
+	AnchorCount int
+	AnchorIDs   []uint
+	GDOP        float64
+	HDOP        float64
+	VDOP        float64
+	EDOP        float64
+	NDOP        float64
+	FixType     FixType
+	Level       *int
+	Geo         *GeoPosition
+	Timestamp   time.Time
+}
+
+type TrackedPosition struct {
+	StationID  uint
+	ClusterID  *uint
+	X          float64
+	Y          float64
+	Z          float64
+	VX         float64
+	VY         float64
+	VZ         float64
+	Covariance [][]float64
+	Model      string
+	Updates    int
+	Fix        *PositionFix
+	Level      *int
+	Geo        *GeoPosition
+	Timestamp  time.Time
+}
+
+type GeoPosition struct {
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+}
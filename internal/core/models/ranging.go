@@ -6,11 +6,24 @@ import (
 
 type Ranging struct {
 	gorm.Model
-	SourceID      *uint    `gorm:"not null"`
-	Source        *Station `gorm:"foreignKey:SourceID"`
-	DestinationID *uint    `gorm:"not null"`
-	Destination   *Station `gorm:"foreignKey:DestinationID"`
-	RawDistance   float64  `gorm:"default:0.0"`
+	SourceID       *uint    `gorm:"not null;uniqueIndex:idx_rangings_pair"`
+	Source         *Station `gorm:"foreignKey:SourceID"`
+	DestinationID  *uint    `gorm:"not null;uniqueIndex:idx_rangings_pair"`
+	Destination    *Station `gorm:"foreignKey:DestinationID"`
+	RawDistance    float64  `gorm:"default:0.0"`
+	ScaledDistance *float64
+	// FilteredDistance is the output of the ranging filter chain. It is
+	// nil when no filter is configured.
+	FilteredDistance *float64
+}
+
+// Distance returns the filtered distance when available and the raw
+// distance otherwise.
+func (r *Ranging) Distance() float64 {
+	if r.FilteredDistance != nil {
+		return *r.FilteredDistance
+	}
+	return r.RawDistance
 }
 
 func (r Ranging) SetID(id uint) {
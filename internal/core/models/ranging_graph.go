@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type RangingGraph struct {
+	ClusterID uint
+	From      time.Time
+	To        time.Time
+	Nodes     []*RangingGraphNode
+	Edges     []*RangingGraphEdge
+}
+
+type RangingGraphNode struct {
+	StationID  uint
+	Name       string
+	MacAddress string
+	Mode       UWBMode
+	Position   Position
+	Degree     int
+}
+
+// RangingGraphEdge is a directed pair as reported by the source station.
+// Sample figures only cover the graph window and are zero for stale pairs.
+type RangingGraphEdge struct {
+	SourceID      uint
+	DestinationID uint
+	Distance      float64
+	LastSeen      time.Time
+	SampleCount   int64
+	SampleRate    float64
+	Stale         bool
+}
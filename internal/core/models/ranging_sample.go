@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+type RangingSample struct {
+	ID             uint      `gorm:"primarykey"`
+	SourceID       *uint     `gorm:"not null;index:idx_ranging_samples_pair_time,priority:1"`
+	Source         *Station  `gorm:"foreignKey:SourceID"`
+	DestinationID  *uint     `gorm:"not null;index:idx_ranging_samples_pair_time,priority:2"`
+	Destination    *Station  `gorm:"foreignKey:DestinationID"`
+	Timestamp      time.Time `gorm:"not null;index;index:idx_ranging_samples_pair_time,priority:3"`
+	RawDistance    float64   `gorm:"not null"`
+	ScaledDistance *float64
+	RSSI           *float64
+	FirstPathPower *float64
+	SignalQuality  *float64
+	Rejected       bool   `gorm:"not null;default:false;index"`
+	RejectStage    string `gorm:"type:varchar(30)"`
+	RejectReason   string `gorm:"type:varchar(255)"`
+}
+
+func (r RangingSample) TableName() string {
+	return "ranging_samples"
+}
+
+type RangingSamplePoint struct {
+	SourceID       uint
+	DestinationID  uint
+	Timestamp      time.Time
+	RawDistance    float64
+	ScaledDistance *float64
+	RSSI           *float64
+	FirstPathPower *float64
+	SignalQuality  *float64
+	Rejected       bool
+	RejectStage    string
+	RejectReason   string
+	SampleCount    int
+}
+
+type RangingPairStats struct {
+	SourceID      uint
+	DestinationID uint
+	Count         int64
+	Mean          float64
+	Median        float64
+	StdDev        float64
+	Min           float64
+	Max           float64
+	FirstSeen     time.Time
+	LastSeen      time.Time
+	MaxGap        float64
+	UpdateRate    float64
+	ExpectedRate  float64
+	LossRatio     float64
+}
+
+type RangingFilterStatistics struct {
+	Stages          []string
+	Processed       uint64
+	Accepted        uint64
+	Rejected        uint64
+	RejectedByStage map[string]uint64
+	Pairs           []*RangingPairFilterStatistics
+}
+
+type RangingPairFilterStatistics struct {
+	SourceID        uint
+	DestinationID   uint
+	Processed       uint64
+	Rejected        uint64
+	RejectedByStage map[string]uint64
+	LastRejectedAt  *time.Time
+	LastReason      string
+}
+
+type RangingSampleCount struct {
+	SourceID      uint
+	DestinationID uint
+	Samples       int64
+	Rejected      int64
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type RolloutStatus string
+
+const (
+	RolloutRunning    RolloutStatus = "running"
+	RolloutPaused     RolloutStatus = "paused"
+	RolloutCompleted  RolloutStatus = "completed"
+	RolloutRolledBack RolloutStatus = "rolled_back"
+	RolloutAborted    RolloutStatus = "aborted"
+)
+
+func (s RolloutStatus) IsActive() bool {
+	return s == RolloutRunning || s == RolloutPaused
+}
+
+// RolloutFailureAction is what a rollout does when a wave degrades the
+// ranging health of its stations.
+type RolloutFailureAction string
+
+const (
+	PauseOnFailure    RolloutFailureAction = "pause"
+	RollbackOnFailure RolloutFailureAction = "rollback"
+)
+
+func (a RolloutFailureAction) IsValid() bool {
+	return a == PauseOnFailure || a == RollbackOnFailure
+}
+
+type RolloutWaveStatus string
+
+const (
+	WavePending    RolloutWaveStatus = "pending"
+	WaveApplied    RolloutWaveStatus = "applied"
+	WaveHealthy    RolloutWaveStatus = "healthy"
+	WaveUnhealthy  RolloutWaveStatus = "unhealthy"
+	WaveRolledBack RolloutWaveStatus = "rolled_back"
+)
+
+// Rollout assigns a profile to the stations of a cluster in cumulative
+// waves. After every wave the ranging health between the updated stations
+// is compared with the health of the same pairs before the rollout started.
+type Rollout struct {
+	gorm.Model
+	ProfileID     uint                 `gorm:"not null;index"`
+	ClusterID     uint                 `gorm:"not null;index"`
+	Status        RolloutStatus        `gorm:"type:varchar(20);not null;index"`
+	FailureAction RolloutFailureAction `gorm:"type:varchar(20);not null"`
+	// SettleTime is how long the stations of a wave run before their
+	// health is checked.
+	SettleTime time.Duration `gorm:"not null"`
+	// MaxRateDrop is the tolerated relative drop of the per-station sample
+	// rate, MaxErrorIncrease the tolerated increase of the rejected ratio.
+	MaxRateDrop      float64       `gorm:"not null"`
+	MaxErrorIncrease float64       `gorm:"not null"`
+	CurrentWave      int           `gorm:"not null;default:0"`
+	Waves            []RolloutWave `gorm:"serializer:json;type:jsonb"`
+	NextCheckAt      *time.Time
+	Actor            string `gorm:"type:varchar(100);not null"`
+	Message          string `gorm:"type:text"`
+}
+
+func (r Rollout) SetID(id uint) {
+	r.ID = id
+}
+
+func (r Rollout) GetID() uint {
+	return r.ID
+}
+
+func (r Rollout) TableName() string {
+	return "rollouts"
+}
+
+// AppliedStationIDs returns the stations of every wave applied so far,
+// without those whose configuration was invalid for the profile.
+func (r *Rollout) AppliedStationIDs() []uint {
+	stationIds := make([]uint, 0)
+	for _, wave := range r.Waves {
+		if wave.Status == WavePending || wave.Status == WaveRolledBack {
+			continue
+		}
+
+		invalid := make(map[uint]bool, len(wave.Invalid))
+		for _, stationId := range wave.Invalid {
+			invalid[stationId] = true
+		}
+
+		for _, stationId := range wave.StationIDs {
+			if !invalid[stationId] {
+				stationIds = append(stationIds, stationId)
+			}
+		}
+	}
+	return stationIds
+}
+
+type RolloutWave struct {
+	Percent    uint              `json:"percent"`
+	StationIDs []uint            `json:"station_ids"`
+	Status     RolloutWaveStatus `json:"status"`
+	// Baseline is the health between all stations up to this wave before
+	// the rollout started.
+	Baseline  *RolloutHealth `json:"baseline,omitempty"`
+	AppliedAt *time.Time     `json:"applied_at,omitempty"`
+	CheckedAt *time.Time     `json:"checked_at,omitempty"`
+	Health    *RolloutHealth `json:"health,omitempty"`
+	// Restore maps the configurations changed by the wave to the revision
+	// version they are rolled back to.
+	Restore map[uint]uint `json:"restore,omitempty"`
+	Invalid []uint        `json:"invalid,omitempty"`
+	// Masked lists the stations whose overrides keep part of the profile
+	// from taking effect.
+	Masked []uint `json:"masked,omitempty"`
+}
+
+// RolloutHealth summarises the ranging samples exchanged between a set of
+// stations over a window. SampleRate is per pair in Hz, ErrorRate the
+// rejected ratio.
+type RolloutHealth struct {
+	Stations   int                 `json:"stations"`
+	Pairs      []RolloutPairHealth `json:"pairs,omitempty"`
+	Samples    int64               `json:"samples"`
+	Rejected   int64               `json:"rejected"`
+	SampleRate float64             `json:"sample_rate"`
+	ErrorRate  float64             `json:"error_rate"`
+	Degraded   bool                `json:"degraded"`
+	Reasons    []string            `json:"reasons,omitempty"`
+	From       time.Time           `json:"from"`
+	To         time.Time           `json:"to"`
+}
+
+type RolloutPairHealth struct {
+	SourceID      uint  `json:"source_id"`
+	DestinationID uint  `json:"destination_id"`
+	Samples       int64 `json:"samples"`
+	Rejected      int64 `json:"rejected"`
+}
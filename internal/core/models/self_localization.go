@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type SelfLocalization struct {
+	ClusterID uint
+	Dimension int
+	Residual  float64
+	Mirrored  bool
+	Ambiguous bool
+	Anchors   []*SelfLocalizedAnchor
+	Warnings  []string
+	From      time.Time
+	To        time.Time
+	Timestamp time.Time
+}
+
+type SelfLocalizedAnchor struct {
+	StationID  uint
+	X          float64
+	Y          float64
+	Z          float64
+	Error      float64
+	Pinned     bool
+	Neighbours int
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// ShadowState is the device-facing part of a station configuration, used for
+// both the desired and the reported side of the shadow.
+type ShadowState struct {
+	UWBMode         UWBMode
+	UWBChannel      uint8
+	UWBPreambleCode uint8
+	UWBPreambleLen  string
+	UWBDataRate     uint
+	UWBPRF          uint8
+	UWBSFD          UWBSFD
+	UWBTxPower      *float64
+	UWBAntennaDelay uint16
+}
+
+// Diff lists the fields in which the reported state differs from s.
+// Parameters the device did not report are left out of the comparison, as
+// is an unset desired TX power which keeps the device default.
+func (s ShadowState) Diff(reported ShadowState) []ShadowDifference {
+	differences := make([]ShadowDifference, 0)
+	compare := func(field string, desired interface{}, actual interface{}, isReported bool) {
+		if isReported && desired != actual {
+			differences = append(differences, ShadowDifference{Field: field, Desired: desired, Reported: actual})
+		}
+	}
+
+	compare("uwb_mode", s.UWBMode, reported.UWBMode, reported.UWBMode != "")
+	compare("uwb_channel", s.UWBChannel, reported.UWBChannel, reported.UWBChannel != 0)
+	compare("uwb_preamble_code", s.UWBPreambleCode, reported.UWBPreambleCode, reported.UWBPreambleCode != 0)
+	compare("uwb_preamble_len", s.UWBPreambleLen, reported.UWBPreambleLen, reported.UWBPreambleLen != "")
+	compare("uwb_data_rate", s.UWBDataRate, reported.UWBDataRate, reported.UWBDataRate != 0)
+	compare("uwb_prf", s.UWBPRF, reported.UWBPRF, reported.UWBPRF != 0)
+	compare("uwb_sfd", s.UWBSFD, reported.UWBSFD, reported.UWBSFD != "")
+	compare("uwb_antenna_delay", s.UWBAntennaDelay, reported.UWBAntennaDelay, reported.UWBAntennaDelay != 0)
+
+	if s.UWBTxPower != nil && reported.UWBTxPower != nil {
+		compare("uwb_tx_power", *s.UWBTxPower, *reported.UWBTxPower, true)
+	}
+
+	return differences
+}
+
+type ShadowDifference struct {
+	Field    string
+	Desired  interface{}
+	Reported interface{}
+}
+
+// StationShadow pairs the desired configuration of a station with the one
+// its device reported. Reported is nil while the device never reported.
+// Stale marks a report that predates the acknowledgement of the applied
+// revision; its delta is kept for inspection but does not count as drift.
+type StationShadow struct {
+	StationID       uint
+	ConfigurationID uint
+	Desired         ShadowState
+	Reported        *ShadowState
+	ReportedAt      *time.Time
+	Delta           []ShadowDifference
+	Drifted         bool
+	Stale           bool
+	Sync            ConfigSync
+	Timestamp       time.Time
+}
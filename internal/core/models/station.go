@@ -5,14 +5,35 @@ import (
 	"time"
 )
 
+type StationStatus string
+
+const (
+	StationUnknown StationStatus = "unknown"
+	StationOnline  StationStatus = "online"
+	StationOffline StationStatus = "offline"
+)
+
+func (s StationStatus) IsValid() bool {
+	return s == StationUnknown || s == StationOnline || s == StationOffline
+}
+
 type Station struct {
 	gorm.Model
 	MacAddress    string `gorm:"uniqueIndex;not null"`
 	Name          string `gorm:"size:100;not null"`
 	ClusterID     *uint
-	Cluster       *Cluster `gorm:"foreignKey:ClusterID"`
-	Uptime        time.Time
+	Cluster       *Cluster      `gorm:"foreignKey:ClusterID"`
+	Status        StationStatus `gorm:"type:varchar(10);not null;default:'unknown';index"`
+	LastSeenAt    *time.Time    `gorm:"index"`
+	StartedAt     *time.Time
+	UptimeSeconds int64
+	Position      Position              `gorm:"embedded;embeddedPrefix:position_"`
 	StationConfig *StationConfiguration `gorm:"foreignKey:StationID"`
+	// ReportedCluster and ReportedPeers are what the device itself announces.
+	// ClusterMismatch is set while they disagree with the server assignment.
+	ReportedCluster *string  `gorm:"size:100"`
+	ReportedPeers   []string `gorm:"serializer:json;type:jsonb"`
+	ClusterMismatch bool     `gorm:"not null;default:false"`
 }
 
 func (s Station) SetID(id uint) {
@@ -34,13 +55,8 @@ func (s *Station) AfterCreate(tx *gorm.DB) error {
 	}
 
 	if count == 0 {
-		config := StationConfiguration{
-			StationID:       s.ID,
-			UWBMode:         AnchorMode,
-			UWBChannel:      5,
-			UWBPreambleCode: 9,
-			UWBPreambleLen:  "128",
-		}
+		config := StationConfiguration{StationID: s.ID}
+		config.ApplyDefaults()
 
 		return tx.Create(&config).Error
 	}
@@ -1,6 +1,9 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type UWBMode string
 
@@ -10,6 +13,37 @@ const (
 	NoneMode   UWBMode = "NONE"
 )
 
+func (m UWBMode) IsValid() bool {
+	return m == AnchorMode || m == TagMode || m == NoneMode
+}
+
+type UWBChip string
+
+const (
+	DW1000Chip UWBChip = "DW1000"
+	DW3000Chip UWBChip = "DW3000"
+)
+
+// UWBSFD selects the start-of-frame delimiter. The Decawave variants are
+// non-standard sequences with better performance between Decawave chips.
+type UWBSFD string
+
+const (
+	StandardSFD   UWBSFD = "standard"
+	DecawaveSFD   UWBSFD = "decawave"
+	Decawave16SFD UWBSFD = "decawave_16"
+	IEEE4zSFD     UWBSFD = "4z"
+)
+
+const (
+	DefaultUWBChannel      uint8  = 5
+	DefaultUWBPreambleCode uint8  = 9
+	DefaultUWBPreambleLen  string = "128"
+	DefaultUWBDataRate     uint   = 6800
+	DefaultUWBPRF          uint8  = 64
+	DefaultUWBAntennaDelay uint16 = 16436
+)
+
 type StationConfiguration struct {
 	gorm.Model
 	StationID uint     `gorm:"uniqueIndex;not null"`
@@ -19,6 +53,126 @@ type StationConfiguration struct {
 	UWBChannel      uint8   `gorm:"not null;default:5"`
 	UWBPreambleCode uint8   `gorm:"not null;default:9"`
 	UWBPreambleLen  string  `gorm:"type:varchar(20);not null;default:'128'"`
+	UWBChip         UWBChip `gorm:"type:varchar(10);not null;default:'DW1000'"`
+	// UWBDataRate is given in kbps and UWBPRF in MHz.
+	UWBDataRate uint   `gorm:"not null;default:6800"`
+	UWBPRF      uint8  `gorm:"column:uwb_prf;not null;default:64"`
+	UWBSFD      UWBSFD `gorm:"column:uwb_sfd;type:varchar(15);not null;default:'standard'"`
+	// UWBTxPower is the transmit gain in dB, nil keeps the device default.
+	UWBTxPower *float64
+	// UWBAntennaDelay is given in device time units of about 15.65 ps.
+	UWBAntennaDelay uint16 `gorm:"not null;default:16436"`
+
+	ProfileID *uint                 `gorm:"index"`
+	Profile   *ConfigurationProfile `gorm:"foreignKey:ProfileID"`
+	Overrides ConfigOverrides       `gorm:"serializer:json;type:jsonb"`
+
+	Sync     ConfigSync            `gorm:"embedded;embeddedPrefix:sync_"`
+	Reported ReportedConfiguration `gorm:"embedded;embeddedPrefix:reported_"`
+}
+
+type ConfigSyncStatus string
+
+const (
+	SyncPending  ConfigSyncStatus = "pending"
+	SyncApplied  ConfigSyncStatus = "applied"
+	SyncFailed   ConfigSyncStatus = "failed"
+	SyncTimedOut ConfigSyncStatus = "timed_out"
+)
+
+func (s ConfigSyncStatus) IsValid() bool {
+	return s == SyncPending || s == SyncApplied || s == SyncFailed || s == SyncTimedOut
+}
+
+// ConfigSync tracks delivery of the configuration to the device. Revision
+// increases with every push and is echoed back in acknowledgements. Attempts
+// counts the pushes since the device last applied a configuration.
+type ConfigSync struct {
+	Status         ConfigSyncStatus `gorm:"type:varchar(10);index"`
+	Revision       uint             `gorm:"not null;default:0"`
+	Attempts       uint             `gorm:"not null;default:0"`
+	PublishedAt    *time.Time
+	AcknowledgedAt *time.Time
+	Error          string `gorm:"type:varchar(255)"`
+}
+
+// ReportedConfiguration is the configuration the device last reported as
+// active. Timestamp stays nil until the device reported at least once.
+type ReportedConfiguration struct {
+	UWBMode         UWBMode `gorm:"type:varchar(10)"`
+	UWBChannel      uint8
+	UWBPreambleCode uint8
+	UWBPreambleLen  string `gorm:"type:varchar(20)"`
+	UWBDataRate     uint
+	UWBPRF          uint8  `gorm:"column:uwb_prf"`
+	UWBSFD          UWBSFD `gorm:"column:uwb_sfd;type:varchar(15)"`
+	UWBTxPower      *float64
+	UWBAntennaDelay uint16
+	Timestamp       *time.Time
+}
+
+// ApplyDefaults fills unset radio parameters with the values the database
+// would use, so a new configuration can be validated before it is stored.
+func (s *StationConfiguration) ApplyDefaults() {
+	if s.UWBMode == "" {
+		s.UWBMode = AnchorMode
+	}
+	if s.UWBChip == "" {
+		s.UWBChip = DW1000Chip
+	}
+	if s.UWBChannel == 0 {
+		s.UWBChannel = DefaultUWBChannel
+	}
+	if s.UWBPreambleCode == 0 {
+		s.UWBPreambleCode = DefaultUWBPreambleCode
+	}
+	if s.UWBPreambleLen == "" {
+		s.UWBPreambleLen = DefaultUWBPreambleLen
+	}
+	if s.UWBDataRate == 0 {
+		s.UWBDataRate = DefaultUWBDataRate
+	}
+	if s.UWBPRF == 0 {
+		s.UWBPRF = DefaultUWBPRF
+	}
+	if s.UWBSFD == "" {
+		s.UWBSFD = StandardSFD
+	}
+	if s.UWBAntennaDelay == 0 {
+		s.UWBAntennaDelay = DefaultUWBAntennaDelay
+	}
+}
+
+func (s StationConfiguration) DesiredState() ShadowState {
+	return ShadowState{
+		UWBMode:         s.UWBMode,
+		UWBChannel:      s.UWBChannel,
+		UWBPreambleCode: s.UWBPreambleCode,
+		UWBPreambleLen:  s.UWBPreambleLen,
+		UWBDataRate:     s.UWBDataRate,
+		UWBPRF:          s.UWBPRF,
+		UWBSFD:          s.UWBSFD,
+		UWBTxPower:      s.UWBTxPower,
+		UWBAntennaDelay: s.UWBAntennaDelay,
+	}
+}
+
+func (s StationConfiguration) ReportedState() (ShadowState, bool) {
+	if s.Reported.Timestamp == nil {
+		return ShadowState{}, false
+	}
+
+	return ShadowState{
+		UWBMode:         s.Reported.UWBMode,
+		UWBChannel:      s.Reported.UWBChannel,
+		UWBPreambleCode: s.Reported.UWBPreambleCode,
+		UWBPreambleLen:  s.Reported.UWBPreambleLen,
+		UWBDataRate:     s.Reported.UWBDataRate,
+		UWBPRF:          s.Reported.UWBPRF,
+		UWBSFD:          s.Reported.UWBSFD,
+		UWBTxPower:      s.Reported.UWBTxPower,
+		UWBAntennaDelay: s.Reported.UWBAntennaDelay,
+	}, true
 }
 
 func (s StationConfiguration) SetID(id uint) {
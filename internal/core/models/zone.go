@@ -0,0 +1,81 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+type ZoneType string
+
+const (
+	RestrictedZone ZoneType = "restricted"
+	SafeZone       ZoneType = "safe"
+	GenericZone    ZoneType = "generic"
+)
+
+func (t ZoneType) IsValid() bool {
+	switch t {
+	case RestrictedZone, SafeZone, GenericZone:
+		return true
+	default:
+		return false
+	}
+}
+
+type Zone struct {
+	gorm.Model
+	ClusterID   *uint    `gorm:"index;not null"`
+	Cluster     *Cluster `gorm:"foreignKey:ClusterID"`
+	Name        string   `gorm:"size:100;not null"`
+	Description string   `gorm:"type:text"`
+	Type        ZoneType `gorm:"type:varchar(30);not null;default:generic"`
+	// Polygon vertices are expressed in the cluster's local frame.
+	Polygon    []ZonePoint `gorm:"serializer:json;type:jsonb"`
+	MinZ       *float64
+	MaxZ       *float64
+	DwellLimit *int // seconds
+}
+
+type ZonePoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Contains reports whether the point lies inside the polygon using the
+// even-odd rule, optionally bounded by the vertical limits of the zone.
+func (z Zone) Contains(x, y, height float64) bool {
+	if z.MinZ != nil && height < *z.MinZ {
+		return false
+	}
+
+	if z.MaxZ != nil && height > *z.MaxZ {
+		return false
+	}
+
+	return z.ContainsHorizontal(x, y)
+}
+
+// ContainsHorizontal ignores the vertical limits, for points whose height
+// is unknown.
+func (z Zone) ContainsHorizontal(x, y float64) bool {
+	inside := false
+	for i, j := 0, len(z.Polygon)-1; i < len(z.Polygon); j, i = i, i+1 {
+		a, b := z.Polygon[i], z.Polygon[j]
+		if (a.Y > y) != (b.Y > y) && x < (b.X-a.X)*(y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+func (z Zone) SetID(id uint) {
+	z.ID = id
+}
+
+func (z Zone) GetID() uint {
+	return z.ID
+}
+
+func (z Zone) TableName() string {
+	return "zones"
+}
@@ -0,0 +1,161 @@
+package planning
+
+import (
+	"sort"
+)
+
+const (
+	// SharedCodeCost applies to neighbours on the same channel and
+	// preamble code, which interfere with each other.
+	SharedCodeCost = 10.0
+	// SharedChannelCost applies to neighbours on the same channel with
+	// different codes. The codes are quasi-orthogonal but still share
+	// airtime.
+	SharedChannelCost = 1.0
+	// ChangeCost is added for moving a cluster away from its current
+	// assignment, so existing clusters are only retuned to resolve
+	// conflicts.
+	ChangeCost = 0.5
+
+	maxIterations = 50
+)
+
+type Assignment struct {
+	Channel      uint8
+	PreambleCode uint8
+}
+
+// Node is a cluster to assign. Candidates lists the assignments all of its
+// stations support.
+type Node struct {
+	ID         uint
+	Current    *Assignment
+	Candidates []Assignment
+}
+
+type Edge struct {
+	A uint
+	B uint
+}
+
+// Cost returns the interference cost of two neighbours.
+func Cost(a Assignment, b Assignment) float64 {
+	switch {
+	case a == b:
+		return SharedCodeCost
+	case a.Channel == b.Channel:
+		return SharedChannelCost
+	default:
+		return 0
+	}
+}
+
+// AssignChannels picks an assignment for every node that minimises the
+// interference cost between neighbours plus the cost of changes. Nodes are
+// assigned greedily by descending degree and then improved one at a time
+// until no change lowers the cost. Nodes without candidates keep their
+// current assignment and are left out of the result when they have none.
+func AssignChannels(nodes []Node, edges []Edge) map[uint]Assignment {
+	neighbours := make(map[uint][]uint, len(nodes))
+	for _, edge := range edges {
+		neighbours[edge.A] = append(neighbours[edge.A], edge.B)
+		neighbours[edge.B] = append(neighbours[edge.B], edge.A)
+	}
+
+	order := make([]Node, len(nodes))
+	copy(order, nodes)
+	sort.SliceStable(order, func(i, j int) bool {
+		if len(neighbours[order[i].ID]) != len(neighbours[order[j].ID]) {
+			return len(neighbours[order[i].ID]) > len(neighbours[order[j].ID])
+		}
+		return order[i].ID < order[j].ID
+	})
+
+	assignments := make(map[uint]Assignment, len(nodes))
+	for _, node := range order {
+		if len(node.Candidates) == 0 && node.Current != nil {
+			assignments[node.ID] = *node.Current
+		}
+	}
+
+	for _, node := range order {
+		if best, exists := bestAssignment(node, neighbours[node.ID], assignments); exists {
+			assignments[node.ID] = best
+		}
+	}
+
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		improved := false
+
+		for _, node := range order {
+			best, exists := bestAssignment(node, neighbours[node.ID], assignments)
+			if !exists || best == assignments[node.ID] {
+				continue
+			}
+
+			if nodeCost(node, best, neighbours[node.ID], assignments) < nodeCost(node, assignments[node.ID], neighbours[node.ID], assignments) {
+				assignments[node.ID] = best
+				improved = true
+			}
+		}
+
+		if !improved {
+			break
+		}
+	}
+
+	return assignments
+}
+
+// bestAssignment returns the cheapest candidate given the assignments of the
+// neighbours. Ties prefer the current assignment, then the lowest channel
+// and code.
+func bestAssignment(node Node, neighbours []uint, assignments map[uint]Assignment) (Assignment, bool) {
+	if len(node.Candidates) == 0 {
+		return Assignment{}, false
+	}
+
+	candidates := sortedCandidates(node)
+	best := candidates[0]
+	bestCost := nodeCost(node, best, neighbours, assignments)
+
+	for _, candidate := range candidates[1:] {
+		if cost := nodeCost(node, candidate, neighbours, assignments); cost < bestCost {
+			best, bestCost = candidate, cost
+		}
+	}
+
+	return best, true
+}
+
+func nodeCost(node Node, assignment Assignment, neighbours []uint, assignments map[uint]Assignment) float64 {
+	cost := 0.0
+	if node.Current != nil && *node.Current != assignment {
+		cost += ChangeCost
+	}
+
+	for _, neighbour := range neighbours {
+		if other, exists := assignments[neighbour]; exists {
+			cost += Cost(assignment, other)
+		}
+	}
+
+	return cost
+}
+
+func sortedCandidates(node Node) []Assignment {
+	candidates := make([]Assignment, len(node.Candidates))
+	copy(candidates, node.Candidates)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if node.Current != nil && (candidates[i] == *node.Current) != (candidates[j] == *node.Current) {
+			return candidates[i] == *node.Current
+		}
+		if candidates[i].Channel != candidates[j].Channel {
+			return candidates[i].Channel < candidates[j].Channel
+		}
+		return candidates[i].PreambleCode < candidates[j].PreambleCode
+	})
+
+	return candidates
+}
@@ -0,0 +1,146 @@
+package planning
+
+import (
+	"testing"
+)
+
+func candidates(channels ...uint8) []Assignment {
+	assignments := make([]Assignment, 0)
+	for _, channel := range channels {
+		for code := uint8(9); code <= 12; code++ {
+			assignments = append(assignments, Assignment{Channel: channel, PreambleCode: code})
+		}
+	}
+	return assignments
+}
+
+func totalCost(nodes []Node, edges []Edge, assignments map[uint]Assignment) float64 {
+	cost := 0.0
+	for _, edge := range edges {
+		cost += Cost(assignments[edge.A], assignments[edge.B])
+	}
+	for _, node := range nodes {
+		if node.Current != nil && *node.Current != assignments[node.ID] {
+			cost += ChangeCost
+		}
+	}
+	return cost
+}
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Assignment
+		b        Assignment
+		expected float64
+	}{
+		{name: "same code", a: Assignment{Channel: 5, PreambleCode: 9}, b: Assignment{Channel: 5, PreambleCode: 9}, expected: SharedCodeCost},
+		{name: "same channel", a: Assignment{Channel: 5, PreambleCode: 9}, b: Assignment{Channel: 5, PreambleCode: 10}, expected: SharedChannelCost},
+		{name: "different channel", a: Assignment{Channel: 5, PreambleCode: 9}, b: Assignment{Channel: 9, PreambleCode: 9}, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if cost := Cost(test.a, test.b); cost != test.expected {
+				t.Errorf("cost = %f, want %f", cost, test.expected)
+			}
+		})
+	}
+}
+
+func TestAssignChannels(t *testing.T) {
+	current := &Assignment{Channel: 5, PreambleCode: 9}
+
+	t.Run("isolated clusters keep their assignment", func(t *testing.T) {
+		nodes := []Node{
+			{ID: 1, Current: current, Candidates: candidates(5, 9)},
+			{ID: 2, Current: current, Candidates: candidates(5, 9)},
+		}
+
+		assignments := AssignChannels(nodes, nil)
+		for _, node := range nodes {
+			if assignments[node.ID] != *current {
+				t.Errorf("cluster %d = %+v, want %+v", node.ID, assignments[node.ID], *current)
+			}
+		}
+	})
+
+	t.Run("new cluster without assignment gets the lowest candidate", func(t *testing.T) {
+		assignments := AssignChannels([]Node{{ID: 1, Candidates: candidates(9, 5)}}, nil)
+
+		if expected := (Assignment{Channel: 5, PreambleCode: 9}); assignments[1] != expected {
+			t.Errorf("cluster 1 = %+v, want %+v", assignments[1], expected)
+		}
+	})
+
+	t.Run("only one of two conflicting neighbours is retuned", func(t *testing.T) {
+		nodes := []Node{
+			{ID: 1, Current: current, Candidates: candidates(5, 9)},
+			{ID: 2, Current: current, Candidates: candidates(5, 9)},
+		}
+		edges := []Edge{{A: 1, B: 2}}
+
+		assignments := AssignChannels(nodes, edges)
+		if assignments[1].Channel == assignments[2].Channel {
+			t.Errorf("neighbours share channel %d", assignments[1].Channel)
+		}
+		if assignments[1] != *current && assignments[2] != *current {
+			t.Errorf("both clusters were retuned: %+v", assignments)
+		}
+	})
+
+	t.Run("neighbours on a single channel get different codes", func(t *testing.T) {
+		nodes := []Node{
+			{ID: 1, Current: current, Candidates: candidates(5)},
+			{ID: 2, Current: current, Candidates: candidates(5)},
+			{ID: 3, Current: current, Candidates: candidates(5)},
+		}
+		edges := []Edge{{A: 1, B: 2}, {A: 2, B: 3}, {A: 1, B: 3}}
+
+		assignments := AssignChannels(nodes, edges)
+		for _, edge := range edges {
+			if assignments[edge.A] == assignments[edge.B] {
+				t.Errorf("clusters %d and %d share %+v", edge.A, edge.B, assignments[edge.A])
+			}
+		}
+		if cost := totalCost(nodes, edges, assignments); cost != 3*SharedChannelCost+2*ChangeCost {
+			t.Errorf("cost = %f, want %f", cost, 3*SharedChannelCost+2*ChangeCost)
+		}
+	})
+
+	t.Run("clusters without candidates keep their assignment and constrain their neighbours", func(t *testing.T) {
+		nodes := []Node{
+			{ID: 1, Current: current},
+			{ID: 2, Current: current, Candidates: candidates(5)},
+			{ID: 3},
+		}
+		edges := []Edge{{A: 1, B: 2}, {A: 2, B: 3}}
+
+		assignments := AssignChannels(nodes, edges)
+		if assignments[1] != *current {
+			t.Errorf("cluster 1 = %+v, want %+v", assignments[1], *current)
+		}
+		if assignments[2] == *current {
+			t.Errorf("cluster 2 kept the code of its fixed neighbour")
+		}
+		if _, exists := assignments[3]; exists {
+			t.Errorf("cluster 3 without candidates or assignment was assigned %+v", assignments[3])
+		}
+	})
+
+	t.Run("a ring of more clusters than channels avoids shared codes", func(t *testing.T) {
+		nodes := make([]Node, 0)
+		edges := make([]Edge, 0)
+		for id := uint(1); id <= 6; id++ {
+			nodes = append(nodes, Node{ID: id, Current: current, Candidates: candidates(5, 9)})
+			edges = append(edges, Edge{A: id, B: id%6 + 1})
+		}
+
+		assignments := AssignChannels(nodes, edges)
+		for _, edge := range edges {
+			if assignments[edge.A].Channel == assignments[edge.B].Channel {
+				t.Errorf("clusters %d and %d share channel %d", edge.A, edge.B, assignments[edge.A].Channel)
+			}
+		}
+	})
+}
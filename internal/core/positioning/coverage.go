@@ -0,0 +1,109 @@
+package positioning
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrInvalidCoverageArea  = errors.New("coverage area must have positive size and resolution")
+	ErrCoverageGridTooLarge = errors.New("coverage grid has too many cells")
+)
+
+// maxCoverageCells bounds the grid regardless of configuration so the cell
+// count always fits into an int and the grid into memory.
+const maxCoverageCells = 1 << 24
+
+type CoverageOptions struct {
+	MinX       float64
+	MinY       float64
+	MaxX       float64
+	MaxY       float64
+	Resolution float64
+	Height     float64
+	// MaxRange limits which anchors are considered visible from a cell; zero disables the limit.
+	MaxRange  float64
+	Dimension int
+}
+
+// CoverageGrid holds row-major cell values; row 0 is at MinY and column 0 at
+// MinX, each sample taken at the cell centre.
+type CoverageGrid struct {
+	Columns int
+	Rows    int
+	HDOP    [][]float64
+	Visible [][]int
+}
+
+// Cells returns the number of grid cells. It is computed in floating point
+// so tiny resolutions cannot overflow.
+func (o CoverageOptions) Cells() float64 {
+	return math.Ceil((o.MaxX-o.MinX)/o.Resolution) * math.Ceil((o.MaxY-o.MinY)/o.Resolution)
+}
+
+func (o CoverageOptions) Size() (int, int, error) {
+	if !(o.Resolution > 0) || !(o.MaxX > o.MinX) || !(o.MaxY > o.MinY) {
+		return 0, 0, ErrInvalidCoverageArea
+	}
+
+	if cells := o.Cells(); !(cells <= maxCoverageCells) {
+		return 0, 0, ErrCoverageGridTooLarge
+	}
+
+	columns := int(math.Ceil((o.MaxX - o.MinX) / o.Resolution))
+	rows := int(math.Ceil((o.MaxY - o.MinY) / o.Resolution))
+	return columns, rows, nil
+}
+
+// ComputeCoverage evaluates the expected HDOP and the number of visible
+// anchors on a regular grid. Cells with too few visible anchors for a fix
+// report MaxDOP.
+func ComputeCoverage(anchors []Vector, options CoverageOptions) (*CoverageGrid, error) {
+	columns, rows, err := options.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	dimension := options.Dimension
+	if dimension != 3 {
+		dimension = 2
+	}
+
+	grid := &CoverageGrid{
+		Columns: columns,
+		Rows:    rows,
+		HDOP:    make([][]float64, rows),
+		Visible: make([][]int, rows),
+	}
+
+	visible := make([]Vector, 0, len(anchors))
+	for row := 0; row < rows; row++ {
+		grid.HDOP[row] = make([]float64, columns)
+		grid.Visible[row] = make([]int, columns)
+
+		for column := 0; column < columns; column++ {
+			point := Vector{
+				X: options.MinX + (float64(column)+0.5)*options.Resolution,
+				Y: options.MinY + (float64(row)+0.5)*options.Resolution,
+				Z: options.Height,
+			}
+
+			visible = visible[:0]
+			for _, anchor := range anchors {
+				if options.MaxRange <= 0 || point.Sub(anchor).Norm() <= options.MaxRange {
+					visible = append(visible, anchor)
+				}
+			}
+
+			grid.Visible[row][column] = len(visible)
+			if len(visible) < dimension+1 {
+				grid.HDOP[row][column] = MaxDOP
+				continue
+			}
+
+			grid.HDOP[row][column] = ComputeDOP(visible, point, dimension).HDOP
+		}
+	}
+
+	return grid, nil
+}
@@ -0,0 +1,80 @@
+package positioning
+
+import (
+	"gps-no-server/internal/common/linalg"
+	"math"
+)
+
+// MaxDOP caps dilution of precision values for degenerate geometries so they
+// stay representable in JSON and NMEA output.
+const MaxDOP = 99.9
+
+// DOP describes how the anchor geometry amplifies ranging errors. Two-way
+// ranging has no receiver clock term, so GDOP equals the position DOP.
+type DOP struct {
+	GDOP float64
+	HDOP float64
+	VDOP float64
+	// XDOP, YDOP and XYCofactor describe the horizontal error ellipse in the
+	// solver's frame, so it can be rotated to east and north.
+	XDOP       float64
+	YDOP       float64
+	XYCofactor float64
+}
+
+// ComputeDOP evaluates the geometry matrix built from unit vectors between
+// the position and each anchor. VDOP is zero for 2D solutions.
+func ComputeDOP(anchors []Vector, position Vector, dimension int) DOP {
+	if len(anchors) < dimension {
+		return DOP{GDOP: MaxDOP, HDOP: MaxDOP, VDOP: verticalCap(dimension), XDOP: MaxDOP, YDOP: MaxDOP}
+	}
+
+	geometry := linalg.NewMatrix(len(anchors), dimension)
+	for i, anchor := range anchors {
+		delta := position.Sub(anchor)
+		if dimension == 2 {
+			delta.Z = 0
+		}
+
+		distance := delta.Norm()
+		if distance < 1e-9 {
+			distance = 1e-9
+		}
+
+		for k := 0; k < dimension; k++ {
+			geometry.Set(i, k, delta.component(k)/distance)
+		}
+	}
+
+	cofactor, err := geometry.T().Mul(geometry).Inverse()
+	if err != nil {
+		return DOP{GDOP: MaxDOP, HDOP: MaxDOP, VDOP: verticalCap(dimension), XDOP: MaxDOP, YDOP: MaxDOP}
+	}
+
+	dop := DOP{
+		GDOP:       capDOP(math.Sqrt(cofactor.Trace())),
+		HDOP:       capDOP(math.Sqrt(cofactor.At(0, 0) + cofactor.At(1, 1))),
+		XDOP:       capDOP(math.Sqrt(cofactor.At(0, 0))),
+		YDOP:       capDOP(math.Sqrt(cofactor.At(1, 1))),
+		XYCofactor: cofactor.At(0, 1),
+	}
+	if dimension == 3 {
+		dop.VDOP = capDOP(math.Sqrt(cofactor.At(2, 2)))
+	}
+
+	return dop
+}
+
+func verticalCap(dimension int) float64 {
+	if dimension == 3 {
+		return MaxDOP
+	}
+	return 0
+}
+
+func capDOP(value float64) float64 {
+	if math.IsNaN(value) || value > MaxDOP {
+		return MaxDOP
+	}
+	return value
+}
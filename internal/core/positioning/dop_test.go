@@ -0,0 +1,71 @@
+package positioning
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeDOP(t *testing.T) {
+	tests := []struct {
+		name      string
+		anchors   []Vector
+		position  Vector
+		dimension int
+		expected  DOP
+	}{
+		{
+			name:      "2D symmetric cross",
+			anchors:   []Vector{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}},
+			dimension: 2,
+			expected:  DOP{GDOP: 1, HDOP: 1, XDOP: math.Sqrt(0.5), YDOP: math.Sqrt(0.5)},
+		},
+		{
+			name:      "2D with a single anchor along y",
+			anchors:   []Vector{{X: 1}, {X: -1}, {Y: 1}},
+			dimension: 2,
+			expected:  DOP{GDOP: math.Sqrt(1.5), HDOP: math.Sqrt(1.5), XDOP: math.Sqrt(0.5), YDOP: 1},
+		},
+		{
+			name:      "3D octahedron",
+			anchors:   []Vector{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}, {Z: 1}, {Z: -1}},
+			dimension: 3,
+			expected:  DOP{GDOP: math.Sqrt(1.5), HDOP: 1, VDOP: math.Sqrt(0.5), XDOP: math.Sqrt(0.5), YDOP: math.Sqrt(0.5)},
+		},
+		{
+			name:      "2D collinear anchors",
+			anchors:   []Vector{{X: 1}, {X: 2}, {X: 3}},
+			position:  Vector{X: -1},
+			dimension: 2,
+			expected:  DOP{GDOP: MaxDOP, HDOP: MaxDOP, XDOP: MaxDOP, YDOP: MaxDOP},
+		},
+		{
+			name:      "3D with too few anchors",
+			anchors:   []Vector{{X: 1}, {Y: 1}},
+			dimension: 3,
+			expected:  DOP{GDOP: MaxDOP, HDOP: MaxDOP, VDOP: MaxDOP, XDOP: MaxDOP, YDOP: MaxDOP},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dop := ComputeDOP(test.anchors, test.position, test.dimension)
+
+			if math.Abs(dop.GDOP-test.expected.GDOP) > 1e-9 ||
+				math.Abs(dop.HDOP-test.expected.HDOP) > 1e-9 ||
+				math.Abs(dop.VDOP-test.expected.VDOP) > 1e-9 ||
+				math.Abs(dop.XDOP-test.expected.XDOP) > 1e-9 ||
+				math.Abs(dop.YDOP-test.expected.YDOP) > 1e-9 {
+				t.Errorf("dop = %+v, want %+v", dop, test.expected)
+			}
+		})
+	}
+}
+
+func TestComputeDOPGeometry(t *testing.T) {
+	good := ComputeDOP([]Vector{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}, Vector{X: 5, Y: 5}, 2)
+	poor := ComputeDOP([]Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0.5, Y: 0.5}}, Vector{X: 20, Y: 20}, 2)
+
+	if good.HDOP >= poor.HDOP {
+		t.Errorf("HDOP inside the anchors = %f, want below %f outside", good.HDOP, poor.HDOP)
+	}
+}
@@ -0,0 +1,266 @@
+package positioning
+
+import (
+	"errors"
+	"gps-no-server/internal/common/linalg"
+	"math"
+)
+
+var (
+	ErrNotEnoughAnchors = errors.New("not enough anchors to solve position")
+	ErrNoConvergence    = errors.New("position solver did not converge")
+	ErrSingularGeometry = errors.New("anchor geometry does not determine a position")
+)
+
+type Vector struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+func (v Vector) Sub(other Vector) Vector {
+	return Vector{X: v.X - other.X, Y: v.Y - other.Y, Z: v.Z - other.Z}
+}
+
+func (v Vector) Norm() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+func (v Vector) component(i int) float64 {
+	switch i {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	default:
+		return v.Z
+	}
+}
+
+type Measurement struct {
+	AnchorID uint
+	Anchor   Vector
+	Distance float64
+}
+
+type Solution struct {
+	Position   Vector
+	Dimension  int
+	Residual   float64
+	Quality    float64
+	Iterations int
+	AnchorIDs  []uint
+	DOP        DOP
+}
+
+type SolverOptions struct {
+	Dimension     int
+	MaxIterations int
+	Tolerance     float64
+	// MinVerticalSpread is the anchor height spread required before a 3D solve is attempted.
+	MinVerticalSpread float64
+}
+
+func DefaultSolverOptions() SolverOptions {
+	return SolverOptions{
+		Dimension:         0,
+		MaxIterations:     50,
+		Tolerance:         1e-6,
+		MinVerticalSpread: 0.5,
+	}
+}
+
+// Solve computes a least-squares position from anchor distances. A linearised
+// solution provides the starting point for a Gauss-Newton refinement.
+func Solve(measurements []Measurement, options SolverOptions) (*Solution, error) {
+	dimension := resolveDimension(measurements, options)
+	if len(measurements) < dimension+1 {
+		return nil, ErrNotEnoughAnchors
+	}
+
+	estimate, err := linearEstimate(measurements, dimension)
+	if err != nil {
+		estimate = centroid(measurements)
+	}
+	if dimension == 2 {
+		estimate.Z = centroid(measurements).Z
+	}
+
+	iterations := 0
+	lambda := 1e-3
+
+	for iterations < options.MaxIterations {
+		iterations++
+
+		jacobian, residuals := linearize(measurements, estimate, dimension)
+		jt := jacobian.T()
+		normal := jt.Mul(jacobian)
+		for i := 0; i < dimension; i++ {
+			normal.Set(i, i, normal.At(i, i)*(1+lambda))
+		}
+
+		inverse, err := normal.Inverse()
+		if errors.Is(err, linalg.ErrSingularMatrix) {
+			return nil, ErrSingularGeometry
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		step := inverse.MulVec(jt.MulVec(residuals))
+		candidate := estimate
+		candidate.X -= step[0]
+		candidate.Y -= step[1]
+		if dimension == 3 {
+			candidate.Z -= step[2]
+		}
+
+		if rms(measurements, candidate, dimension) <= rms(measurements, estimate, dimension) {
+			estimate = candidate
+			lambda /= 10
+		} else {
+			lambda *= 10
+		}
+
+		stepNorm := 0.0
+		for _, s := range step {
+			stepNorm += s * s
+		}
+		if math.Sqrt(stepNorm) < options.Tolerance {
+			break
+		}
+	}
+
+	if math.IsNaN(estimate.X) || math.IsNaN(estimate.Y) || math.IsNaN(estimate.Z) {
+		return nil, ErrNoConvergence
+	}
+
+	anchorIDs := make([]uint, 0, len(measurements))
+	anchors := make([]Vector, 0, len(measurements))
+	for _, m := range measurements {
+		anchorIDs = append(anchorIDs, m.AnchorID)
+		anchors = append(anchors, m.Anchor)
+	}
+
+	residual := rms(measurements, estimate, dimension)
+
+	return &Solution{
+		Position:   estimate,
+		Dimension:  dimension,
+		Residual:   residual,
+		Quality:    1 / (1 + residual),
+		Iterations: iterations,
+		AnchorIDs:  anchorIDs,
+		DOP:        ComputeDOP(anchors, estimate, dimension),
+	}, nil
+}
+
+func resolveDimension(measurements []Measurement, options SolverOptions) int {
+	if options.Dimension == 2 || options.Dimension == 3 {
+		return options.Dimension
+	}
+
+	if len(measurements) < 4 {
+		return 2
+	}
+
+	minZ, maxZ := math.Inf(1), math.Inf(-1)
+	for _, m := range measurements {
+		minZ = math.Min(minZ, m.Anchor.Z)
+		maxZ = math.Max(maxZ, m.Anchor.Z)
+	}
+
+	if maxZ-minZ < options.MinVerticalSpread {
+		return 2
+	}
+
+	return 3
+}
+
+// linearEstimate subtracts the first range equation from the others which
+// turns the problem into an ordinary linear least-squares system.
+func linearEstimate(measurements []Measurement, dimension int) (Vector, error) {
+	reference := measurements[0]
+	a := linalg.NewMatrix(len(measurements)-1, dimension)
+	b := make([]float64, len(measurements)-1)
+
+	refNorm := squaredNorm(reference.Anchor, dimension)
+	for i, m := range measurements[1:] {
+		for k := 0; k < dimension; k++ {
+			a.Set(i, k, 2*(m.Anchor.component(k)-reference.Anchor.component(k)))
+		}
+		b[i] = reference.Distance*reference.Distance - m.Distance*m.Distance + squaredNorm(m.Anchor, dimension) - refNorm
+	}
+
+	at := a.T()
+	inverse, err := at.Mul(a).Inverse()
+	if err != nil {
+		return Vector{}, err
+	}
+
+	x := inverse.MulVec(at.MulVec(b))
+	result := Vector{X: x[0], Y: x[1]}
+	if dimension == 3 {
+		result.Z = x[2]
+	}
+
+	return result, nil
+}
+
+func linearize(measurements []Measurement, estimate Vector, dimension int) (*linalg.Matrix, []float64) {
+	jacobian := linalg.NewMatrix(len(measurements), dimension)
+	residuals := make([]float64, len(measurements))
+
+	for i, m := range measurements {
+		delta := estimate.Sub(m.Anchor)
+		if dimension == 2 {
+			delta.Z = 0
+		}
+
+		distance := delta.Norm()
+		if distance < 1e-9 {
+			distance = 1e-9
+		}
+
+		for k := 0; k < dimension; k++ {
+			jacobian.Set(i, k, delta.component(k)/distance)
+		}
+		residuals[i] = distance - m.Distance
+	}
+
+	return jacobian, residuals
+}
+
+func rms(measurements []Measurement, estimate Vector, dimension int) float64 {
+	sum := 0.0
+	for _, m := range measurements {
+		delta := estimate.Sub(m.Anchor)
+		if dimension == 2 {
+			delta.Z = 0
+		}
+		r := delta.Norm() - m.Distance
+		sum += r * r
+	}
+
+	return math.Sqrt(sum / float64(len(measurements)))
+}
+
+func centroid(measurements []Measurement) Vector {
+	var c Vector
+	for _, m := range measurements {
+		c.X += m.Anchor.X
+		c.Y += m.Anchor.Y
+		c.Z += m.Anchor.Z
+	}
+
+	n := float64(len(measurements))
+	return Vector{X: c.X / n, Y: c.Y / n, Z: c.Z / n}
+}
+
+func squaredNorm(v Vector, dimension int) float64 {
+	sum := 0.0
+	for k := 0; k < dimension; k++ {
+		sum += v.component(k) * v.component(k)
+	}
+	return sum
+}
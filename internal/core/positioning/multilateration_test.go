@@ -0,0 +1,136 @@
+package positioning
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func measurementsTo(position Vector, anchors []Vector) []Measurement {
+	measurements := make([]Measurement, len(anchors))
+	for i, anchor := range anchors {
+		measurements[i] = Measurement{
+			AnchorID: uint(i + 1),
+			Anchor:   anchor,
+			Distance: position.Sub(anchor).Norm(),
+		}
+	}
+	return measurements
+}
+
+func TestSolve(t *testing.T) {
+	square := []Vector{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	cube := []Vector{{X: 0, Y: 0, Z: 0}, {X: 10, Y: 0, Z: 3}, {X: 10, Y: 10, Z: 0}, {X: 0, Y: 10, Z: 3}, {X: 5, Y: 5, Z: 2.5}}
+
+	tests := []struct {
+		name      string
+		anchors   []Vector
+		position  Vector
+		dimension int
+		expected  int
+	}{
+		{name: "2D inside square", anchors: square, position: Vector{X: 3, Y: 4}, expected: 2},
+		{name: "2D outside square", anchors: square, position: Vector{X: 14, Y: -2}, expected: 2},
+		{name: "2D with three anchors", anchors: square[:3], position: Vector{X: 7, Y: 2}, expected: 2},
+		{name: "3D with vertical spread", anchors: cube, position: Vector{X: 4, Y: 6, Z: 1.5}, expected: 3},
+		{name: "forced 2D", anchors: cube, position: Vector{X: 4, Y: 6, Z: 1.5}, dimension: 2, expected: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := DefaultSolverOptions()
+			options.Dimension = test.dimension
+
+			measurements := measurementsTo(test.position, test.anchors)
+			if test.dimension == 2 {
+				for i := range measurements {
+					delta := test.position.Sub(measurements[i].Anchor)
+					measurements[i].Distance = math.Hypot(delta.X, delta.Y)
+				}
+			}
+
+			solution, err := Solve(measurements, options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if solution.Dimension != test.expected {
+				t.Errorf("dimension = %d, want %d", solution.Dimension, test.expected)
+			}
+
+			errorX := math.Abs(solution.Position.X - test.position.X)
+			errorY := math.Abs(solution.Position.Y - test.position.Y)
+			if errorX > 1e-4 || errorY > 1e-4 {
+				t.Errorf("position = (%f, %f), want (%f, %f)", solution.Position.X, solution.Position.Y, test.position.X, test.position.Y)
+			}
+
+			if test.expected == 3 && math.Abs(solution.Position.Z-test.position.Z) > 1e-4 {
+				t.Errorf("z = %f, want %f", solution.Position.Z, test.position.Z)
+			}
+
+			if solution.Residual > 1e-4 {
+				t.Errorf("residual = %f, want about 0", solution.Residual)
+			}
+
+			if len(solution.AnchorIDs) != len(test.anchors) {
+				t.Errorf("anchors = %d, want %d", len(solution.AnchorIDs), len(test.anchors))
+			}
+		})
+	}
+}
+
+func TestSolveNoisyRanges(t *testing.T) {
+	anchors := []Vector{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	position := Vector{X: 5, Y: 5}
+
+	measurements := measurementsTo(position, anchors)
+	noise := []float64{0.05, -0.03, 0.04, -0.05}
+	for i := range measurements {
+		measurements[i].Distance += noise[i]
+	}
+
+	solution, err := Solve(measurements, DefaultSolverOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if distance := solution.Position.Sub(position).Norm(); distance > 0.1 {
+		t.Errorf("position is %f m off, want below 0.1 m", distance)
+	}
+	if solution.Residual <= 0 || solution.Residual > 0.1 {
+		t.Errorf("residual = %f, want within (0, 0.1]", solution.Residual)
+	}
+}
+
+func TestSolveNotEnoughAnchors(t *testing.T) {
+	tests := []struct {
+		name      string
+		anchors   []Vector
+		dimension int
+	}{
+		{name: "2D with two anchors", anchors: []Vector{{X: 0}, {X: 10}}},
+		{name: "3D with three anchors", anchors: []Vector{{X: 0}, {X: 10}, {Y: 10, Z: 3}}, dimension: 3},
+		{name: "no anchors", anchors: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := DefaultSolverOptions()
+			options.Dimension = test.dimension
+
+			_, err := Solve(measurementsTo(Vector{X: 1, Y: 1}, test.anchors), options)
+			if !errors.Is(err, ErrNotEnoughAnchors) {
+				t.Errorf("error = %v, want %v", err, ErrNotEnoughAnchors)
+			}
+		})
+	}
+}
+
+func TestSolveSingularGeometry(t *testing.T) {
+	anchors := []Vector{{X: 2, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 2}}
+	measurements := measurementsTo(Vector{X: 5, Y: 6}, anchors)
+
+	if _, err := Solve(measurements, DefaultSolverOptions()); !errors.Is(err, ErrSingularGeometry) {
+		t.Errorf("error = %v, want %v", err, ErrSingularGeometry)
+	}
+}
@@ -0,0 +1,386 @@
+package positioning
+
+import (
+	"errors"
+	"gps-no-server/internal/common/linalg"
+	"math"
+)
+
+var (
+	ErrDisconnectedAnchors = errors.New("anchor ranging graph is not connected")
+	ErrNoPinnedAnchors     = errors.New("at least one pinned anchor is required")
+)
+
+type LocalizationOptions struct {
+	Dimension            int
+	RefinementIterations int
+}
+
+type LocalizationResult struct {
+	Positions  []Vector
+	Errors     []float64
+	Neighbours []int
+	Residual   float64
+	Mirrored   bool
+	Ambiguous  bool
+}
+
+// Localize estimates relative anchor coordinates from a (possibly sparse)
+// symmetric distance matrix. Missing distances are marked with NaN. The
+// result is expressed in the frame defined by the pinned anchors.
+func Localize(distances [][]float64, pinned map[int]Vector, options LocalizationOptions) (*LocalizationResult, error) {
+	n := len(distances)
+	dimension := options.Dimension
+	if dimension != 3 {
+		dimension = 2
+	}
+
+	if n < dimension+1 {
+		return nil, ErrNotEnoughAnchors
+	}
+
+	if len(pinned) == 0 {
+		return nil, ErrNoPinnedAnchors
+	}
+
+	completed, err := completeDistances(distances)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := ClassicalMDS(completed, dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	positions = refine(positions, distances, dimension, options.RefinementIterations)
+
+	aligned, mirrored, ambiguous := align(positions, pinned, dimension)
+
+	result := &LocalizationResult{
+		Positions:  aligned,
+		Errors:     make([]float64, n),
+		Neighbours: make([]int, n),
+		Mirrored:   mirrored,
+		Ambiguous:  ambiguous,
+	}
+
+	total, count := 0.0, 0
+	for i := 0; i < n; i++ {
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			if i == j || math.IsNaN(distances[i][j]) {
+				continue
+			}
+
+			r := aligned[i].Sub(aligned[j]).Norm() - distances[i][j]
+			sum += r * r
+			result.Neighbours[i]++
+		}
+
+		if result.Neighbours[i] > 0 {
+			result.Errors[i] = math.Sqrt(sum / float64(result.Neighbours[i]))
+		}
+		total += sum
+		count += result.Neighbours[i]
+	}
+
+	if count > 0 {
+		result.Residual = math.Sqrt(total / float64(count))
+	}
+
+	return result, nil
+}
+
+// ClassicalMDS embeds a complete distance matrix into the requested number of
+// dimensions using the double-centred Gram matrix.
+func ClassicalMDS(distances [][]float64, dimension int) ([]Vector, error) {
+	n := len(distances)
+	squared := linalg.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			squared.Set(i, j, distances[i][j]*distances[i][j])
+		}
+	}
+
+	centering := linalg.Identity(n).Sub(constantMatrix(n, 1/float64(n)))
+	gram := centering.Mul(squared).Mul(centering).Scale(-0.5)
+
+	values, vectors := gram.SymmetricEigen()
+
+	positions := make([]Vector, n)
+	for k := 0; k < dimension && k < n; k++ {
+		scale := math.Sqrt(math.Max(values[k], 0))
+		for i := 0; i < n; i++ {
+			coordinate := vectors.At(i, k) * scale
+			switch k {
+			case 0:
+				positions[i].X = coordinate
+			case 1:
+				positions[i].Y = coordinate
+			case 2:
+				positions[i].Z = coordinate
+			}
+		}
+	}
+
+	return positions, nil
+}
+
+// completeDistances fills missing entries with shortest-path estimates so
+// the matrix can seed MDS. Measured entries are left untouched.
+func completeDistances(distances [][]float64) ([][]float64, error) {
+	n := len(distances)
+	completed := make([][]float64, n)
+	for i := range distances {
+		completed[i] = make([]float64, n)
+		for j := range distances[i] {
+			switch {
+			case i == j:
+				completed[i][j] = 0
+			case math.IsNaN(distances[i][j]):
+				completed[i][j] = math.Inf(1)
+			default:
+				completed[i][j] = distances[i][j]
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if via := completed[i][k] + completed[k][j]; via < completed[i][j] {
+					completed[i][j] = via
+				}
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if math.IsInf(completed[i][j], 1) {
+				return nil, ErrDisconnectedAnchors
+			}
+		}
+	}
+
+	return completed, nil
+}
+
+// refine reduces the stress over measured distances with per-anchor
+// majorization updates.
+func refine(positions []Vector, distances [][]float64, dimension int, iterations int) []Vector {
+	if iterations <= 0 {
+		iterations = 200
+	}
+
+	n := len(positions)
+	current := append([]Vector(nil), positions...)
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		maxStep := 0.0
+
+		for i := 0; i < n; i++ {
+			var next Vector
+			weight := 0.0
+
+			for j := 0; j < n; j++ {
+				if i == j || math.IsNaN(distances[i][j]) {
+					continue
+				}
+
+				delta := current[i].Sub(current[j])
+				norm := delta.Norm()
+				if norm < 1e-9 {
+					continue
+				}
+
+				factor := distances[i][j] / norm
+				next.X += current[j].X + factor*delta.X
+				next.Y += current[j].Y + factor*delta.Y
+				next.Z += current[j].Z + factor*delta.Z
+				weight++
+			}
+
+			if weight == 0 {
+				continue
+			}
+
+			next = Vector{X: next.X / weight, Y: next.Y / weight, Z: next.Z / weight}
+			if dimension == 2 {
+				next.Z = 0
+			}
+
+			maxStep = math.Max(maxStep, next.Sub(current[i]).Norm())
+			current[i] = next
+		}
+
+		if maxStep < 1e-6 {
+			break
+		}
+	}
+
+	return current
+}
+
+func align(positions []Vector, pinned map[int]Vector, dimension int) ([]Vector, bool, bool) {
+	indices := make([]int, 0, len(pinned))
+	for index := range pinned {
+		indices = append(indices, index)
+	}
+
+	if len(indices) == 1 {
+		offset := pinned[indices[0]].Sub(positions[indices[0]])
+		return translate(positions, offset), false, true
+	}
+
+	best, bestError := fitRigid(positions, pinned, indices, dimension)
+
+	mirrored := mirror(positions, dimension)
+	candidate, candidateError := fitRigid(mirrored, pinned, indices, dimension)
+
+	ambiguous := len(indices) < dimension+1 || math.Abs(candidateError-bestError) < 1e-6
+	if candidateError < bestError-1e-6 {
+		return candidate, true, ambiguous
+	}
+
+	return best, false, ambiguous
+}
+
+func fitRigid(positions []Vector, pinned map[int]Vector, indices []int, dimension int) ([]Vector, float64) {
+	var sourceCentroid, targetCentroid Vector
+	for _, index := range indices {
+		sourceCentroid = add(sourceCentroid, positions[index])
+		targetCentroid = add(targetCentroid, pinned[index])
+	}
+	count := float64(len(indices))
+	sourceCentroid = scale(sourceCentroid, 1/count)
+	targetCentroid = scale(targetCentroid, 1/count)
+
+	var rotation *linalg.Matrix
+	if dimension == 2 {
+		rotation = rotation2D(positions, pinned, indices, sourceCentroid, targetCentroid)
+	} else {
+		rotation = rotation3D(positions, pinned, indices, sourceCentroid, targetCentroid)
+	}
+
+	result := make([]Vector, len(positions))
+	for i, position := range positions {
+		centered := position.Sub(sourceCentroid)
+		rotated := rotation.MulVec([]float64{centered.X, centered.Y, centered.Z})
+		result[i] = add(Vector{X: rotated[0], Y: rotated[1], Z: rotated[2]}, targetCentroid)
+		if dimension == 2 {
+			result[i].Z = pinnedHeight(pinned, indices)
+		}
+	}
+
+	residual := 0.0
+	for _, index := range indices {
+		target := pinned[index]
+		delta := result[index].Sub(target)
+		if dimension == 2 {
+			delta.Z = 0
+		}
+		residual += delta.Norm() * delta.Norm()
+	}
+
+	return result, math.Sqrt(residual / count)
+}
+
+func rotation2D(positions []Vector, pinned map[int]Vector, indices []int, sourceCentroid, targetCentroid Vector) *linalg.Matrix {
+	cross, dot := 0.0, 0.0
+	for _, index := range indices {
+		source := positions[index].Sub(sourceCentroid)
+		target := pinned[index].Sub(targetCentroid)
+		dot += source.X*target.X + source.Y*target.Y
+		cross += source.X*target.Y - source.Y*target.X
+	}
+
+	angle := math.Atan2(cross, dot)
+	return linalg.NewMatrixFromRows([][]float64{
+		{math.Cos(angle), -math.Sin(angle), 0},
+		{math.Sin(angle), math.Cos(angle), 0},
+		{0, 0, 1},
+	})
+}
+
+// rotation3D solves the absolute orientation problem with Horn's quaternion
+// method.
+func rotation3D(positions []Vector, pinned map[int]Vector, indices []int, sourceCentroid, targetCentroid Vector) *linalg.Matrix {
+	var s [3][3]float64
+	for _, index := range indices {
+		source := positions[index].Sub(sourceCentroid)
+		target := pinned[index].Sub(targetCentroid)
+		a := [3]float64{source.X, source.Y, source.Z}
+		b := [3]float64{target.X, target.Y, target.Z}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				s[i][j] += a[i] * b[j]
+			}
+		}
+	}
+
+	n := linalg.NewMatrixFromRows([][]float64{
+		{s[0][0] + s[1][1] + s[2][2], s[1][2] - s[2][1], s[2][0] - s[0][2], s[0][1] - s[1][0]},
+		{s[1][2] - s[2][1], s[0][0] - s[1][1] - s[2][2], s[0][1] + s[1][0], s[2][0] + s[0][2]},
+		{s[2][0] - s[0][2], s[0][1] + s[1][0], -s[0][0] + s[1][1] - s[2][2], s[1][2] + s[2][1]},
+		{s[0][1] - s[1][0], s[2][0] + s[0][2], s[1][2] + s[2][1], -s[0][0] - s[1][1] + s[2][2]},
+	})
+
+	_, vectors := n.SymmetricEigen()
+	q := vectors.Col(0)
+	w, x, y, z := q[0], q[1], q[2], q[3]
+
+	return linalg.NewMatrixFromRows([][]float64{
+		{w*w + x*x - y*y - z*z, 2 * (x*y - w*z), 2 * (x*z + w*y)},
+		{2 * (x*y + w*z), w*w - x*x + y*y - z*z, 2 * (y*z - w*x)},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), w*w - x*x - y*y + z*z},
+	})
+}
+
+func pinnedHeight(pinned map[int]Vector, indices []int) float64 {
+	sum := 0.0
+	for _, index := range indices {
+		sum += pinned[index].Z
+	}
+	return sum / float64(len(indices))
+}
+
+func mirror(positions []Vector, dimension int) []Vector {
+	result := make([]Vector, len(positions))
+	for i, position := range positions {
+		result[i] = position
+		if dimension == 2 {
+			result[i].Y = -position.Y
+		} else {
+			result[i].Z = -position.Z
+		}
+	}
+	return result
+}
+
+func translate(positions []Vector, offset Vector) []Vector {
+	result := make([]Vector, len(positions))
+	for i, position := range positions {
+		result[i] = add(position, offset)
+	}
+	return result
+}
+
+func constantMatrix(n int, value float64) *linalg.Matrix {
+	m := linalg.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.Set(i, j, value)
+		}
+	}
+	return m
+}
+
+func add(a, b Vector) Vector {
+	return Vector{X: a.X + b.X, Y: a.Y + b.Y, Z: a.Z + b.Z}
+}
+
+func scale(v Vector, factor float64) Vector {
+	return Vector{X: v.X * factor, Y: v.Y * factor, Z: v.Z * factor}
+}
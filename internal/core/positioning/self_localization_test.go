@@ -0,0 +1,136 @@
+package positioning
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func distanceMatrix(positions []Vector) [][]float64 {
+	distances := make([][]float64, len(positions))
+	for i := range positions {
+		distances[i] = make([]float64, len(positions))
+		for j := range positions {
+			distances[i][j] = positions[i].Sub(positions[j]).Norm()
+		}
+	}
+	return distances
+}
+
+func TestClassicalMDS(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []Vector
+		dimension int
+	}{
+		{
+			name:      "2D rectangle with centre",
+			positions: []Vector{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 3}, {X: 0, Y: 3}, {X: 2, Y: 1.5}},
+			dimension: 2,
+		},
+		{
+			name:      "3D tetrahedron",
+			positions: []Vector{{X: 0, Y: 0, Z: 0}, {X: 5, Y: 0, Z: 0}, {X: 0, Y: 5, Z: 0}, {X: 0, Y: 0, Z: 5}},
+			dimension: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			distances := distanceMatrix(test.positions)
+
+			embedded, err := ClassicalMDS(distances, test.dimension)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i := range embedded {
+				for j := range embedded {
+					if got := embedded[i].Sub(embedded[j]).Norm(); math.Abs(got-distances[i][j]) > 1e-6 {
+						t.Errorf("distance %d-%d = %f, want %f", i, j, got, distances[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	positions := []Vector{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 8}, {X: 0, Y: 8}, {X: 4, Y: 3}}
+
+	sparse := distanceMatrix(positions)
+	sparse[0][2], sparse[2][0] = math.NaN(), math.NaN()
+
+	tests := []struct {
+		name      string
+		distances [][]float64
+		pinned    []int
+		ambiguous bool
+	}{
+		{name: "complete distances", distances: distanceMatrix(positions), pinned: []int{0, 1, 3}},
+		{name: "missing distance", distances: sparse, pinned: []int{0, 1, 3}},
+		{name: "two pinned anchors", distances: distanceMatrix(positions), pinned: []int{0, 1}, ambiguous: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pinned := make(map[int]Vector, len(test.pinned))
+			for _, index := range test.pinned {
+				pinned[index] = positions[index]
+			}
+
+			result, err := Localize(test.distances, pinned, LocalizationOptions{Dimension: 2})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Ambiguous != test.ambiguous {
+				t.Errorf("ambiguous = %t, want %t", result.Ambiguous, test.ambiguous)
+			}
+			if result.Residual > 1e-3 {
+				t.Errorf("residual = %f, want about 0", result.Residual)
+			}
+
+			// With two pinned anchors the layout may be mirrored across
+			// them, which only the residual can check.
+			if test.ambiguous {
+				return
+			}
+
+			for i, position := range result.Positions {
+				if distance := position.Sub(positions[i]).Norm(); distance > 1e-3 {
+					t.Errorf("anchor %d = %+v, want %+v", i, position, positions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLocalizeErrors(t *testing.T) {
+	positions := []Vector{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 8}, {X: 0, Y: 8}}
+
+	disconnected := distanceMatrix(positions)
+	for _, pair := range [][2]int{{0, 2}, {0, 3}, {1, 2}, {1, 3}} {
+		disconnected[pair[0]][pair[1]], disconnected[pair[1]][pair[0]] = math.NaN(), math.NaN()
+	}
+
+	tests := []struct {
+		name      string
+		distances [][]float64
+		pinned    map[int]Vector
+		expected  error
+	}{
+		{name: "too few anchors", distances: distanceMatrix(positions[:2]), pinned: map[int]Vector{0: positions[0]}, expected: ErrNotEnoughAnchors},
+		{name: "no pinned anchors", distances: distanceMatrix(positions), pinned: map[int]Vector{}, expected: ErrNoPinnedAnchors},
+		{name: "disconnected graph", distances: disconnected, pinned: map[int]Vector{0: positions[0]}, expected: ErrDisconnectedAnchors},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Localize(test.distances, test.pinned, LocalizationOptions{Dimension: 2})
+			if !errors.Is(err, test.expected) {
+				t.Errorf("error = %v, want %v", err, test.expected)
+			}
+		})
+	}
+}
@@ -38,3 +38,22 @@ func (c *ClusterRepository) FindByMac(ctx context.Context, macAddress string, in
 
 	return &cluster, result.Error
 }
+
+func (c *ClusterRepository) FindByName(ctx context.Context, name string, includes map[string]bool) (*models.Cluster, error) {
+	var cluster models.Cluster
+	result := c.db.WithContext(ctx).Where("name = ?", name).First(&cluster)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &cluster, nil
+}
+
+// FindNames returns the names of all clusters including deleted ones, which
+// still hold on to their unique name.
+func (c *ClusterRepository) FindNames(ctx context.Context) ([]string, error) {
+	var names []string
+	result := c.db.WithContext(ctx).Unscoped().Model(&models.Cluster{}).Pluck("name", &names)
+	return names, result.Error
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+)
+
+type ConfigurationProfileRepository struct {
+	*BaseRepository[models.ConfigurationProfile]
+	db  *gorm.DB
+	log zerolog.Logger
+}
+
+func NewConfigurationProfileRepository(db *gorm.DB) *ConfigurationProfileRepository {
+	baseRepository := &BaseRepository[models.ConfigurationProfile]{
+		DB:         db,
+		Log:        logger.GetLogger("configuration-profile-repository"),
+		EntityName: "configuration-profile-repository",
+	}
+
+	return &ConfigurationProfileRepository{
+		BaseRepository: baseRepository,
+		db:             db,
+		log:            logger.GetLogger("configuration-profile-repository"),
+	}
+}
+
+func (c *ConfigurationProfileRepository) FindByName(ctx context.Context, name string) (*models.ConfigurationProfile, error) {
+	var profile models.ConfigurationProfile
+	result := c.db.WithContext(ctx).Where("name = ?", name).First(&profile)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &profile, nil
+}
+
+// DeleteUnreferenced deletes the profile unless a station configuration, a
+// cluster or an active rollout still uses it. It returns the number of
+// references that kept the profile, which is zero once it was deleted.
+func (c *ConfigurationProfileRepository) DeleteUnreferenced(ctx context.Context, profile *models.ConfigurationProfile) (int64, error) {
+	var references int64
+
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.ConfigurationProfile{}, profile.ID).Error; err != nil {
+			return err
+		}
+
+		counts := []*gorm.DB{
+			tx.Model(&models.StationConfiguration{}).Where("profile_id = ?", profile.ID),
+			tx.Model(&models.Cluster{}).Where("profile_id = ?", profile.ID),
+			tx.Model(&models.Rollout{}).Where("profile_id = ? AND status IN ?", profile.ID, []models.RolloutStatus{models.RolloutRunning, models.RolloutPaused}),
+		}
+		for _, query := range counts {
+			var count int64
+			if err := query.Count(&count).Error; err != nil {
+				return err
+			}
+			references += count
+		}
+
+		if references > 0 {
+			return nil
+		}
+
+		return tx.Delete(profile).Error
+	})
+
+	return references, err
+}
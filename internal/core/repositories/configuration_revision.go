@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gps-no-server/internal/common/actor"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+)
+
+type ConfigurationRevisionRepository struct {
+	db  *gorm.DB
+	log zerolog.Logger
+}
+
+func NewConfigurationRevisionRepository(db *gorm.DB) *ConfigurationRevisionRepository {
+	return &ConfigurationRevisionRepository{
+		db:  db,
+		log: logger.GetLogger("configuration-revision-repository"),
+	}
+}
+
+func (c *ConfigurationRevisionRepository) FindByConfiguration(ctx context.Context, configurationId uint) ([]*models.ConfigurationRevision, error) {
+	var revisions []*models.ConfigurationRevision
+	result := c.db.WithContext(ctx).
+		Where("station_configuration_id = ?", configurationId).
+		Order("version DESC").
+		Find(&revisions)
+	return revisions, result.Error
+}
+
+func (c *ConfigurationRevisionRepository) FindByVersion(ctx context.Context, configurationId uint, version uint) (*models.ConfigurationRevision, error) {
+	var revision models.ConfigurationRevision
+	result := c.db.WithContext(ctx).
+		Where("station_configuration_id = ? AND version = ?", configurationId, version).
+		First(&revision)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &revision, nil
+}
+
+// FindLatestVersions returns the newest revision version of every
+// configuration that has a history.
+func (c *ConfigurationRevisionRepository) FindLatestVersions(ctx context.Context, configurationIds []uint) (map[uint]uint, error) {
+	var rows []struct {
+		StationConfigurationID uint
+		Version                uint
+	}
+
+	versions := make(map[uint]uint, len(configurationIds))
+	if len(configurationIds) == 0 {
+		return versions, nil
+	}
+
+	result := c.db.WithContext(ctx).Model(&models.ConfigurationRevision{}).
+		Select("station_configuration_id, MAX(version) AS version").
+		Where("station_configuration_id IN ?", configurationIds).
+		Group("station_configuration_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, row := range rows {
+		versions[row.StationConfigurationID] = row.Version
+	}
+
+	return versions, nil
+}
+
+// createRevision assigns the next version of the configuration and stores
+// the revision, preceded by a baseline when the history is still empty.
+func createRevision(tx *gorm.DB, revision *models.ConfigurationRevision) error {
+	var latest uint
+	err := tx.Model(&models.ConfigurationRevision{}).
+		Where("station_configuration_id = ?", revision.StationConfigurationID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	if latest == 0 && revision.Previous != nil {
+		baseline := &models.ConfigurationRevision{
+			StationConfigurationID: revision.StationConfigurationID,
+			StationID:              revision.StationID,
+			Version:                1,
+			Actor:                  actor.System,
+			Source:                 models.BaselineRevision,
+			Snapshot:               *revision.Previous,
+			Changes:                make([]models.ConfigChange, 0),
+		}
+
+		if err := tx.Create(baseline).Error; err != nil {
+			return err
+		}
+		latest = 1
+	}
+
+	revision.Version = latest + 1
+	return tx.Create(revision).Error
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gps-no-server/internal/common/logger"
 	"gps-no-server/internal/core/models"
+	"slices"
+	"time"
 )
 
 type RangingRepository struct {
@@ -51,3 +54,198 @@ func (r *RangingRepository) FindBySourceStationAndDestinationStation(ctx context
 
 	return &ranging, result.Error
 }
+
+func (r *RangingRepository) FindRecentByStation(ctx context.Context, stationId uint, since time.Time, includes map[string]bool) ([]*models.Ranging, error) {
+	var rangings []*models.Ranging
+	result := r.db.WithContext(ctx).
+		Where("(source_id = ? OR destination_id = ?) AND updated_at >= ?", stationId, stationId, since).
+		Order("updated_at DESC").
+		Find(&rangings)
+
+	return rangings, result.Error
+}
+
+// FindByCluster returns the latest ranging of every pair whose stations both
+// belong to the cluster.
+func (r *RangingRepository) FindByCluster(ctx context.Context, clusterId uint, includes map[string]bool) ([]*models.Ranging, error) {
+	var rangings []*models.Ranging
+	clusterStations := r.db.Model(&models.Station{}).Select("id").Where("cluster_id = ?", clusterId)
+
+	result := r.db.WithContext(ctx).
+		Where("source_id IN (?) AND destination_id IN (?)", clusterStations, clusterStations).
+		Order("source_id, destination_id").
+		Find(&rangings)
+
+	return rangings, result.Error
+}
+
+func (r *RangingRepository) UpsertBatch(ctx context.Context, rangings []*models.Ranging, samples []*models.RangingSample, includes map[string]bool) ([]*models.Ranging, error) {
+	if len(rangings) == 0 && len(samples) == 0 {
+		return rangings, nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rangings) > 0 {
+			err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "source_id"}, {Name: "destination_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"raw_distance":      gorm.Expr("EXCLUDED.raw_distance"),
+					"scaled_distance":   gorm.Expr("EXCLUDED.scaled_distance"),
+					"filtered_distance": gorm.Expr("EXCLUDED.filtered_distance"),
+					"updated_at":        gorm.Expr("EXCLUDED.updated_at"),
+					"deleted_at":        nil,
+				}),
+			}).Create(&rangings).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(samples) > 0 {
+			return tx.Omit(clause.Associations).Create(&samples).Error
+		}
+
+		return nil
+	})
+
+	return rangings, err
+}
+
+type RangingSampleStatus string
+
+const (
+	AcceptedSamples RangingSampleStatus = "accepted"
+	RejectedSamples RangingSampleStatus = "rejected"
+	AllSamples      RangingSampleStatus = "all"
+)
+
+type RangingSampleFilter struct {
+	SourceID      *uint
+	DestinationID *uint
+	StationID     *uint
+	From          time.Time
+	To            time.Time
+	Interval      time.Duration
+	Status        RangingSampleStatus
+	Order         string
+	Limit         int
+}
+
+// FindSamples returns the newest samples or buckets within the limit in the
+// requested order and reports whether older ones were left out.
+func (r *RangingRepository) FindSamples(ctx context.Context, filter RangingSampleFilter, includes map[string]bool) ([]*models.RangingSamplePoint, bool, error) {
+	var points []*models.RangingSamplePoint
+	query := r.db.WithContext(ctx).Model(&models.RangingSample{}).
+		Where("timestamp >= ? AND timestamp <= ?", filter.From, filter.To)
+
+	if filter.SourceID != nil {
+		query = query.Where("source_id = ?", *filter.SourceID)
+	}
+
+	if filter.DestinationID != nil {
+		query = query.Where("destination_id = ?", *filter.DestinationID)
+	}
+
+	if filter.StationID != nil {
+		query = query.Where("(source_id = ? OR destination_id = ?)", *filter.StationID, *filter.StationID)
+	}
+
+	switch filter.Status {
+	case RejectedSamples:
+		query = query.Where("rejected = ?", true)
+	case AllSamples:
+	default:
+		query = query.Where("rejected = ?", false)
+	}
+
+	if filter.Interval > 0 {
+		seconds := filter.Interval.Seconds()
+		bucket := gorm.Expr("to_timestamp(floor(extract(epoch from timestamp) / ?) * ?)", seconds, seconds)
+
+		query = query.
+			Select("source_id, destination_id, ? AS timestamp, "+
+				"avg(raw_distance) AS raw_distance, avg(scaled_distance) AS scaled_distance, "+
+				"avg(rssi) AS rssi, avg(first_path_power) AS first_path_power, "+
+				"avg(signal_quality) AS signal_quality, count(*) AS sample_count", bucket).
+			Group("source_id, destination_id, 3").
+			Order("3 DESC")
+	} else {
+		query = query.
+			Select("source_id, destination_id, timestamp, raw_distance, scaled_distance, " +
+				"rssi, first_path_power, signal_quality, rejected, reject_stage, reject_reason, 1 AS sample_count").
+			Order("timestamp DESC")
+	}
+
+	// One extra row tells whether the limit cut off older samples.
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit + 1)
+	}
+
+	if err := query.Scan(&points).Error; err != nil {
+		return nil, false, err
+	}
+
+	truncated := filter.Limit > 0 && len(points) > filter.Limit
+	if truncated {
+		points = points[:filter.Limit]
+	}
+
+	if filter.Order != "desc" {
+		slices.Reverse(points)
+	}
+
+	return points, truncated, nil
+}
+
+type RangingStatsFilter struct {
+	ClusterID *uint
+	From      time.Time
+	To        time.Time
+}
+
+func (r *RangingRepository) FindPairStats(ctx context.Context, filter RangingStatsFilter, includes map[string]bool) ([]*models.RangingPairStats, error) {
+	var stats []*models.RangingPairStats
+
+	samples := r.db.WithContext(ctx).Model(&models.RangingSample{}).
+		Select("source_id, destination_id, timestamp, raw_distance, "+
+			"extract(epoch from timestamp - lag(timestamp) OVER (PARTITION BY source_id, destination_id ORDER BY timestamp)) AS gap").
+		Where("timestamp >= ? AND timestamp <= ? AND rejected = ?", filter.From, filter.To, false)
+
+	if filter.ClusterID != nil {
+		clusterStations := r.db.Model(&models.Station{}).Select("id").Where("cluster_id = ?", *filter.ClusterID)
+		samples = samples.Where("source_id IN (?) AND destination_id IN (?)", clusterStations, clusterStations)
+	}
+
+	result := r.db.WithContext(ctx).Table("(?) AS samples", samples).
+		Select("source_id, destination_id, count(*) AS count, " +
+			"avg(raw_distance) AS mean, " +
+			"percentile_cont(0.5) WITHIN GROUP (ORDER BY raw_distance) AS median, " +
+			"coalesce(stddev_samp(raw_distance), 0) AS std_dev, " +
+			"min(raw_distance) AS min, max(raw_distance) AS max, " +
+			"min(timestamp) AS first_seen, max(timestamp) AS last_seen, " +
+			"coalesce(max(gap), 0) AS max_gap").
+		Group("source_id, destination_id").
+		Order("source_id, destination_id").
+		Scan(&stats)
+
+	return stats, result.Error
+}
+
+// CountPairSamples counts the samples per pair in which both stations are
+// part of the given set, including those rejected by the filter chain.
+func (r *RangingRepository) CountPairSamples(ctx context.Context, stationIds []uint, from time.Time, to time.Time) ([]*models.RangingSampleCount, error) {
+	counts := make([]*models.RangingSampleCount, 0)
+	if len(stationIds) == 0 {
+		return counts, nil
+	}
+
+	result := r.db.WithContext(ctx).Model(&models.RangingSample{}).
+		Select("source_id, destination_id, count(*) AS samples, count(*) FILTER (WHERE rejected) AS rejected").
+		Where("timestamp >= ? AND timestamp <= ?", from, to).
+		Where("source_id IN ? AND destination_id IN ?", stationIds, stationIds).
+		Group("source_id, destination_id").
+		Order("source_id, destination_id").
+		Scan(&counts)
+
+	return counts, result.Error
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+)
+
+type RolloutRepository struct {
+	*BaseRepository[models.Rollout]
+	db  *gorm.DB
+	log zerolog.Logger
+}
+
+func NewRolloutRepository(db *gorm.DB) *RolloutRepository {
+	baseRepository := &BaseRepository[models.Rollout]{
+		DB:         db,
+		Log:        logger.GetLogger("rollout-repository"),
+		EntityName: "rollout-repository",
+	}
+
+	return &RolloutRepository{
+		BaseRepository: baseRepository,
+		db:             db,
+		log:            logger.GetLogger("rollout-repository"),
+	}
+}
+
+func (r *RolloutRepository) FindByStatus(ctx context.Context, statuses ...models.RolloutStatus) ([]*models.Rollout, error) {
+	var rollouts []*models.Rollout
+	result := r.db.WithContext(ctx).
+		Where("status IN ?", statuses).
+		Order("id").
+		Find(&rollouts)
+	return rollouts, result.Error
+}
+
+func (r *RolloutRepository) FindActiveByCluster(ctx context.Context, clusterId uint) ([]*models.Rollout, error) {
+	var rollouts []*models.Rollout
+	result := r.db.WithContext(ctx).
+		Where("cluster_id = ? AND status IN ?", clusterId, []models.RolloutStatus{models.RolloutRunning, models.RolloutPaused}).
+		Find(&rollouts)
+	return rollouts, result.Error
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gps-no-server/internal/common/logger"
 	"gps-no-server/internal/core/models"
+	"time"
 )
 
 type StationRepository struct {
@@ -34,8 +36,168 @@ func (s *StationRepository) FindByMac(ctx context.Context, macAddress string, in
 	return &station, result.Error
 }
 
+func (s *StationRepository) FindByMacs(ctx context.Context, macAddresses []string, includes map[string]bool) ([]*models.Station, error) {
+	var stations []*models.Station
+	result := s.db.WithContext(ctx).Where("mac_address IN ?", macAddresses).Find(&stations)
+	return stations, result.Error
+}
+
+func (s *StationRepository) FindByIds(ctx context.Context, stationIds []uint, includes map[string]bool) ([]*models.Station, error) {
+	var stations []*models.Station
+	result := s.db.WithContext(ctx).Where("id IN ?", stationIds).Order("id").Find(&stations)
+	return stations, result.Error
+}
+
 func (s *StationRepository) FindByIdentifier(ctx context.Context, identifier string, includes map[string]bool) (*models.Station, error) {
 	var station models.Station
 	result := s.db.WithContext(ctx).Where("identifier = ?", identifier).First(&station)
 	return &station, result.Error
 }
+
+func (s *StationRepository) FindByCluster(ctx context.Context, clusterId uint, includes map[string]bool) ([]*models.Station, error) {
+	var stations []*models.Station
+	query := s.db.WithContext(ctx).Where("cluster_id = ?", clusterId)
+
+	if includes["config"] {
+		query = query.Preload("StationConfig")
+	}
+
+	result := query.Find(&stations)
+	return stations, result.Error
+}
+
+func (s *StationRepository) UpdatePosition(ctx context.Context, stationId uint, position models.Position) error {
+	result := s.db.WithContext(ctx).Model(&models.Station{}).Where("id = ?", stationId).Updates(map[string]interface{}{
+		"position_x":      position.X,
+		"position_y":      position.Y,
+		"position_z":      position.Z,
+		"position_frame":  position.Frame,
+		"position_source": position.Source,
+	})
+
+	return result.Error
+}
+
+func (s *StationRepository) UpdatePositions(ctx context.Context, positions map[uint]models.Position) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for stationId, position := range positions {
+			result := tx.Model(&models.Station{}).Where("id = ?", stationId).Updates(map[string]interface{}{
+				"position_x":      position.X,
+				"position_y":      position.Y,
+				"position_z":      position.Z,
+				"position_frame":  position.Frame,
+				"position_source": position.Source,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		return nil
+	})
+}
+
+// ClusterAssignment moves stations into a cluster. A cluster without an ID
+// is created first.
+type ClusterAssignment struct {
+	Cluster    *models.Cluster
+	StationIDs []uint
+}
+
+// AssignClusters creates the new clusters and moves their stations in a
+// single transaction, so a failure leaves neither empty clusters nor
+// partially moved stations behind.
+func (s *StationRepository) AssignClusters(ctx context.Context, assignments []*ClusterAssignment) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, assignment := range assignments {
+			if assignment.Cluster.ID == 0 {
+				if err := tx.Create(assignment.Cluster).Error; err != nil {
+					return err
+				}
+			}
+
+			if len(assignment.StationIDs) == 0 {
+				continue
+			}
+
+			result := tx.Model(&models.Station{}).Where("id IN ?", assignment.StationIDs).Update("cluster_id", assignment.Cluster.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *StationRepository) UpdateClusterReport(ctx context.Context, station *models.Station) error {
+	result := s.db.WithContext(ctx).Model(station).
+		Select("cluster_id", "reported_cluster", "reported_peers", "cluster_mismatch").
+		Updates(station)
+
+	return result.Error
+}
+
+func (s *StationRepository) FindByStatus(ctx context.Context, status models.StationStatus, includes map[string]bool) ([]*models.Station, error) {
+	var stations []*models.Station
+	query := s.db.WithContext(ctx).Where("status = ?", status)
+
+	if includes["config"] {
+		query = query.Preload("StationConfig")
+	}
+
+	result := query.Find(&stations)
+	return stations, result.Error
+}
+
+// MarkSeen records activity for the given MAC addresses and returns the
+// stations that were not online before. The status is switched with a
+// single conditional update, so of two concurrent calls only one reports
+// the transition.
+func (s *StationRepository) MarkSeen(ctx context.Context, macAddresses []string, seenAt time.Time) ([]*models.Station, error) {
+	var transitioned []*models.Station
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&transitioned).
+			Clauses(clause.Returning{}).
+			Where("mac_address IN ? AND status <> ?", macAddresses, models.StationOnline).
+			Update("status", models.StationOnline)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Model(&models.Station{}).
+			Where("mac_address IN ? AND (last_seen_at IS NULL OR last_seen_at < ?)", macAddresses, seenAt).
+			Update("last_seen_at", seenAt).Error
+	})
+
+	return transitioned, err
+}
+
+// MarkOffline switches an online station to offline unless it has been seen
+// since the given time. It reports whether the status changed.
+func (s *StationRepository) MarkOffline(ctx context.Context, stationId uint, notSeenSince time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&models.Station{}).
+		Where("id = ? AND status = ? AND (last_seen_at IS NULL OR last_seen_at <= ?)", stationId, models.StationOnline, notSeenSince).
+		Update("status", models.StationOffline)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *StationRepository) FindStale(ctx context.Context, notSeenSince time.Time) ([]*models.Station, error) {
+	var stations []*models.Station
+	result := s.db.WithContext(ctx).
+		Where("status = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", models.StationOnline, notSeenSince).
+		Find(&stations)
+
+	return stations, result.Error
+}
+
+func (s *StationRepository) UpdateUptime(ctx context.Context, stationId uint, startedAt *time.Time, uptimeSeconds int64) error {
+	result := s.db.WithContext(ctx).Model(&models.Station{}).Where("id = ?", stationId).Updates(map[string]interface{}{
+		"started_at":     startedAt,
+		"uptime_seconds": uptimeSeconds,
+	})
+
+	return result.Error
+}
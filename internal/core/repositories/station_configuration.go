@@ -4,8 +4,10 @@ import (
 	"context"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gps-no-server/internal/common/logger"
 	"gps-no-server/internal/core/models"
+	"time"
 )
 
 type StationConfigurationRepository struct {
@@ -38,3 +40,174 @@ func (s *StationConfigurationRepository) FindByStationId(ctx context.Context, st
 
 	return &stationConfig, result.Error
 }
+
+func (s *StationConfigurationRepository) FindByStationIds(ctx context.Context, stationIds []uint, includes map[string]bool) ([]*models.StationConfiguration, error) {
+	var stationConfigs []*models.StationConfiguration
+	result := s.db.WithContext(ctx).Where("station_id IN ?", stationIds).Find(&stationConfigs)
+	return stationConfigs, result.Error
+}
+
+func (s *StationConfigurationRepository) FindBySyncStatus(ctx context.Context, status models.ConfigSyncStatus, includes map[string]bool) ([]*models.StationConfiguration, error) {
+	var stationConfigs []*models.StationConfiguration
+	result := s.db.WithContext(ctx).Where("sync_status = ?", status).Order("id").Find(&stationConfigs)
+	return stationConfigs, result.Error
+}
+
+// FindPendingSince returns configurations published before the given time
+// that are still waiting for an acknowledgement.
+func (s *StationConfigurationRepository) FindPendingSince(ctx context.Context, publishedBefore time.Time) ([]*models.StationConfiguration, error) {
+	var stationConfigs []*models.StationConfiguration
+	result := s.db.WithContext(ctx).
+		Where("sync_status = ? AND sync_published_at < ?", models.SyncPending, publishedBefore).
+		Find(&stationConfigs)
+	return stationConfigs, result.Error
+}
+
+func (s *StationConfigurationRepository) UpdateSync(ctx context.Context, id uint, sync models.ConfigSync) error {
+	result := s.db.WithContext(ctx).Model(&models.StationConfiguration{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sync_status":          sync.Status,
+		"sync_revision":        sync.Revision,
+		"sync_attempts":        sync.Attempts,
+		"sync_published_at":    sync.PublishedAt,
+		"sync_acknowledged_at": sync.AcknowledgedAt,
+		"sync_error":           sync.Error,
+	})
+
+	return result.Error
+}
+
+// UpdateSyncIf changes the sync state only while the stored status and
+// revision still match, so late acknowledgements cannot overwrite a newer push.
+func (s *StationConfigurationRepository) UpdateSyncIf(ctx context.Context, id uint, status models.ConfigSyncStatus, revision uint, sync models.ConfigSync) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&models.StationConfiguration{}).
+		Where("id = ? AND sync_status = ? AND sync_revision = ?", id, status, revision).
+		Updates(map[string]interface{}{
+			"sync_status":          sync.Status,
+			"sync_acknowledged_at": sync.AcknowledgedAt,
+			"sync_error":           sync.Error,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *StationConfigurationRepository) FindReported(ctx context.Context) ([]*models.StationConfiguration, error) {
+	var stationConfigs []*models.StationConfiguration
+	result := s.db.WithContext(ctx).Where("reported_timestamp IS NOT NULL").Order("station_id").Find(&stationConfigs)
+	return stationConfigs, result.Error
+}
+
+func (s *StationConfigurationRepository) UpdateReported(ctx context.Context, id uint, reported models.ReportedConfiguration) error {
+	result := s.db.WithContext(ctx).Model(&models.StationConfiguration{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"reported_uwb_mode":          reported.UWBMode,
+		"reported_uwb_channel":       reported.UWBChannel,
+		"reported_uwb_preamble_code": reported.UWBPreambleCode,
+		"reported_uwb_preamble_len":  reported.UWBPreambleLen,
+		"reported_uwb_data_rate":     reported.UWBDataRate,
+		"reported_uwb_prf":           reported.UWBPRF,
+		"reported_uwb_sfd":           reported.UWBSFD,
+		"reported_uwb_tx_power":      reported.UWBTxPower,
+		"reported_uwb_antenna_delay": reported.UWBAntennaDelay,
+		"reported_timestamp":         reported.Timestamp,
+	})
+
+	return result.Error
+}
+
+// CreateWithRevision stores a new configuration and the revision revise
+// builds from it in one transaction.
+func (s *StationConfigurationRepository) CreateWithRevision(
+	ctx context.Context,
+	stationConfig *models.StationConfiguration,
+	revise func(before *models.StationConfiguration, created *models.StationConfiguration) *models.ConfigurationRevision,
+) (*models.StationConfiguration, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(stationConfig).Error; err != nil {
+			return err
+		}
+
+		return storeRevision(tx, nil, stationConfig, revise)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stationConfig, nil
+}
+
+// UpdateWithRevision updates the given fields, or the whole configuration
+// without fields, and stores the revision revise builds from the stored and
+// the updated configuration in the same transaction. The stored
+// configuration is read with a row lock, so concurrent updates of the same
+// configuration wait for each other and each revision describes the state it
+// actually replaced.
+func (s *StationConfigurationRepository) UpdateWithRevision(
+	ctx context.Context,
+	stationConfig *models.StationConfiguration,
+	fields []string,
+	revise func(before *models.StationConfiguration, updated *models.StationConfiguration) *models.ConfigurationRevision,
+) (*models.StationConfiguration, error) {
+	var before, updated models.StationConfiguration
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, stationConfig.ID).Error; err != nil {
+			return err
+		}
+
+		query := tx.Model(stationConfig)
+		if len(fields) > 0 {
+			query = query.Select(fields).Updates(stationConfig)
+		} else {
+			query = tx.Save(stationConfig)
+		}
+		if query.Error != nil {
+			return query.Error
+		}
+
+		if err := tx.First(&updated, stationConfig.ID).Error; err != nil {
+			return err
+		}
+
+		return storeRevision(tx, &before, &updated, revise)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func storeRevision(
+	tx *gorm.DB,
+	before *models.StationConfiguration,
+	after *models.StationConfiguration,
+	revise func(before *models.StationConfiguration, after *models.StationConfiguration) *models.ConfigurationRevision,
+) error {
+	revision := revise(before, after)
+	if revision == nil {
+		return nil
+	}
+
+	return createRevision(tx, revision)
+}
+
+// UpdateRadioBatch stores the radio parameters, profile assignment and
+// overrides of all configurations and their revisions in one transaction.
+// Either every configuration is updated or none is.
+func (s *StationConfigurationRepository) UpdateRadioBatch(ctx context.Context, stationConfigs []*models.StationConfiguration, revisions []*models.ConfigurationRevision) error {
+	fields := append([]string{"profile_id", "overrides"}, models.ProfileFields...)
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, stationConfig := range stationConfigs {
+			if err := tx.Model(stationConfig).Select(fields).Updates(stationConfig).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, revision := range revisions {
+			if err := createRevision(tx, revision); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
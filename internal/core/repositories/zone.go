@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+)
+
+type ZoneRepository struct {
+	*BaseRepository[models.Zone]
+	db  *gorm.DB
+	log zerolog.Logger
+}
+
+func NewZoneRepository(db *gorm.DB) *ZoneRepository {
+	baseRepository := &BaseRepository[models.Zone]{
+		DB:         db,
+		Log:        logger.GetLogger("zone-repository"),
+		EntityName: "zone-repository",
+	}
+
+	return &ZoneRepository{
+		BaseRepository: baseRepository,
+		db:             db,
+		log:            logger.GetLogger("zone-repository"),
+	}
+}
+
+func (z *ZoneRepository) FindByCluster(ctx context.Context, clusterId uint, includes map[string]bool) ([]*models.Zone, error) {
+	var zones []*models.Zone
+	query := z.db.WithContext(ctx).Where("cluster_id = ?", clusterId)
+
+	if includes["cluster"] {
+		query = query.Preload("Cluster")
+	}
+
+	result := query.Order("id").Find(&zones)
+	return zones, result.Error
+}
@@ -0,0 +1,486 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/geo"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/planning"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/core/validation"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoChannelPlan          = errors.New("no channel plan available")
+	ErrClusterNotInPlan       = errors.New("cluster is not part of the channel plan")
+	ErrInvalidPlanningChannel = errors.New("unsupported UWB channel")
+)
+
+// ChannelPlanService assigns UWB channels and preamble codes to clusters so
+// that neighbouring clusters do not interfere. The latest plan is kept until
+// it is applied or replaced.
+type ChannelPlanService struct {
+	stationRepository *repositories.StationRepository
+	clusterRepository *repositories.ClusterRepository
+	rangingRepository *repositories.RangingRepository
+	profileService    *ConfigurationProfileService
+	config            *config.PlanningConfig
+	plan              *models.ChannelPlan
+	planLock          sync.RWMutex
+	log               zerolog.Logger
+}
+
+func NewChannelPlanService(
+	stationRepository *repositories.StationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	rangingRepository *repositories.RangingRepository,
+	profileService *ConfigurationProfileService,
+	cfg *config.PlanningConfig,
+) *ChannelPlanService {
+	return &ChannelPlanService{
+		stationRepository: stationRepository,
+		clusterRepository: clusterRepository,
+		rangingRepository: rangingRepository,
+		profileService:    profileService,
+		config:            cfg,
+		log:               logger.GetLogger("channel-plan-service"),
+	}
+}
+
+// Plan proposes a channel and preamble code for every cluster with
+// configured stations. Clusters are neighbours when either lists the other,
+// when their stations ranged with each other within the window or when
+// their anchors are within the interference range. Channels restricts the
+// assignable channels and falls back to the configured ones.
+func (s *ChannelPlanService) Plan(ctx context.Context, window time.Duration, channels []uint8) (*models.ChannelPlan, error) {
+	if window <= 0 {
+		window = s.config.Window
+	}
+
+	if len(channels) == 0 {
+		for _, channel := range s.config.Channels {
+			channels = append(channels, uint8(channel))
+		}
+	}
+	for _, channel := range channels {
+		if len(validation.PreambleCodes(channel, models.DefaultUWBPRF)) == 0 {
+			return nil, fmt.Errorf("channel %d: %w", channel, ErrInvalidPlanningChannel)
+		}
+	}
+
+	clusters, err := s.clusterRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].ID < clusters[j].ID })
+
+	to := time.Now()
+	plan := &models.ChannelPlan{
+		Clusters:    make([]*models.ClusterChannelPlan, 0, len(clusters)),
+		Adjacencies: make([]*models.ClusterAdjacency, 0),
+		Warnings:    make([]string, 0),
+		From:        to.Add(-window),
+		To:          to,
+		Timestamp:   to,
+	}
+
+	members := make(map[uint][]*models.Station, len(clusters))
+	nodes := make([]planning.Node, 0, len(clusters))
+	plans := make(map[uint]*models.ClusterChannelPlan, len(clusters))
+
+	for _, cluster := range clusters {
+		stations, err := s.stationRepository.FindByCluster(ctx, cluster.ID, map[string]bool{"config": true})
+		if err != nil {
+			return nil, err
+		}
+
+		configured := make([]*models.Station, 0, len(stations))
+		for _, station := range stations {
+			if station.StationConfig != nil {
+				configured = append(configured, station)
+			}
+		}
+		if len(configured) == 0 {
+			continue
+		}
+		members[cluster.ID] = configured
+
+		current := currentAssignment(configured)
+		node := planning.Node{
+			ID:         cluster.ID,
+			Current:    &planning.Assignment{Channel: current.Channel, PreambleCode: current.PreambleCode},
+			Candidates: candidateAssignments(configured, current.PRF, channels),
+		}
+		nodes = append(nodes, node)
+
+		if len(node.Candidates) == 0 {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("cluster %d has no channel all of its stations support and keeps its assignment", cluster.ID))
+		}
+
+		clusterPlan := &models.ClusterChannelPlan{
+			ClusterID:     cluster.ID,
+			Name:          cluster.Name,
+			Stations:      len(configured),
+			Current:       current,
+			Neighbours:    make([]uint, 0),
+			Conflicts:     make([]uint, 0),
+			SharedChannel: make([]uint, 0),
+		}
+		plans[cluster.ID] = clusterPlan
+		plan.Clusters = append(plan.Clusters, clusterPlan)
+	}
+
+	adjacencies, err := s.adjacencies(ctx, clusters, members, plan.From, plan.To)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]planning.Edge, 0, len(adjacencies))
+	for _, adjacency := range adjacencies {
+		if plans[adjacency.ClusterID] == nil || plans[adjacency.NeighbourID] == nil {
+			continue
+		}
+
+		plan.Adjacencies = append(plan.Adjacencies, adjacency)
+		edges = append(edges, planning.Edge{A: adjacency.ClusterID, B: adjacency.NeighbourID})
+		plans[adjacency.ClusterID].Neighbours = append(plans[adjacency.ClusterID].Neighbours, adjacency.NeighbourID)
+		plans[adjacency.NeighbourID].Neighbours = append(plans[adjacency.NeighbourID].Neighbours, adjacency.ClusterID)
+	}
+
+	assignments := planning.AssignChannels(nodes, edges)
+
+	for _, clusterPlan := range plan.Clusters {
+		clusterPlan.Proposed = clusterPlan.Current
+		if assignment, exists := assignments[clusterPlan.ClusterID]; exists {
+			clusterPlan.Proposed.Channel = assignment.Channel
+			clusterPlan.Proposed.PreambleCode = assignment.PreambleCode
+		}
+		clusterPlan.Changed = clusterPlan.Proposed != clusterPlan.Current
+		sort.Slice(clusterPlan.Neighbours, func(i, j int) bool { return clusterPlan.Neighbours[i] < clusterPlan.Neighbours[j] })
+	}
+
+	for _, adjacency := range plan.Adjacencies {
+		a, b := plans[adjacency.ClusterID], plans[adjacency.NeighbourID]
+		if sameCode(a.Current, b.Current) {
+			plan.ConflictsBefore++
+		}
+
+		switch {
+		case sameCode(a.Proposed, b.Proposed):
+			plan.ConflictsAfter++
+			a.Conflicts = append(a.Conflicts, b.ClusterID)
+			b.Conflicts = append(b.Conflicts, a.ClusterID)
+		case a.Proposed.Channel == b.Proposed.Channel:
+			a.SharedChannel = append(a.SharedChannel, b.ClusterID)
+			b.SharedChannel = append(b.SharedChannel, a.ClusterID)
+		}
+	}
+
+	if plan.ConflictsAfter > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d neighbouring cluster pairs still share channel and preamble code", plan.ConflictsAfter))
+	}
+
+	s.planLock.Lock()
+	s.plan = plan
+	s.planLock.Unlock()
+
+	s.log.Info().
+		Int("clusters", len(plan.Clusters)).
+		Int("adjacencies", len(plan.Adjacencies)).
+		Int("conflicts_before", plan.ConflictsBefore).
+		Int("conflicts_after", plan.ConflictsAfter).
+		Msg("Computed channel plan")
+
+	return plan, nil
+}
+
+func (s *ChannelPlanService) GetLatest() (*models.ChannelPlan, bool) {
+	s.planLock.RLock()
+	defer s.planLock.RUnlock()
+
+	return s.plan, s.plan != nil
+}
+
+// Apply writes the proposed channel and preamble code of the changed
+// clusters into the stored configuration of their stations and pushes the
+// result. A later profile that sets another channel or code takes over from
+// the plan. Stations governed by a profile keep the plan as overrides
+// instead, since the profile would replace the stored values; rollouts pause
+// on them rather than silently leaving the channel to the override. An
+// empty selection applies every changed cluster.
+// Every cluster is applied on its own. When one fails, the reports of the
+// clusters applied before are returned with the error and the plan is kept,
+// so applying it again picks up the remaining clusters. Otherwise the plan
+// is discarded afterwards.
+func (s *ChannelPlanService) Apply(ctx context.Context, clusterIds []uint) ([]*models.ConfigApplyReport, error) {
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	plan := s.plan
+	if plan == nil {
+		return nil, ErrNoChannelPlan
+	}
+
+	planned := make(map[uint]*models.ClusterChannelPlan, len(plan.Clusters))
+	for _, clusterPlan := range plan.Clusters {
+		planned[clusterPlan.ClusterID] = clusterPlan
+	}
+
+	selected := make(map[uint]bool, len(clusterIds))
+	for _, clusterId := range clusterIds {
+		if planned[clusterId] == nil {
+			return nil, fmt.Errorf("cluster %d: %w", clusterId, ErrClusterNotInPlan)
+		}
+		selected[clusterId] = true
+	}
+
+	reports := make([]*models.ConfigApplyReport, 0)
+	for _, clusterPlan := range plan.Clusters {
+		if !clusterPlan.Changed || (len(selected) > 0 && !selected[clusterPlan.ClusterID]) {
+			continue
+		}
+
+		report, err := s.applyCluster(ctx, clusterPlan)
+		if err != nil {
+			s.log.Error().Err(err).Uint("cluster_id", clusterPlan.ClusterID).Int("clusters", len(reports)).Msg("Failed to apply channel plan")
+			return reports, fmt.Errorf("cluster %d: %w", clusterPlan.ClusterID, err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	s.plan = nil
+
+	s.log.Info().Int("clusters", len(reports)).Msg("Applied channel plan")
+
+	return reports, nil
+}
+
+func (s *ChannelPlanService) applyCluster(ctx context.Context, clusterPlan *models.ClusterChannelPlan) (*models.ConfigApplyReport, error) {
+	cluster, err := s.clusterRepository.FindById(ctx, clusterPlan.ClusterID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, preambleCode := clusterPlan.Proposed.Channel, clusterPlan.Proposed.PreambleCode
+	return s.profileService.ApplyToClusterWith(ctx, cluster.ID, func(stationConfig *models.StationConfiguration) {
+		if stationConfig.ProfileID != nil || cluster.ProfileID != nil {
+			stationConfig.Overrides.UWBChannel = &channel
+			stationConfig.Overrides.UWBPreambleCode = &preambleCode
+			return
+		}
+
+		stationConfig.UWBChannel = channel
+		stationConfig.UWBPreambleCode = preambleCode
+		stationConfig.Overrides.UWBChannel = nil
+		stationConfig.Overrides.UWBPreambleCode = nil
+	}, models.PlanRevision)
+}
+
+// adjacencies collects the neighbouring cluster pairs and why they are
+// considered neighbours. Pairs are ordered by the lower cluster id first.
+func (s *ChannelPlanService) adjacencies(
+	ctx context.Context,
+	clusters []*models.Cluster,
+	members map[uint][]*models.Station,
+	from time.Time,
+	to time.Time,
+) ([]*models.ClusterAdjacency, error) {
+	type pairKey struct{ a, b uint }
+	pairs := make(map[pairKey]*models.ClusterAdjacency)
+
+	add := func(a uint, b uint, reason models.AdjacencyReason) {
+		if a == b {
+			return
+		}
+
+		key := pairKey{a: min(a, b), b: max(a, b)}
+		adjacency, exists := pairs[key]
+		if !exists {
+			adjacency = &models.ClusterAdjacency{ClusterID: key.a, NeighbourID: key.b, Reasons: make([]models.AdjacencyReason, 0)}
+			pairs[key] = adjacency
+		}
+
+		for _, existing := range adjacency.Reasons {
+			if existing == reason {
+				return
+			}
+		}
+		adjacency.Reasons = append(adjacency.Reasons, reason)
+	}
+
+	for _, cluster := range clusters {
+		for _, neighbourId := range cluster.NeighbourIDs {
+			add(cluster.ID, neighbourId, models.NeighbourAdjacency)
+		}
+	}
+
+	clusterOf := make(map[uint]uint)
+	for clusterId, stations := range members {
+		for _, station := range stations {
+			clusterOf[station.ID] = clusterId
+		}
+	}
+
+	stats, err := s.rangingRepository.FindPairStats(ctx, repositories.RangingStatsFilter{From: from, To: to}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stat := range stats {
+		a, sourceExists := clusterOf[stat.SourceID]
+		b, destinationExists := clusterOf[stat.DestinationID]
+		if sourceExists && destinationExists && stat.Count > 0 {
+			add(a, b, models.RangingAdjacency)
+		}
+	}
+
+	if s.config.InterferenceRange > 0 {
+		anchors := make(map[uint][]geo.ECEF)
+		for _, cluster := range clusters {
+			anchors[cluster.ID] = anchorPositions(cluster, members[cluster.ID])
+		}
+
+		for i, a := range clusters {
+			for _, b := range clusters[i+1:] {
+				if withinRange(anchors[a.ID], anchors[b.ID], s.config.InterferenceRange) {
+					add(a.ID, b.ID, models.OverlapAdjacency)
+				}
+			}
+		}
+	}
+
+	adjacencies := make([]*models.ClusterAdjacency, 0, len(pairs))
+	for _, adjacency := range pairs {
+		adjacencies = append(adjacencies, adjacency)
+	}
+
+	sort.Slice(adjacencies, func(i, j int) bool {
+		if adjacencies[i].ClusterID != adjacencies[j].ClusterID {
+			return adjacencies[i].ClusterID < adjacencies[j].ClusterID
+		}
+		return adjacencies[i].NeighbourID < adjacencies[j].NeighbourID
+	})
+
+	return adjacencies, nil
+}
+
+// currentAssignment returns the most common channel, preamble code and PRF
+// of the stations, preferring the lowest on ties.
+func currentAssignment(stations []*models.Station) models.ChannelAssignment {
+	counts := make(map[models.ChannelAssignment]int)
+	for _, station := range stations {
+		counts[models.ChannelAssignment{
+			Channel:      station.StationConfig.UWBChannel,
+			PreambleCode: station.StationConfig.UWBPreambleCode,
+			PRF:          station.StationConfig.UWBPRF,
+		}]++
+	}
+
+	var current models.ChannelAssignment
+	best := 0
+	for assignment, count := range counts {
+		if count > best || (count == best && lessAssignment(assignment, current)) {
+			current, best = assignment, count
+		}
+	}
+
+	return current
+}
+
+// candidateAssignments lists the channel and preamble code pairs every
+// station of the cluster supports at the given PRF.
+func candidateAssignments(stations []*models.Station, prf uint8, channels []uint8) []planning.Assignment {
+	supported := make(map[uint8]int)
+	for _, station := range stations {
+		capabilities, exists := validation.GetPhyCapabilities(station.StationConfig.UWBChip)
+		if !exists {
+			return nil
+		}
+
+		for _, channel := range capabilities.Channels {
+			supported[channel]++
+		}
+	}
+
+	allowed := make(map[uint8]bool, len(channels))
+	for _, channel := range channels {
+		allowed[channel] = true
+	}
+
+	candidates := make([]planning.Assignment, 0)
+	for channel, count := range supported {
+		if count != len(stations) || (len(allowed) > 0 && !allowed[channel]) {
+			continue
+		}
+
+		for _, code := range validation.PreambleCodes(channel, prf) {
+			candidates = append(candidates, planning.Assignment{Channel: channel, PreambleCode: code})
+		}
+	}
+
+	return candidates
+}
+
+// anchorPositions returns the earth-centred positions of the positioned
+// anchors of a georeferenced cluster.
+func anchorPositions(cluster *models.Cluster, stations []*models.Station) []geo.ECEF {
+	if !cluster.Frame.IsGeoreferenced() {
+		return nil
+	}
+
+	scale := unitScale(cluster)
+	positions := make([]geo.ECEF, 0)
+	for _, station := range stations {
+		if station.StationConfig.UWBMode != models.AnchorMode || !station.Position.IsSet() {
+			continue
+		}
+
+		z := 0.0
+		if station.Position.Z != nil {
+			z = *station.Position.Z * scale
+		}
+
+		position := georeference(cluster, *station.Position.X*scale, *station.Position.Y*scale, z)
+		positions = append(positions, geo.GeodeticToECEF(geo.Geodetic{
+			Latitude:  position.Latitude,
+			Longitude: position.Longitude,
+			Altitude:  position.Altitude,
+		}))
+	}
+
+	return positions
+}
+
+func withinRange(a []geo.ECEF, b []geo.ECEF, distance float64) bool {
+	for _, p := range a {
+		for _, q := range b {
+			if math.Sqrt((p.X-q.X)*(p.X-q.X)+(p.Y-q.Y)*(p.Y-q.Y)+(p.Z-q.Z)*(p.Z-q.Z)) <= distance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameCode(a models.ChannelAssignment, b models.ChannelAssignment) bool {
+	return a.Channel == b.Channel && a.PreambleCode == b.PreambleCode
+}
+
+func lessAssignment(a models.ChannelAssignment, b models.ChannelAssignment) bool {
+	if a.Channel != b.Channel {
+		return a.Channel < b.Channel
+	}
+	if a.PreambleCode != b.PreambleCode {
+		return a.PreambleCode < b.PreambleCode
+	}
+	return a.PRF < b.PRF
+}
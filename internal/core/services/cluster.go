@@ -2,10 +2,13 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"github.com/rs/zerolog"
 	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/geo"
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/core/tracking"
 	"gps-no-server/internal/core/validation"
 	"gps-no-server/internal/infrastructure/http/dto"
 )
@@ -36,3 +39,101 @@ func (c *ClusterService) GetByMac(ctx context.Context, mac string, includeParam
 
 	return c.clusterRepository.FindByMac(ctx, mac, includes)
 }
+
+func (c *ClusterService) Create(ctx context.Context, cluster *models.Cluster, includeParam *string) (*models.Cluster, error) {
+	if err := validateTrackingSettings(cluster.Tracking); err != nil {
+		return nil, err
+	}
+
+	if err := validateClusterFrame(cluster.Frame); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateNeighbours(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	return c.BaseService.Create(ctx, cluster, includeParam)
+}
+
+func (c *ClusterService) UpdateFields(ctx context.Context, cluster *models.Cluster, fields []string, includeParam *string) (*models.Cluster, error) {
+	expandedFields := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		switch field {
+		case "tracking":
+			if err := validateTrackingSettings(cluster.Tracking); err != nil {
+				return nil, err
+			}
+
+			expandedFields = append(expandedFields, "tracking_model", "tracking_process_noise", "tracking_measurement_noise")
+		case "frame":
+			if err := validateClusterFrame(cluster.Frame); err != nil {
+				return nil, err
+			}
+
+			expandedFields = append(expandedFields, "frame_origin_latitude", "frame_origin_longitude", "frame_origin_altitude",
+				"frame_rotation", "frame_units", "frame_level")
+		case "neighbour_ids":
+			if err := c.validateNeighbours(ctx, cluster); err != nil {
+				return nil, err
+			}
+
+			expandedFields = append(expandedFields, field)
+		default:
+			expandedFields = append(expandedFields, field)
+		}
+	}
+
+	return c.BaseService.UpdateFields(ctx, cluster, expandedFields, includeParam)
+}
+
+func (c *ClusterService) validateNeighbours(ctx context.Context, cluster *models.Cluster) error {
+	for _, neighbourId := range cluster.NeighbourIDs {
+		if neighbourId == cluster.ID {
+			return fmt.Errorf("a cluster cannot be its own neighbour")
+		}
+
+		if _, err := c.clusterRepository.FindById(ctx, neighbourId, nil); err != nil {
+			return fmt.Errorf("unknown neighbour cluster %d", neighbourId)
+		}
+	}
+
+	return nil
+}
+
+func validateTrackingSettings(settings models.TrackingSettings) error {
+	if settings.Model != "" && !tracking.MotionModel(settings.Model).IsValid() {
+		return fmt.Errorf("unknown tracking model: %s", settings.Model)
+	}
+
+	if settings.ProcessNoise != nil && *settings.ProcessNoise <= 0 {
+		return fmt.Errorf("tracking process noise must be positive")
+	}
+
+	if settings.MeasurementNoise != nil && *settings.MeasurementNoise <= 0 {
+		return fmt.Errorf("tracking measurement noise must be positive")
+	}
+
+	return nil
+}
+
+func validateClusterFrame(frame models.ClusterFrame) error {
+	if (frame.OriginLatitude == nil) != (frame.OriginLongitude == nil) {
+		return fmt.Errorf("frame origin requires both latitude and longitude")
+	}
+
+	if frame.OriginLatitude != nil && (*frame.OriginLatitude < -90 || *frame.OriginLatitude > 90) {
+		return fmt.Errorf("frame origin latitude must be between -90 and 90")
+	}
+
+	if frame.OriginLongitude != nil && (*frame.OriginLongitude < -180 || *frame.OriginLongitude > 180) {
+		return fmt.Errorf("frame origin longitude must be between -180 and 180")
+	}
+
+	if _, err := geo.UnitScale(frame.Units); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/graph"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/events"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidFormationMethod = errors.New("formation method must be components or communities")
+	ErrNoClusterProposal      = errors.New("no cluster proposal available")
+	ErrInvalidProposalIndex   = errors.New("cluster is not part of the proposal")
+)
+
+// ClusterFormationService groups stations by who can range with whom. The
+// latest proposal is kept until it is accepted or replaced.
+type ClusterFormationService struct {
+	stationRepository *repositories.StationRepository
+	clusterRepository *repositories.ClusterRepository
+	rangingRepository *repositories.RangingRepository
+	eventBus          *events.StationEventBus
+	config            *config.RangingConfig
+	proposal          *models.ClusterProposal
+	proposalLock      sync.RWMutex
+	log               zerolog.Logger
+}
+
+func NewClusterFormationService(
+	stationRepository *repositories.StationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	rangingRepository *repositories.RangingRepository,
+	eventBus *events.StationEventBus,
+	cfg *config.RangingConfig,
+) *ClusterFormationService {
+	return &ClusterFormationService{
+		stationRepository: stationRepository,
+		clusterRepository: clusterRepository,
+		rangingRepository: rangingRepository,
+		eventBus:          eventBus,
+		config:            cfg,
+		log:               logger.GetLogger("cluster-formation-service"),
+	}
+}
+
+func (s *ClusterFormationService) Propose(ctx context.Context, method models.FormationMethod, window time.Duration) (*models.ClusterProposal, error) {
+	if method == "" {
+		method = models.ComponentsFormation
+	}
+
+	if !method.IsValid() {
+		return nil, ErrInvalidFormationMethod
+	}
+
+	if window <= 0 {
+		window = s.config.FormationWindow
+	}
+
+	stations, err := s.stationRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := s.clusterRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	from := to.Add(-window)
+	stats, err := s.rangingRepository.FindPairStats(ctx, repositories.RangingStatsFilter{From: from, To: to}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint]*uint, len(stations))
+	nodes := make([]uint, 0, len(stations))
+	for _, station := range stations {
+		current[station.ID] = station.ClusterID
+		nodes = append(nodes, station.ID)
+	}
+
+	// Both directions of a pair count towards one undirected link.
+	type pairKey struct{ a, b uint }
+	counts := make(map[pairKey]int64)
+	for _, stat := range stats {
+		counts[pairKey{a: min(stat.SourceID, stat.DestinationID), b: max(stat.SourceID, stat.DestinationID)}] += stat.Count
+	}
+
+	edges := make([]graph.Edge, 0, len(counts))
+	for key, count := range counts {
+		if float64(count)/window.Seconds() < s.config.FormationMinRate {
+			continue
+		}
+		edges = append(edges, graph.Edge{A: key.a, B: key.b, Weight: float64(count)})
+	}
+
+	var groups [][]uint
+	if method == models.CommunitiesFormation {
+		groups = graph.Communities(nodes, edges, 0)
+	} else {
+		groups = graph.Components(nodes, edges)
+	}
+
+	proposal := &models.ClusterProposal{
+		Method:     method,
+		Clusters:   make([]*models.ProposedCluster, 0, len(groups)),
+		Unassigned: make([]uint, 0),
+		From:       from,
+		To:         to,
+		Timestamp:  to,
+	}
+
+	// Larger groups pick their existing cluster first so that a split keeps
+	// the cluster on its bigger half.
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+
+	names := make(map[uint]string, len(clusters))
+	for _, cluster := range clusters {
+		names[cluster.ID] = cluster.Name
+	}
+
+	claimed := make(map[uint]bool)
+	for _, members := range groups {
+		if len(members) < 2 {
+			proposal.Unassigned = append(proposal.Unassigned, members...)
+			continue
+		}
+
+		proposed := &models.ProposedCluster{StationIDs: members, Added: make([]uint, 0)}
+
+		overlap := make(map[uint]int)
+		for _, id := range members {
+			if current[id] != nil && !claimed[*current[id]] {
+				overlap[*current[id]]++
+			}
+		}
+
+		for clusterId, count := range overlap {
+			if proposed.ClusterID == nil || count > overlap[*proposed.ClusterID] || (count == overlap[*proposed.ClusterID] && clusterId < *proposed.ClusterID) {
+				id := clusterId
+				proposed.ClusterID = &id
+			}
+		}
+
+		if proposed.ClusterID != nil {
+			claimed[*proposed.ClusterID] = true
+			proposed.Name = names[*proposed.ClusterID]
+		} else {
+			proposed.Name = fmt.Sprintf("auto-%d", members[0])
+		}
+
+		for _, id := range members {
+			if proposed.ClusterID == nil || current[id] == nil || *current[id] != *proposed.ClusterID {
+				proposed.Added = append(proposed.Added, id)
+			}
+		}
+
+		proposal.Clusters = append(proposal.Clusters, proposed)
+	}
+
+	sort.Slice(proposal.Clusters, func(i, j int) bool {
+		return proposal.Clusters[i].StationIDs[0] < proposal.Clusters[j].StationIDs[0]
+	})
+	sort.Slice(proposal.Unassigned, func(i, j int) bool { return proposal.Unassigned[i] < proposal.Unassigned[j] })
+
+	s.proposalLock.Lock()
+	s.proposal = proposal
+	s.proposalLock.Unlock()
+
+	s.log.Info().
+		Str("method", string(method)).
+		Int("clusters", len(proposal.Clusters)).
+		Int("unassigned", len(proposal.Unassigned)).
+		Msg("Computed cluster proposal")
+
+	return proposal, nil
+}
+
+func (s *ClusterFormationService) GetLatest() (*models.ClusterProposal, bool) {
+	s.proposalLock.RLock()
+	defer s.proposalLock.RUnlock()
+
+	return s.proposal, s.proposal != nil
+}
+
+// Accept applies the selected clusters of the latest proposal, creating new
+// clusters where needed. New clusters whose name is already taken get a
+// numeric suffix. Clusters and assignments are written in one transaction;
+// the stored proposal is left untouched when that fails so it can be
+// accepted again. Every station that changes cluster produces a removed
+// event for its previous cluster and an added event for the new one. An
+// empty selection accepts every proposed cluster. The proposal is discarded
+// afterwards since it no longer reflects the assignments.
+func (s *ClusterFormationService) Accept(ctx context.Context, indices []int) (*models.ClusterProposal, error) {
+	s.proposalLock.Lock()
+	defer s.proposalLock.Unlock()
+
+	if s.proposal == nil {
+		return nil, ErrNoClusterProposal
+	}
+
+	selected := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		if index < 0 || index >= len(s.proposal.Clusters) {
+			return nil, fmt.Errorf("cluster %d: %w", index, ErrInvalidProposalIndex)
+		}
+		selected[index] = true
+	}
+
+	proposal := *s.proposal
+	proposal.Clusters = make([]*models.ProposedCluster, len(s.proposal.Clusters))
+	for i, proposed := range s.proposal.Clusters {
+		cluster := *proposed
+		proposal.Clusters[i] = &cluster
+	}
+
+	stations, err := s.stationRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[uint]*uint, len(stations))
+	for _, station := range stations {
+		previous[station.ID] = station.ClusterID
+	}
+
+	names, err := s.clusterRepository.FindNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(names))
+	for _, name := range names {
+		taken[name] = true
+	}
+
+	accepted := make([]*models.ProposedCluster, 0, len(proposal.Clusters))
+	assignments := make([]*repositories.ClusterAssignment, 0, len(proposal.Clusters))
+	for i, proposed := range proposal.Clusters {
+		if len(selected) > 0 && !selected[i] {
+			continue
+		}
+
+		cluster := &models.Cluster{}
+		if proposed.ClusterID != nil {
+			cluster.ID = *proposed.ClusterID
+		} else {
+			proposed.Name = uniqueClusterName(proposed.Name, taken)
+			taken[proposed.Name] = true
+			cluster.Name = proposed.Name
+		}
+
+		accepted = append(accepted, proposed)
+		assignments = append(assignments, &repositories.ClusterAssignment{Cluster: cluster, StationIDs: proposed.Added})
+	}
+
+	if err := s.stationRepository.AssignClusters(ctx, assignments); err != nil {
+		return nil, err
+	}
+	s.proposal = nil
+
+	now := time.Now()
+	moved := 0
+	for i, assignment := range assignments {
+		clusterId := assignment.Cluster.ID
+		accepted[i].ClusterID = &clusterId
+		moved += len(assignment.StationIDs)
+
+		for _, stationId := range assignment.StationIDs {
+			if from := previous[stationId]; from != nil {
+				if *from == clusterId {
+					continue
+				}
+
+				s.eventBus.Publish(&events.StationEvent{
+					Type:      events.StationRemovedFromCluster,
+					ClusterId: *from,
+					StationId: stationId,
+					Timestamp: now,
+				})
+			}
+
+			s.eventBus.Publish(&events.StationEvent{
+				Type:      events.StationAddedToCluster,
+				ClusterId: clusterId,
+				StationId: stationId,
+				Timestamp: now,
+			})
+		}
+	}
+
+	s.log.Info().
+		Int("stations", moved).
+		Msg("Accepted cluster proposal")
+
+	return &proposal, nil
+}
+
+func uniqueClusterName(name string, taken map[string]bool) string {
+	candidate := name
+	for suffix := 2; taken[candidate]; suffix++ {
+		candidate = fmt.Sprintf("%s-%d", name, suffix)
+	}
+	return candidate
+}
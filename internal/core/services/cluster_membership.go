@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/events"
+	"strings"
+	"time"
+)
+
+// ClusterMembershipService reconciles the cluster a device reports with the
+// server-side assignment. Stations without an assignment adopt the reported
+// cluster; otherwise the server wins and disagreements are flagged.
+type ClusterMembershipService struct {
+	stationRepository *repositories.StationRepository
+	clusterRepository *repositories.ClusterRepository
+	eventBus          *events.StationEventBus
+	log               zerolog.Logger
+}
+
+func NewClusterMembershipService(
+	stationRepository *repositories.StationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	eventBus *events.StationEventBus,
+) *ClusterMembershipService {
+	return &ClusterMembershipService{
+		stationRepository: stationRepository,
+		clusterRepository: clusterRepository,
+		eventBus:          eventBus,
+		log:               logger.GetLogger("cluster-membership-service"),
+	}
+}
+
+// Reconcile stores the reported cluster name and peer MAC addresses of a
+// station. A correction event is published when a mismatch is first
+// detected so the device can be told its server-side cluster. A device that
+// reports no cluster name is not in conflict with its assignment; only its
+// peers are checked then.
+func (s *ClusterMembershipService) Reconcile(ctx context.Context, station *models.Station, reportedName *string, peers []string) error {
+	var reported *string
+	if reportedName != nil && strings.TrimSpace(*reportedName) != "" {
+		name := strings.TrimSpace(*reportedName)
+		reported = &name
+	}
+
+	wasMismatched := station.ClusterMismatch
+	assigned := false
+
+	var cluster *models.Cluster
+	var err error
+	switch {
+	case station.ClusterID != nil:
+		cluster, err = s.clusterRepository.FindById(ctx, *station.ClusterID, nil)
+		if err != nil {
+			return err
+		}
+	case reported != nil:
+		cluster, err = s.findOrCreate(ctx, *reported)
+		if err != nil {
+			return err
+		}
+		station.ClusterID = &cluster.ID
+		assigned = true
+	}
+
+	station.ReportedCluster = reported
+	station.ReportedPeers = peers
+	station.ClusterMismatch = false
+
+	if cluster != nil {
+		if reported != nil && *reported != cluster.Name {
+			station.ClusterMismatch = true
+		} else if len(peers) > 0 {
+			mismatched, err := s.peersOutside(ctx, cluster.ID, peers)
+			if err != nil {
+				return err
+			}
+			station.ClusterMismatch = mismatched
+		}
+	}
+
+	if err := s.stationRepository.UpdateClusterReport(ctx, station); err != nil {
+		return err
+	}
+
+	if assigned {
+		s.log.Info().
+			Uint("station_id", station.ID).
+			Uint("cluster_id", cluster.ID).
+			Str("cluster", cluster.Name).
+			Msg("Assigned station to reported cluster")
+
+		s.eventBus.Publish(&events.StationEvent{
+			Type:        events.StationAddedToCluster,
+			ClusterId:   cluster.ID,
+			StationId:   station.ID,
+			ClusterName: cluster.Name,
+			Timestamp:   time.Now(),
+		})
+	}
+
+	if station.ClusterMismatch && !wasMismatched {
+		reportedValue := ""
+		if reported != nil {
+			reportedValue = *reported
+		}
+
+		s.log.Warn().
+			Uint("station_id", station.ID).
+			Str("cluster", cluster.Name).
+			Str("reported", reportedValue).
+			Strs("peers", peers).
+			Msg("Station reports a different cluster than assigned")
+
+		s.eventBus.Publish(&events.StationEvent{
+			Type:        events.StationClusterMismatch,
+			ClusterId:   cluster.ID,
+			StationId:   station.ID,
+			ClusterName: cluster.Name,
+			Timestamp:   time.Now(),
+		})
+	}
+
+	return nil
+}
+
+func (s *ClusterMembershipService) findOrCreate(ctx context.Context, name string) (*models.Cluster, error) {
+	cluster, err := s.clusterRepository.FindByName(ctx, name, nil)
+	if err == nil {
+		return cluster, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	created, err := s.clusterRepository.Create(ctx, &models.Cluster{Name: name}, nil)
+	if err != nil {
+		// Another device may have created the cluster concurrently.
+		if existing, findErr := s.clusterRepository.FindByName(ctx, name, nil); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// peersOutside reports whether a known peer is assigned to another cluster.
+// Peers the server has not seen yet are ignored.
+func (s *ClusterMembershipService) peersOutside(ctx context.Context, clusterId uint, peers []string) (bool, error) {
+	stations, err := s.stationRepository.FindByMacs(ctx, peers, nil)
+	if err != nil {
+		return false, err
+	}
+
+	for _, peer := range stations {
+		if peer.ClusterID != nil && *peer.ClusterID != clusterId {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
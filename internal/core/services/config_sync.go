@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/interfaces"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/mappers"
+	"gps-no-server/internal/core/repositories"
+	"sync"
+	"time"
+)
+
+var ErrNoMessagePublisher = errors.New("no message publisher available")
+
+// ConfigSyncService pushes station configurations to their devices and
+// follows them until the device acknowledges, rejects or ignores them.
+type ConfigSyncService struct {
+	stationConfigRepository *repositories.StationConfigurationRepository
+	stationRepository       *repositories.StationRepository
+	publisher               interfaces.MessagePublisher
+	eventPublisher          *ConfigSyncEventPublisher
+	config                  *config.ConfigSyncConfig
+	stop                    chan struct{}
+	stopOnce                sync.Once
+	log                     zerolog.Logger
+}
+
+func NewConfigSyncService(
+	stationConfigRepository *repositories.StationConfigurationRepository,
+	stationRepository *repositories.StationRepository,
+	publisher interfaces.MessagePublisher,
+	eventStreamService *EventStreamService,
+	cfg *config.ConfigSyncConfig,
+) *ConfigSyncService {
+	service := &ConfigSyncService{
+		stationConfigRepository: stationConfigRepository,
+		stationRepository:       stationRepository,
+		publisher:               publisher,
+		config:                  cfg,
+		stop:                    make(chan struct{}),
+		log:                     logger.GetLogger("config-sync-service"),
+	}
+
+	if eventStreamService != nil {
+		service.eventPublisher = NewConfigSyncEventPublisher(eventStreamService)
+	}
+
+	return service
+}
+
+// ConfigTopic is the per-device topic desired configurations are sent to.
+func ConfigTopic(macAddress string) string {
+	return fmt.Sprintf("gpsno/simulation/devices/%s/uwb/config/set", macAddress)
+}
+
+func (s *ConfigSyncService) GetByStatus(ctx context.Context, status models.ConfigSyncStatus) ([]*models.StationConfiguration, error) {
+	return s.stationConfigRepository.FindBySyncStatus(ctx, status, nil)
+}
+
+// Push publishes the configuration under a new revision and marks it
+// pending. A failed publish is recorded as failed rather than returned, the
+// returned error only covers loading and storing the configuration.
+func (s *ConfigSyncService) Push(ctx context.Context, stationConfig *models.StationConfiguration) (*models.StationConfiguration, error) {
+	return s.push(ctx, stationConfig, 1)
+}
+
+// Resend pushes the configuration again as another attempt at delivering
+// it. The attempts start over once the device applied a configuration.
+func (s *ConfigSyncService) Resend(ctx context.Context, stationConfig *models.StationConfiguration) (*models.StationConfiguration, error) {
+	attempts := stationConfig.Sync.Attempts + 1
+	if stationConfig.Sync.Status == models.SyncApplied {
+		attempts = 1
+	}
+
+	return s.push(ctx, stationConfig, attempts)
+}
+
+func (s *ConfigSyncService) push(ctx context.Context, stationConfig *models.StationConfiguration, attempts uint) (*models.StationConfiguration, error) {
+	station, err := s.stationRepository.FindById(ctx, stationConfig.StationID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stationConfig.Sync = models.ConfigSync{
+		Status:      models.SyncPending,
+		Revision:    stationConfig.Sync.Revision + 1,
+		Attempts:    attempts,
+		PublishedAt: &now,
+	}
+
+	payload, err := json.Marshal(mappers.ToConfigurationMessage(stationConfig, now))
+	if err != nil {
+		return nil, err
+	}
+
+	topic := ConfigTopic(station.MacAddress)
+	if s.publisher == nil {
+		stationConfig.Sync.Status = models.SyncFailed
+		stationConfig.Sync.Error = ErrNoMessagePublisher.Error()
+	} else if err := s.publisher.Publish(topic, 1, true, payload); err != nil {
+		stationConfig.Sync.Status = models.SyncFailed
+		stationConfig.Sync.Error = err.Error()
+	}
+
+	if err := s.stationConfigRepository.UpdateSync(ctx, stationConfig.ID, stationConfig.Sync); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().
+		Uint("station_id", stationConfig.StationID).
+		Uint("revision", stationConfig.Sync.Revision).
+		Uint("attempts", stationConfig.Sync.Attempts).
+		Str("topic", topic).
+		Str("status", string(stationConfig.Sync.Status)).
+		Msg("Pushed station configuration")
+
+	s.publishEvent(stationConfig)
+	return stationConfig, nil
+}
+
+// Acknowledge records the device's answer to a pushed revision. Answers for
+// an older revision are ignored.
+func (s *ConfigSyncService) Acknowledge(ctx context.Context, macAddress string, revision uint, applied bool, message string) error {
+	stationConfig, err := s.findByMac(ctx, macAddress)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	update := models.ConfigSync{Status: models.SyncApplied, AcknowledgedAt: &now}
+	if !applied {
+		update.Status = models.SyncFailed
+		update.Error = message
+	}
+
+	return s.transition(ctx, stationConfig, revision, update)
+}
+
+// Report stores the configuration a device reports as active. A report that
+// matches the desired configuration counts as acknowledgement of the pending
+// revision.
+func (s *ConfigSyncService) Report(ctx context.Context, macAddress string, reported models.ShadowState) error {
+	stationConfig, err := s.findByMac(ctx, macAddress)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stationConfig.Reported = models.ReportedConfiguration{
+		UWBMode:         reported.UWBMode,
+		UWBChannel:      reported.UWBChannel,
+		UWBPreambleCode: reported.UWBPreambleCode,
+		UWBPreambleLen:  reported.UWBPreambleLen,
+		UWBDataRate:     reported.UWBDataRate,
+		UWBPRF:          reported.UWBPRF,
+		UWBSFD:          reported.UWBSFD,
+		UWBTxPower:      reported.UWBTxPower,
+		UWBAntennaDelay: reported.UWBAntennaDelay,
+		Timestamp:       &now,
+	}
+
+	if err := s.stationConfigRepository.UpdateReported(ctx, stationConfig.ID, stationConfig.Reported); err != nil {
+		return err
+	}
+
+	if stationConfig.Sync.Status != models.SyncPending && stationConfig.Sync.Status != models.SyncTimedOut {
+		return nil
+	}
+
+	if len(stationConfig.DesiredState().Diff(reported)) > 0 {
+		return nil
+	}
+
+	return s.transition(ctx, stationConfig, stationConfig.Sync.Revision, models.ConfigSync{Status: models.SyncApplied, AcknowledgedAt: &now})
+}
+
+func (s *ConfigSyncService) Start() {
+	interval := s.config.CheckInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.expire()
+			}
+		}
+	}()
+}
+
+func (s *ConfigSyncService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *ConfigSyncService) expire() {
+	if s.config.AckTimeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stationConfigs, err := s.stationConfigRepository.FindPendingSince(ctx, time.Now().Add(-s.config.AckTimeout))
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to load pending configurations")
+		return
+	}
+
+	for _, stationConfig := range stationConfigs {
+		update := models.ConfigSync{Status: models.SyncTimedOut, Error: "no acknowledgement within " + s.config.AckTimeout.String()}
+		if err := s.transition(ctx, stationConfig, stationConfig.Sync.Revision, update); err != nil {
+			s.log.Error().Err(err).Uint("station_id", stationConfig.StationID).Msg("Failed to time out configuration")
+		}
+	}
+}
+
+// transition applies an update when the configuration is still waiting for
+// the given revision. A timed out revision can still be acknowledged late.
+func (s *ConfigSyncService) transition(ctx context.Context, stationConfig *models.StationConfiguration, revision uint, update models.ConfigSync) error {
+	if revision != stationConfig.Sync.Revision {
+		s.log.Debug().
+			Uint("station_id", stationConfig.StationID).
+			Uint("revision", revision).
+			Uint("current_revision", stationConfig.Sync.Revision).
+			Msg("Ignoring answer for outdated configuration revision")
+		return nil
+	}
+
+	if stationConfig.Sync.Status != models.SyncPending &&
+		!(stationConfig.Sync.Status == models.SyncTimedOut && update.Status != models.SyncTimedOut) {
+		return nil
+	}
+
+	changed, err := s.stationConfigRepository.UpdateSyncIf(ctx, stationConfig.ID, stationConfig.Sync.Status, revision, update)
+	if err != nil || !changed {
+		return err
+	}
+
+	stationConfig.Sync.Status = update.Status
+	stationConfig.Sync.AcknowledgedAt = update.AcknowledgedAt
+	stationConfig.Sync.Error = update.Error
+
+	s.log.Info().
+		Uint("station_id", stationConfig.StationID).
+		Uint("revision", revision).
+		Str("status", string(update.Status)).
+		Msg("Station configuration sync changed")
+
+	s.publishEvent(stationConfig)
+	return nil
+}
+
+func (s *ConfigSyncService) findByMac(ctx context.Context, macAddress string) (*models.StationConfiguration, error) {
+	station, err := s.stationRepository.FindByMac(ctx, macAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stationConfigRepository.FindByStationId(ctx, station.ID, nil)
+}
+
+func (s *ConfigSyncService) publishEvent(stationConfig *models.StationConfiguration) {
+	if s.eventPublisher == nil {
+		return
+	}
+
+	if err := s.eventPublisher.PublishConfigSyncEvent(stationConfig); err != nil {
+		s.log.Error().Err(err).Uint("station_id", stationConfig.StationID).Msg("Failed to publish config sync event")
+	}
+}
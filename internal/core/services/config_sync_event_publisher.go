@@ -0,0 +1,22 @@
+package services
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/mappers"
+)
+
+const ConfigSyncEventType = "config_sync"
+
+type ConfigSyncEventPublisher struct {
+	eventService *EventStreamService
+}
+
+func NewConfigSyncEventPublisher(eventService *EventStreamService) *ConfigSyncEventPublisher {
+	return &ConfigSyncEventPublisher{
+		eventService: eventService,
+	}
+}
+
+func (p *ConfigSyncEventPublisher) PublishConfigSyncEvent(config *models.StationConfiguration) error {
+	return p.eventService.Publish(ConfigSyncEventType, mappers.FromConfigSync(config))
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/core/validation"
+	"slices"
+)
+
+var (
+	ErrProfileInUse           = errors.New("configuration profile is still in use")
+	ErrNoStationConfiguration = errors.New("station has no configuration")
+	ErrStationNotFound        = errors.New("station does not exist")
+)
+
+type ConfigurationProfileService struct {
+	*BaseService[models.ConfigurationProfile]
+	profileRepository       *repositories.ConfigurationProfileRepository
+	stationRepository       *repositories.StationRepository
+	stationConfigRepository *repositories.StationConfigurationRepository
+	clusterRepository       *repositories.ClusterRepository
+	configSyncService       *ConfigSyncService
+	profileValidator        *validation.ProfileValidator
+	stationConfigValidator  *validation.StationConfigValidator
+	log                     zerolog.Logger
+}
+
+func NewConfigurationProfileService(
+	profileRepository *repositories.ConfigurationProfileRepository,
+	stationRepository *repositories.StationRepository,
+	stationConfigRepository *repositories.StationConfigurationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	configSyncService *ConfigSyncService,
+) *ConfigurationProfileService {
+	baseService := NewBaseService[models.ConfigurationProfile](
+		profileRepository,
+		"configuration-profile",
+	)
+
+	return &ConfigurationProfileService{
+		BaseService:             baseService,
+		profileRepository:       profileRepository,
+		stationRepository:       stationRepository,
+		stationConfigRepository: stationConfigRepository,
+		clusterRepository:       clusterRepository,
+		configSyncService:       configSyncService,
+		profileValidator:        validation.NewProfileValidator(),
+		stationConfigValidator:  validation.NewStationConfigValidator(),
+		log:                     logger.GetLogger("configuration-profile-service"),
+	}
+}
+
+func (s *ConfigurationProfileService) Create(ctx context.Context, profile *models.ConfigurationProfile, includeParam *string) (*models.ConfigurationProfile, error) {
+	profile.ApplyDefaults()
+	if err := s.profileValidator.Validate(profile); err != nil {
+		return nil, err
+	}
+
+	return s.BaseService.Create(ctx, profile, includeParam)
+}
+
+// UpdateFields validates the stored profile merged with the updated fields.
+// Stations using the profile only change once it is applied again.
+func (s *ConfigurationProfileService) UpdateFields(ctx context.Context, profile *models.ConfigurationProfile, fields []string, includeParam *string) (*models.ConfigurationProfile, error) {
+	existing, err := s.profileRepository.FindById(ctx, profile.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.profileValidator.Validate(mergeProfileFields(existing, profile, fields)); err != nil {
+		return nil, err
+	}
+
+	return s.BaseService.UpdateFields(ctx, profile, fields, includeParam)
+}
+
+// Delete refuses to delete a profile that stations, clusters or active
+// rollouts still refer to, resolving their configuration would fail
+// otherwise.
+func (s *ConfigurationProfileService) Delete(ctx context.Context, profile *models.ConfigurationProfile, includeParam *string) error {
+	references, err := s.profileRepository.DeleteUnreferenced(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	if references > 0 {
+		return fmt.Errorf("%w by %d stations, clusters or rollouts", ErrProfileInUse, references)
+	}
+
+	return nil
+}
+
+// GetEffective resolves the configuration a station should run: the station
+// profile, or else the profile of its cluster, followed by its overrides.
+func (s *ConfigurationProfileService) GetEffective(ctx context.Context, stationId uint) (*models.EffectiveConfiguration, error) {
+	station, err := s.stationRepository.FindById(ctx, stationId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stationConfig, err := s.stationConfigRepository.FindByStationId(ctx, stationId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProfileResolver(s).resolve(ctx, station, stationConfig)
+}
+
+func (s *ConfigurationProfileService) ApplyToStation(ctx context.Context, stationId uint) (*models.ConfigApplyReport, error) {
+	station, err := s.stationRepository.FindById(ctx, stationId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stationConfigs, err := s.stationConfigRepository.FindByStationIds(ctx, []uint{stationId}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apply(ctx, nil, []*models.Station{station}, stationConfigs, nil, models.ProfileRevision)
+}
+
+func (s *ConfigurationProfileService) ApplyToCluster(ctx context.Context, clusterId uint) (*models.ConfigApplyReport, error) {
+	return s.ApplyToClusterWith(ctx, clusterId, nil, models.ProfileRevision)
+}
+
+// ApplyToClusterWith applies the cluster after prepare changed the stored
+// configuration of every member, e.g. its overrides.
+func (s *ConfigurationProfileService) ApplyToClusterWith(
+	ctx context.Context,
+	clusterId uint,
+	prepare func(stationConfig *models.StationConfiguration),
+	source models.RevisionSource,
+) (*models.ConfigApplyReport, error) {
+	if _, err := s.clusterRepository.FindById(ctx, clusterId, nil); err != nil {
+		return nil, err
+	}
+
+	stations, err := s.stationRepository.FindByCluster(ctx, clusterId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stationIds := make([]uint, len(stations))
+	for i, station := range stations {
+		stationIds[i] = station.ID
+	}
+
+	stationConfigs := make([]*models.StationConfiguration, 0)
+	if len(stationIds) > 0 {
+		stationConfigs, err = s.stationConfigRepository.FindByStationIds(ctx, stationIds, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.apply(ctx, &clusterId, stations, stationConfigs, prepare, source)
+}
+
+// AssignToStations assigns the profile to the given stations and applies
+// their resulting effective configuration. Unknown station IDs are reported
+// as skipped.
+func (s *ConfigurationProfileService) AssignToStations(ctx context.Context, profileId uint, stationIds []uint, source models.RevisionSource) (*models.ConfigApplyReport, error) {
+	if _, err := s.profileRepository.FindById(ctx, profileId, nil); err != nil {
+		return nil, err
+	}
+
+	stations, err := s.stationRepository.FindByIds(ctx, stationIds, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stationConfigs, err := s.stationConfigRepository.FindByStationIds(ctx, stationIds, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.apply(ctx, nil, stations, stationConfigs, func(stationConfig *models.StationConfiguration) {
+		stationConfig.ProfileID = &profileId
+	}, source)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(stations))
+	for _, station := range stations {
+		found[station.ID] = true
+	}
+
+	for _, stationId := range stationIds {
+		if !found[stationId] {
+			found[stationId] = true
+			report.Results = append(report.Results, &models.ConfigApplyResult{StationID: stationId, Status: models.ConfigSkipped, Error: ErrStationNotFound})
+			report.Skipped++
+		}
+	}
+
+	return report, nil
+}
+
+// apply resolves and validates the effective configuration of every station
+// and stores all changed ones together with their revisions in a single
+// transaction. A non-nil prepare changes a copy of every stored configuration
+// first, e.g. to assign a profile.
+// Invalid stations and stations without a configuration are reported and
+// skipped, a storage error leaves every station untouched. Configurations with changed radio parameters are pushed
+// to their devices after the commit.
+func (s *ConfigurationProfileService) apply(
+	ctx context.Context,
+	clusterId *uint,
+	stations []*models.Station,
+	stationConfigs []*models.StationConfiguration,
+	prepare func(stationConfig *models.StationConfiguration),
+	source models.RevisionSource,
+) (*models.ConfigApplyReport, error) {
+	configsByStation := make(map[uint]*models.StationConfiguration, len(stationConfigs))
+	for _, stationConfig := range stationConfigs {
+		configsByStation[stationConfig.StationID] = stationConfig
+	}
+
+	report := &models.ConfigApplyReport{
+		ClusterID: clusterId,
+		Results:   make([]*models.ConfigApplyResult, 0, len(stations)),
+	}
+
+	resolver := newProfileResolver(s)
+	changed := make([]*models.StationConfiguration, 0)
+	pushed := make([]*models.StationConfiguration, 0)
+	revisions := make([]*models.ConfigurationRevision, 0)
+
+	for _, station := range stations {
+		stationConfig, exists := configsByStation[station.ID]
+		if !exists {
+			report.Results = append(report.Results, &models.ConfigApplyResult{StationID: station.ID, Status: models.ConfigSkipped, Error: ErrNoStationConfiguration})
+			report.Skipped++
+			continue
+		}
+
+		prepared := *stationConfig
+		if prepare != nil {
+			prepare(&prepared)
+		}
+
+		effective, err := resolver.resolve(ctx, station, &prepared)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &models.ConfigApplyResult{StationID: station.ID, Effective: effective}
+		report.Results = append(report.Results, result)
+
+		if len(effective.Masked) > 0 {
+			report.Masked++
+			s.log.Warn().
+				Uint("station_id", station.ID).
+				Strs("fields", effective.Masked).
+				Msg("Station overrides mask profile settings")
+		}
+
+		if err := s.stationConfigValidator.Validate(effective.Configuration); err != nil {
+			result.Status = models.ConfigInvalid
+			result.Error = err
+			report.Invalid++
+			continue
+		}
+
+		radio := radioChanged(stationConfig, effective.Configuration)
+		if !radio && len(stationConfig.Snapshot().Diff(effective.Configuration.Snapshot())) == 0 {
+			result.Status = models.ConfigUnchanged
+			report.Unchanged++
+			continue
+		}
+
+		result.Status = models.ConfigApplied
+		report.Applied++
+		changed = append(changed, effective.Configuration)
+		if radio {
+			pushed = append(pushed, effective.Configuration)
+		}
+
+		if revision := newRevision(ctx, stationConfig, effective.Configuration, source); revision != nil {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	if len(changed) > 0 {
+		if err := s.stationConfigRepository.UpdateRadioBatch(ctx, changed, revisions); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, stationConfig := range pushed {
+		if _, err := s.configSyncService.Push(ctx, stationConfig); err != nil {
+			s.log.Error().Err(err).Uint("station_id", stationConfig.StationID).Msg("Failed to push applied configuration")
+		}
+	}
+
+	s.log.Info().
+		Int("applied", report.Applied).
+		Int("unchanged", report.Unchanged).
+		Int("invalid", report.Invalid).
+		Int("skipped", report.Skipped).
+		Int("masked", report.Masked).
+		Msg("Applied configuration profiles")
+
+	return report, nil
+}
+
+// profileResolver caches profiles and cluster profile assignments for the
+// duration of a single resolution pass.
+type profileResolver struct {
+	service         *ConfigurationProfileService
+	profiles        map[uint]*models.ConfigurationProfile
+	clusterProfiles map[uint]*uint
+}
+
+func newProfileResolver(service *ConfigurationProfileService) *profileResolver {
+	return &profileResolver{
+		service:         service,
+		profiles:        make(map[uint]*models.ConfigurationProfile),
+		clusterProfiles: make(map[uint]*uint),
+	}
+}
+
+func (r *profileResolver) resolve(ctx context.Context, station *models.Station, stationConfig *models.StationConfiguration) (*models.EffectiveConfiguration, error) {
+	configuration := *stationConfig
+	effective := &models.EffectiveConfiguration{
+		StationID:     station.ID,
+		ProfileSource: models.StationSource,
+		Configuration: &configuration,
+		Sources:       make(map[string]models.ConfigSource),
+	}
+
+	profileId, source := stationConfig.ProfileID, models.ProfileSource
+	if profileId == nil && station.ClusterID != nil {
+		clusterProfileId, err := r.clusterProfile(ctx, *station.ClusterID)
+		if err != nil {
+			return nil, err
+		}
+		profileId, source = clusterProfileId, models.ClusterProfileSource
+	}
+
+	fieldSource := models.StationSource
+	if profileId != nil {
+		profile, err := r.profile(ctx, *profileId)
+		if err != nil {
+			return nil, err
+		}
+
+		configuration.ApplyProfile(profile)
+		effective.ProfileID = profileId
+		effective.ProfileSource = source
+		fieldSource = source
+	}
+
+	for _, field := range models.ProfileFields {
+		effective.Sources[field] = fieldSource
+	}
+
+	profiled := configuration.Snapshot()
+	overridden := configuration.ApplyOverrides(stationConfig.Overrides)
+	for _, field := range overridden {
+		effective.Sources[field] = models.OverrideSource
+	}
+
+	effective.Masked = make([]string, 0)
+	if profileId != nil && len(overridden) > 0 {
+		for _, change := range profiled.Diff(configuration.Snapshot()) {
+			if slices.Contains(overridden, change.Field) {
+				effective.Masked = append(effective.Masked, change.Field)
+			}
+		}
+	}
+	for _, field := range []string{"uwb_mode", "uwb_chip", "uwb_antenna_delay"} {
+		effective.Sources[field] = models.StationSource
+	}
+
+	return effective, nil
+}
+
+func (r *profileResolver) profile(ctx context.Context, profileId uint) (*models.ConfigurationProfile, error) {
+	if profile, exists := r.profiles[profileId]; exists {
+		return profile, nil
+	}
+
+	profile, err := r.service.profileRepository.FindById(ctx, profileId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.profiles[profileId] = profile
+	return profile, nil
+}
+
+func (r *profileResolver) clusterProfile(ctx context.Context, clusterId uint) (*uint, error) {
+	if profileId, exists := r.clusterProfiles[clusterId]; exists {
+		return profileId, nil
+	}
+
+	cluster, err := r.service.clusterRepository.FindById(ctx, clusterId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.clusterProfiles[clusterId] = cluster.ProfileID
+	return cluster.ProfileID, nil
+}
+
+func radioChanged(before *models.StationConfiguration, after *models.StationConfiguration) bool {
+	if (before.UWBTxPower == nil) != (after.UWBTxPower == nil) ||
+		(before.UWBTxPower != nil && *before.UWBTxPower != *after.UWBTxPower) {
+		return true
+	}
+
+	return before.UWBChannel != after.UWBChannel ||
+		before.UWBPreambleCode != after.UWBPreambleCode ||
+		before.UWBPreambleLen != after.UWBPreambleLen ||
+		before.UWBDataRate != after.UWBDataRate ||
+		before.UWBPRF != after.UWBPRF ||
+		before.UWBSFD != after.UWBSFD
+}
+
+func mergeProfileFields(existing *models.ConfigurationProfile, update *models.ConfigurationProfile, fields []string) *models.ConfigurationProfile {
+	merged := *existing
+
+	for _, field := range fields {
+		switch field {
+		case "name":
+			merged.Name = update.Name
+		case "uwb_channel":
+			merged.UWBChannel = update.UWBChannel
+		case "uwb_preamble_code":
+			merged.UWBPreambleCode = update.UWBPreambleCode
+		case "uwb_preamble_len":
+			merged.UWBPreambleLen = update.UWBPreambleLen
+		case "uwb_data_rate":
+			merged.UWBDataRate = update.UWBDataRate
+		case "uwb_prf":
+			merged.UWBPRF = update.UWBPRF
+		case "uwb_sfd":
+			merged.UWBSFD = update.UWBSFD
+		case "uwb_tx_power":
+			merged.UWBTxPower = update.UWBTxPower
+		}
+	}
+
+	return &merged
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"gps-no-server/internal/core/repositories"
+	"math"
+	"sort"
+)
+
+var ErrCoverageTooLarge = errors.New("coverage grid exceeds the configured cell limit")
+
+// coverageCells is the number of cells along the longer side of the area
+// when no resolution is requested.
+const coverageCells = 100
+
+type CoverageService struct {
+	stationRepository *repositories.StationRepository
+	clusterRepository *repositories.ClusterRepository
+	config            *config.PositioningConfig
+	log               zerolog.Logger
+}
+
+func NewCoverageService(
+	stationRepository *repositories.StationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	cfg *config.PositioningConfig,
+) *CoverageService {
+	return &CoverageService{
+		stationRepository: stationRepository,
+		clusterRepository: clusterRepository,
+		config:            cfg,
+		log:               logger.GetLogger("coverage-service"),
+	}
+}
+
+// Compute evaluates the expected HDOP over an area of the cluster. The
+// positioned anchors of the cluster are combined with the hypothetical
+// anchors of the request; a hypothetical anchor referencing a station moves
+// that station instead of adding a new one.
+func (s *CoverageService) Compute(ctx context.Context, clusterId uint, request *models.CoverageRequest) (*models.CoverageMap, error) {
+	cluster, err := s.clusterRepository.FindById(ctx, clusterId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors, err := s.collectAnchors(ctx, clusterId, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests use the cluster units while DOP is computed in metres.
+	scale := unitScale(cluster)
+
+	result := &models.CoverageMap{
+		ClusterID:  clusterId,
+		Units:      cluster.Frame.Units,
+		Resolution: request.Resolution,
+		Height:     request.Height,
+		Dimension:  request.Dimension,
+		Anchors:    anchors,
+	}
+
+	if result.Dimension != 3 {
+		result.Dimension = 2
+	}
+
+	if request.MaxRange != nil {
+		result.MaxRange = *request.MaxRange
+	} else {
+		result.MaxRange = s.config.CoverageMaxRange / scale
+	}
+
+	s.resolveArea(result, request, scale)
+
+	options := positioning.CoverageOptions{
+		MinX:       result.MinX * scale,
+		MinY:       result.MinY * scale,
+		MaxX:       result.MaxX * scale,
+		MaxY:       result.MaxY * scale,
+		Resolution: result.Resolution * scale,
+		Height:     result.Height * scale,
+		MaxRange:   result.MaxRange * scale,
+		Dimension:  result.Dimension,
+	}
+
+	if _, _, err := options.Size(); err != nil {
+		return nil, err
+	}
+
+	if s.config.CoverageMaxCells > 0 && options.Cells() > float64(s.config.CoverageMaxCells) {
+		return nil, ErrCoverageTooLarge
+	}
+
+	positions := make([]positioning.Vector, 0, len(anchors))
+	for _, anchor := range anchors {
+		positions = append(positions, positioning.Vector{X: anchor.X * scale, Y: anchor.Y * scale, Z: anchor.Z * scale})
+	}
+
+	grid, err := positioning.ComputeCoverage(positions, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Columns = grid.Columns
+	result.Rows = grid.Rows
+	result.HDOP = grid.HDOP
+	result.Visible = grid.Visible
+
+	s.log.Debug().
+		Uint("cluster_id", clusterId).
+		Int("anchors", len(anchors)).
+		Int("columns", result.Columns).
+		Int("rows", result.Rows).
+		Msg("Computed coverage map")
+
+	return result, nil
+}
+
+func (s *CoverageService) collectAnchors(ctx context.Context, clusterId uint, request *models.CoverageRequest) ([]models.CoverageAnchor, error) {
+	anchors := make([]models.CoverageAnchor, 0)
+
+	if !request.ReplaceAnchors {
+		moved := make(map[uint]bool)
+		for _, anchor := range request.Anchors {
+			if anchor.StationID != nil {
+				moved[*anchor.StationID] = true
+			}
+		}
+
+		stations, err := s.stationRepository.FindByCluster(ctx, clusterId, map[string]bool{"config": true})
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Slice(stations, func(i, j int) bool { return stations[i].ID < stations[j].ID })
+
+		for _, station := range stations {
+			if station.StationConfig == nil || station.StationConfig.UWBMode != models.AnchorMode {
+				continue
+			}
+
+			if !station.Position.IsSet() || moved[station.ID] {
+				continue
+			}
+
+			stationId := station.ID
+			anchor := models.CoverageAnchor{StationID: &stationId, X: *station.Position.X, Y: *station.Position.Y}
+			if station.Position.Z != nil {
+				anchor.Z = *station.Position.Z
+			}
+			anchors = append(anchors, anchor)
+		}
+	}
+
+	for _, anchor := range request.Anchors {
+		anchor.Hypothetical = true
+		anchors = append(anchors, anchor)
+	}
+
+	return anchors, nil
+}
+
+// resolveArea fills the bounds that were not requested from the anchor
+// extent, padded by a tenth of its size but at least one metre.
+func (s *CoverageService) resolveArea(result *models.CoverageMap, request *models.CoverageRequest, scale float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, anchor := range result.Anchors {
+		minX, maxX = math.Min(minX, anchor.X), math.Max(maxX, anchor.X)
+		minY, maxY = math.Min(minY, anchor.Y), math.Max(maxY, anchor.Y)
+	}
+
+	if len(result.Anchors) > 0 {
+		margin := math.Max(0.1*math.Max(maxX-minX, maxY-minY), 1/scale)
+		minX, maxX = minX-margin, maxX+margin
+		minY, maxY = minY-margin, maxY+margin
+	}
+
+	result.MinX = valueOr(request.MinX, minX)
+	result.MinY = valueOr(request.MinY, minY)
+	result.MaxX = valueOr(request.MaxX, maxX)
+	result.MaxY = valueOr(request.MaxY, maxY)
+
+	if result.Resolution <= 0 {
+		extent := math.Max(result.MaxX-result.MinX, result.MaxY-result.MinY)
+		result.Resolution = extent / coverageCells
+	}
+}
+
+func valueOr(value *float64, fallback float64) float64 {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
@@ -68,6 +68,14 @@ func (s *EventStreamService) Publish(eventType string, data interface{}) error {
 
 	if ranging, ok := data.(*dtos.RangingDto); ok && ranging.ID > 0 {
 		rangingID = ranging.ID
+	} else if position, ok := data.(*dtos.PositionDto); ok {
+		rangingID = position.StationID
+	} else if zoneEvent, ok := data.(*dtos.ZoneEventDto); ok {
+		rangingID = zoneEvent.ZoneID
+	} else if presenceEvent, ok := data.(*dtos.PresenceEventDto); ok {
+		rangingID = presenceEvent.StationID
+	} else if configSync, ok := data.(*dtos.ConfigSyncDto); ok {
+		rangingID = configSync.ConfigurationID
 	} else if rangingMap, ok := data.(map[string]interface{}); ok {
 		if id, exists := rangingMap["id"]; exists {
 			if idFloat, ok := id.(float64); ok {
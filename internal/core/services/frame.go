@@ -0,0 +1,84 @@
+package services
+
+import (
+	"gps-no-server/internal/core/geo"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"math"
+)
+
+// unitScale returns the metres per unit of the coordinates stored for a
+// cluster. Positions computed by the services are always in metres.
+func unitScale(cluster *models.Cluster) float64 {
+	if cluster == nil {
+		return 1
+	}
+
+	scale, err := geo.UnitScale(cluster.Frame.Units)
+	if err != nil {
+		return 1
+	}
+
+	return scale
+}
+
+// georeference converts a local position in metres to WGS84. It returns nil
+// for clusters without a geodetic origin.
+func georeference(cluster *models.Cluster, x, y, z float64) *models.GeoPosition {
+	if cluster == nil || !cluster.Frame.IsGeoreferenced() {
+		return nil
+	}
+
+	frame := geo.Frame{
+		Origin: geo.Geodetic{
+			Latitude:  *cluster.Frame.OriginLatitude,
+			Longitude: *cluster.Frame.OriginLongitude,
+		},
+		Scale: 1,
+	}
+
+	if cluster.Frame.OriginAltitude != nil {
+		frame.Origin.Altitude = *cluster.Frame.OriginAltitude
+	}
+
+	if cluster.Frame.Rotation != nil {
+		frame.Rotation = *cluster.Frame.Rotation
+	}
+
+	position := frame.ToGeodetic(x, y, z)
+
+	return &models.GeoPosition{
+		Latitude:  position.Latitude,
+		Longitude: position.Longitude,
+		Altitude:  position.Altitude,
+	}
+}
+
+// geoDOP rotates the horizontal error ellipse of a solution from the local
+// frame to east and north. Both values are zero for clusters without a
+// geodetic origin.
+func geoDOP(cluster *models.Cluster, dop positioning.DOP) (float64, float64) {
+	if cluster == nil || !cluster.Frame.IsGeoreferenced() {
+		return 0, 0
+	}
+
+	angle := 0.0
+	if cluster.Frame.Rotation != nil {
+		angle = *cluster.Frame.Rotation * math.Pi / 180
+	}
+
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	xx, yy := dop.XDOP*dop.XDOP, dop.YDOP*dop.YDOP
+	east := cos*cos*xx + sin*sin*yy - 2*cos*sin*dop.XYCofactor
+	north := sin*sin*xx + cos*cos*yy + 2*cos*sin*dop.XYCofactor
+
+	return dopFromVariance(east), dopFromVariance(north)
+}
+
+func dopFromVariance(variance float64) float64 {
+	value := math.Sqrt(math.Max(variance, 0))
+	if math.IsNaN(value) || value > positioning.MaxDOP {
+		return positioning.MaxDOP
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"math"
+	"testing"
+)
+
+func TestGeoDOP(t *testing.T) {
+	georeferenced := func(rotation float64) *models.Cluster {
+		latitude, longitude := 48.1, 11.5
+		cluster := &models.Cluster{}
+		cluster.Frame.OriginLatitude = &latitude
+		cluster.Frame.OriginLongitude = &longitude
+		cluster.Frame.Rotation = &rotation
+		return cluster
+	}
+
+	elongated := positioning.DOP{XDOP: 2, YDOP: 1}
+	diagonal := positioning.DOP{XDOP: 1, YDOP: 1, XYCofactor: 0.5}
+
+	tests := []struct {
+		name    string
+		cluster *models.Cluster
+		dop     positioning.DOP
+		east    float64
+		north   float64
+	}{
+		{name: "not georeferenced", cluster: &models.Cluster{}, dop: elongated},
+		{name: "x points east", cluster: georeferenced(0), dop: elongated, east: 2, north: 1},
+		{name: "x points north", cluster: georeferenced(90), dop: elongated, east: 1, north: 2},
+		{name: "correlated axes rotated by 45 degrees", cluster: georeferenced(45), dop: diagonal, east: math.Sqrt(0.5), north: math.Sqrt(1.5)},
+		{name: "degenerate geometry", cluster: georeferenced(30), dop: positioning.DOP{XDOP: positioning.MaxDOP, YDOP: positioning.MaxDOP}, east: positioning.MaxDOP, north: positioning.MaxDOP},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			east, north := geoDOP(test.cluster, test.dop)
+			if math.Abs(east-test.east) > 1e-9 || math.Abs(north-test.north) > 1e-9 {
+				t.Errorf("dop = (%f, %f), want (%f, %f)", east, north, test.east, test.north)
+			}
+		})
+	}
+}
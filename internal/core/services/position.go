@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"gps-no-server/internal/core/repositories"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrStationNotTag       = errors.New("station is not configured as a tag")
+	ErrStationNotInCluster = errors.New("station is not assigned to a cluster")
+)
+
+type PositionService struct {
+	stationRepository       *repositories.StationRepository
+	stationConfigRepository *repositories.StationConfigurationRepository
+	clusterRepository       *repositories.ClusterRepository
+	rangingRepository       *repositories.RangingRepository
+	trackingService         *TrackingService
+	zoneEvaluator           *ZoneEvaluator
+	eventPublisher          *PositionEventPublisher
+	config                  *config.PositioningConfig
+	fixes                   map[uint]*models.PositionFix
+	fixLock                 sync.RWMutex
+	log                     zerolog.Logger
+}
+
+func NewPositionService(
+	stationRepository *repositories.StationRepository,
+	stationConfigRepository *repositories.StationConfigurationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	rangingRepository *repositories.RangingRepository,
+	trackingService *TrackingService,
+	zoneEvaluator *ZoneEvaluator,
+	eventStreamService *EventStreamService,
+	cfg *config.PositioningConfig,
+) *PositionService {
+	service := &PositionService{
+		stationRepository:       stationRepository,
+		stationConfigRepository: stationConfigRepository,
+		clusterRepository:       clusterRepository,
+		rangingRepository:       rangingRepository,
+		trackingService:         trackingService,
+		zoneEvaluator:           zoneEvaluator,
+		config:                  cfg,
+		fixes:                   make(map[uint]*models.PositionFix),
+		log:                     logger.GetLogger("position-service"),
+	}
+
+	if eventStreamService != nil {
+		service.eventPublisher = NewPositionEventPublisher(eventStreamService)
+	}
+
+	return service
+}
+
+func (s *PositionService) GetLatest(stationId uint) (*models.PositionFix, bool) {
+	s.fixLock.RLock()
+	defer s.fixLock.RUnlock()
+
+	fix, exists := s.fixes[stationId]
+	return fix, exists
+}
+
+func (s *PositionService) GetAll() []*models.PositionFix {
+	s.fixLock.RLock()
+	defer s.fixLock.RUnlock()
+
+	fixes := make([]*models.PositionFix, 0, len(s.fixes))
+	for _, fix := range s.fixes {
+		fixes = append(fixes, fix)
+	}
+
+	sort.Slice(fixes, func(i, j int) bool {
+		return fixes[i].StationID < fixes[j].StationID
+	})
+
+	return fixes
+}
+
+// Solve computes the current position of a tag from its recent rangings
+// without storing, tracking or publishing it.
+func (s *PositionService) Solve(ctx context.Context, stationId uint) (*models.PositionFix, error) {
+	fix, _, err := s.solve(ctx, stationId)
+	if err != nil {
+		return nil, err
+	}
+	return fix, nil
+}
+
+// solve returns the fix together with the scale from cluster units to
+// metres. When the solver fails, the error comes with a fix of type none so
+// callers can replace the previous one.
+func (s *PositionService) solve(ctx context.Context, stationId uint) (*models.PositionFix, float64, error) {
+	stationConfig, err := s.stationConfigRepository.FindByStationId(ctx, stationId, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if stationConfig.UWBMode != models.TagMode {
+		return nil, 0, ErrStationNotTag
+	}
+
+	station, err := s.stationRepository.FindById(ctx, stationId, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if station.ClusterID == nil {
+		return nil, 0, ErrStationNotInCluster
+	}
+
+	cluster, err := s.clusterRepository.FindById(ctx, *station.ClusterID, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	scale := unitScale(cluster)
+
+	measurements, err := s.collectMeasurements(ctx, station, scale)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	options := positioning.DefaultSolverOptions()
+	options.Dimension = s.config.Dimension
+	options.MinVerticalSpread = s.config.MinVerticalSpread
+
+	solution, err := positioning.Solve(measurements, options)
+	if err != nil {
+		anchorIds := make([]uint, len(measurements))
+		for i, measurement := range measurements {
+			anchorIds[i] = measurement.AnchorID
+		}
+
+		return &models.PositionFix{
+			StationID:   station.ID,
+			ClusterID:   station.ClusterID,
+			AnchorCount: len(anchorIds),
+			AnchorIDs:   anchorIds,
+			FixType:     models.FixNone,
+			Level:       cluster.Frame.Level,
+			Timestamp:   time.Now(),
+		}, scale, err
+	}
+
+	edop, ndop := geoDOP(cluster, solution.DOP)
+	fix := &models.PositionFix{
+		StationID:   station.ID,
+		ClusterID:   station.ClusterID,
+		X:           solution.Position.X,
+		Y:           solution.Position.Y,
+		Z:           solution.Position.Z,
+		Dimension:   solution.Dimension,
+		Residual:    solution.Residual,
+		Quality:     solution.Quality,
+		AnchorCount: len(solution.AnchorIDs),
+		AnchorIDs:   solution.AnchorIDs,
+		GDOP:        solution.DOP.GDOP,
+		HDOP:        solution.DOP.HDOP,
+		VDOP:        solution.DOP.VDOP,
+		EDOP:        edop,
+		NDOP:        ndop,
+		FixType:     s.classify(solution),
+		Level:       cluster.Frame.Level,
+		Geo:         georeference(cluster, solution.Position.X, solution.Position.Y, solution.Position.Z),
+		Timestamp:   time.Now(),
+	}
+
+	return fix, scale, nil
+}
+
+// ingest solves the position of a tag after new rangings arrived, stores
+// the fix and feeds it to the tracker, the zone evaluator and subscribers.
+// A failed solve replaces the previous fix with one of type none, which is
+// published once when the station loses its fix. A tag without a fix or
+// without a cluster leaves all of its zones.
+func (s *PositionService) ingest(ctx context.Context, stationId uint) (*models.PositionFix, error) {
+	fix, scale, err := s.solve(ctx, stationId)
+	if err != nil {
+		if s.zoneEvaluator != nil && (fix != nil || errors.Is(err, ErrStationNotInCluster)) {
+			s.zoneEvaluator.Exit(stationId, time.Now())
+		}
+
+		if fix != nil {
+			s.fixLock.Lock()
+			previous := s.fixes[fix.StationID]
+			s.fixes[fix.StationID] = fix
+			s.fixLock.Unlock()
+
+			if s.eventPublisher != nil && (previous == nil || previous.FixType != models.FixNone) {
+				if err := s.eventPublisher.PublishPositionEvent(fix); err != nil {
+					s.log.Error().Err(err).Uint("station_id", fix.StationID).Msg("Failed to publish position event")
+				}
+			}
+		}
+		return nil, err
+	}
+
+	s.fixLock.Lock()
+	s.fixes[fix.StationID] = fix
+	s.fixLock.Unlock()
+
+	s.log.Debug().
+		Uint("station_id", fix.StationID).
+		Float64("x", fix.X).
+		Float64("y", fix.Y).
+		Float64("z", fix.Z).
+		Float64("residual", fix.Residual).
+		Float64("hdop", fix.HDOP).
+		Str("fix_type", string(fix.FixType)).
+		Msg("Updated station position")
+
+	if s.eventPublisher != nil {
+		if err := s.eventPublisher.PublishPositionEvent(fix); err != nil {
+			s.log.Error().Err(err).Uint("station_id", fix.StationID).Msg("Failed to publish position event")
+		}
+	}
+
+	x, y, z := fix.X, fix.Y, fix.Z
+	if s.trackingService != nil {
+		tracked, err := s.trackingService.Track(ctx, fix)
+		if err != nil {
+			s.log.Error().Err(err).Uint("station_id", fix.StationID).Msg("Failed to track station position")
+		} else {
+			x, y, z = tracked.X, tracked.Y, tracked.Z
+		}
+	}
+
+	// Zones are defined in the cluster's units, fixes are in metres.
+	if s.zoneEvaluator != nil {
+		if _, err := s.zoneEvaluator.Evaluate(ctx, fix.StationID, *fix.ClusterID, x/scale, y/scale, z/scale, fix.Dimension, fix.Timestamp); err != nil {
+			s.log.Error().Err(err).Uint("station_id", fix.StationID).Msg("Failed to evaluate zones")
+		}
+	}
+
+	return fix, nil
+}
+
+func (s *PositionService) UpdateForRangings(ctx context.Context, rangings []*models.Ranging) []*models.PositionFix {
+	stationSet := make(map[uint]bool)
+	for _, ranging := range rangings {
+		stationSet[*ranging.SourceID] = true
+		stationSet[*ranging.DestinationID] = true
+	}
+
+	stationIds := make([]uint, 0, len(stationSet))
+	for stationId := range stationSet {
+		stationIds = append(stationIds, stationId)
+	}
+
+	if len(stationIds) == 0 {
+		return nil
+	}
+
+	stationConfigs, err := s.stationConfigRepository.FindByStationIds(ctx, stationIds, nil)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to load station configurations")
+		return nil
+	}
+
+	fixes := make([]*models.PositionFix, 0)
+	for _, stationConfig := range stationConfigs {
+		if stationConfig.UWBMode != models.TagMode {
+			continue
+		}
+
+		fix, err := s.ingest(ctx, stationConfig.StationID)
+		if err != nil {
+			s.log.Debug().Err(err).Uint("station_id", stationConfig.StationID).Msg("Unable to update station position")
+			continue
+		}
+
+		fixes = append(fixes, fix)
+	}
+
+	return fixes
+}
+
+func (s *PositionService) classify(solution *positioning.Solution) models.FixType {
+	if (s.config.MaxHDOP > 0 && solution.DOP.HDOP > s.config.MaxHDOP) ||
+		(s.config.MaxResidual > 0 && solution.Residual > s.config.MaxResidual) {
+		return models.FixDegraded
+	}
+
+	if solution.Dimension == 3 {
+		return models.Fix3D
+	}
+
+	return models.Fix2D
+}
+
+// collectMeasurements pairs recent rangings with positioned anchors. Anchor
+// coordinates are converted from cluster units to metres.
+func (s *PositionService) collectMeasurements(ctx context.Context, station *models.Station, scale float64) ([]positioning.Measurement, error) {
+	clusterStations, err := s.stationRepository.FindByCluster(ctx, *station.ClusterID, map[string]bool{"config": true})
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := make(map[uint]positioning.Vector)
+	for _, anchor := range clusterStations {
+		if anchor.ID == station.ID || anchor.StationConfig == nil || anchor.StationConfig.UWBMode != models.AnchorMode {
+			continue
+		}
+
+		if !anchor.Position.IsSet() {
+			continue
+		}
+
+		position := positioning.Vector{X: *anchor.Position.X * scale, Y: *anchor.Position.Y * scale}
+		if anchor.Position.Z != nil {
+			position.Z = *anchor.Position.Z * scale
+		}
+		anchors[anchor.ID] = position
+	}
+
+	since := time.Now().Add(-s.config.MaxRangingAge)
+	rangings, err := s.rangingRepository.FindRecentByStation(ctx, station.ID, since, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	measurements := make([]positioning.Measurement, 0, len(anchors))
+	used := make(map[uint]bool)
+
+	for _, ranging := range rangings {
+		peerId := *ranging.DestinationID
+		if peerId == station.ID {
+			peerId = *ranging.SourceID
+		}
+
+		anchor, isAnchor := anchors[peerId]
+		if !isAnchor || used[peerId] {
+			continue
+		}
+
+		used[peerId] = true
+		measurements = append(measurements, positioning.Measurement{
+			AnchorID: peerId,
+			Anchor:   anchor,
+			Distance: ranging.Distance(),
+		})
+	}
+
+	return measurements, nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/models/mappers"
+)
+
+const PositionEventType = "position"
+
+type PositionEventPublisher struct {
+	eventService *EventStreamService
+}
+
+func NewPositionEventPublisher(eventService *EventStreamService) *PositionEventPublisher {
+	return &PositionEventPublisher{
+		eventService: eventService,
+	}
+}
+
+func (p *PositionEventPublisher) PublishPositionEvent(fix *models.PositionFix) error {
+	includeParam := "anchors"
+	positionDto := mappers.FromPositionFix(fix, &includeParam)
+
+	return p.eventService.Publish(PositionEventType, positionDto)
+}
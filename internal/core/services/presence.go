@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/events"
+	"sync"
+	"time"
+)
+
+const PresenceEventType = "presence"
+
+// PresenceService tracks when stations were last heard from. Any MQTT
+// message marks its stations online; a background watcher marks them offline
+// once they stay silent for longer than the configured timeout.
+type PresenceService struct {
+	stationRepository *repositories.StationRepository
+	eventBus          *events.StationEventBus
+	config            *config.PresenceConfig
+	stop              chan struct{}
+	stopOnce          sync.Once
+	log               zerolog.Logger
+}
+
+func NewPresenceService(
+	stationRepository *repositories.StationRepository,
+	eventBus *events.StationEventBus,
+	cfg *config.PresenceConfig,
+) *PresenceService {
+	return &PresenceService{
+		stationRepository: stationRepository,
+		eventBus:          eventBus,
+		config:            cfg,
+		stop:              make(chan struct{}),
+		log:               logger.GetLogger("presence-service"),
+	}
+}
+
+func (s *PresenceService) GetByStatus(ctx context.Context, status models.StationStatus) ([]*models.Station, error) {
+	return s.stationRepository.FindByStatus(ctx, status, nil)
+}
+
+func (s *PresenceService) Touch(ctx context.Context, macAddresses []string, seenAt time.Time) error {
+	if len(macAddresses) == 0 {
+		return nil
+	}
+
+	transitioned, err := s.stationRepository.MarkSeen(ctx, macAddresses, seenAt)
+	if err != nil {
+		return err
+	}
+
+	for _, station := range transitioned {
+		s.publish(events.StationWentOnline, station, seenAt)
+	}
+
+	return nil
+}
+
+// ReportUptime stores the uptime a device announces. A missing start time is
+// derived from the uptime.
+func (s *PresenceService) ReportUptime(ctx context.Context, stationId uint, startedAt *time.Time, uptimeSeconds int64, reportedAt time.Time) error {
+	if startedAt == nil && uptimeSeconds > 0 {
+		derived := reportedAt.Add(-time.Duration(uptimeSeconds) * time.Second)
+		startedAt = &derived
+	}
+
+	return s.stationRepository.UpdateUptime(ctx, stationId, startedAt, uptimeSeconds)
+}
+
+// MarkOffline handles an explicit offline notice such as an MQTT last will.
+func (s *PresenceService) MarkOffline(ctx context.Context, macAddress string, at time.Time) error {
+	station, err := s.stationRepository.FindByMac(ctx, macAddress, nil)
+	if err != nil {
+		return err
+	}
+
+	changed, err := s.stationRepository.MarkOffline(ctx, station.ID, at)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		s.publish(events.StationWentOffline, station, at)
+	}
+
+	return nil
+}
+
+func (s *PresenceService) Start() {
+	interval := s.config.CheckInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.expire()
+			}
+		}
+	}()
+}
+
+func (s *PresenceService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *PresenceService) expire() {
+	if s.config.OfflineTimeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	cutoff := now.Add(-s.config.OfflineTimeout)
+
+	stations, err := s.stationRepository.FindStale(ctx, cutoff)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to load stale stations")
+		return
+	}
+
+	for _, station := range stations {
+		changed, err := s.stationRepository.MarkOffline(ctx, station.ID, cutoff)
+		if err != nil {
+			s.log.Error().Err(err).Uint("station_id", station.ID).Msg("Failed to mark station offline")
+			continue
+		}
+
+		if changed {
+			s.publish(events.StationWentOffline, station, now)
+		}
+	}
+}
+
+func (s *PresenceService) publish(eventType events.StationEventType, station *models.Station, at time.Time) {
+	event := &events.StationEvent{
+		Type:      eventType,
+		StationId: station.ID,
+		Timestamp: at,
+	}
+
+	if station.ClusterID != nil {
+		event.ClusterId = *station.ClusterID
+	}
+
+	s.log.Info().
+		Uint("station_id", station.ID).
+		Str("mac", station.MacAddress).
+		Str("type", string(eventType)).
+		Msg("Station presence changed")
+
+	s.eventBus.Publish(event)
+}
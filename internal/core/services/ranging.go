@@ -2,11 +2,15 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
 	"gps-no-server/internal/common/logger"
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/repositories"
 	"gps-no-server/internal/infrastructure/http/dto"
+	"math"
+	"time"
 )
 
 type RangingService struct {
@@ -14,10 +18,18 @@ type RangingService struct {
 	rangingRepository *repositories.RangingRepository
 	stationService    *StationService
 	eventPublisher    *RangingEventPublisher
+	filterService     *RangingFilterService
+	config            *config.RangingConfig
 	log               zerolog.Logger
 }
 
-func NewRangingService(rangingRepository *repositories.RangingRepository, stationService *StationService, eventStreamService *EventStreamService) *RangingService {
+func NewRangingService(
+	rangingRepository *repositories.RangingRepository,
+	stationService *StationService,
+	eventStreamService *EventStreamService,
+	filterService *RangingFilterService,
+	cfg *config.RangingConfig,
+) *RangingService {
 	baseService := NewBaseService[models.Ranging](
 		rangingRepository,
 		"ranging",
@@ -27,6 +39,8 @@ func NewRangingService(rangingRepository *repositories.RangingRepository, statio
 		BaseService:       baseService,
 		rangingRepository: rangingRepository,
 		stationService:    stationService,
+		filterService:     filterService,
+		config:            cfg,
 		log:               logger.GetLogger("ranging-service"),
 	}
 
@@ -51,3 +65,154 @@ func (s *RangingService) GetBySourceStationAndDestinationStation(ctx context.Con
 
 	return ranging, nil
 }
+
+func (s *RangingService) UpsertBatch(ctx context.Context, samples []*models.RangingSample, includeParam *string) ([]*models.Ranging, error) {
+	includes := dto.ParseIncludes(includeParam)
+
+	stationsByMac, err := s.resolveStations(ctx, samples)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make(map[[2]uint]int)
+	batch := make([]*models.Ranging, 0, len(samples))
+	resolvedSamples := make([]*models.RangingSample, 0, len(samples))
+
+	for _, sample := range samples {
+		if sample.Source == nil || sample.Destination == nil {
+			continue
+		}
+
+		source, sourceExists := stationsByMac[sample.Source.MacAddress]
+		destination, destinationExists := stationsByMac[sample.Destination.MacAddress]
+		if !sourceExists || !destinationExists {
+			s.log.Warn().
+				Str("source", sample.Source.MacAddress).
+				Str("destination", sample.Destination.MacAddress).
+				Msg("Skipping ranging for unknown station")
+			continue
+		}
+
+		if sample.Timestamp.IsZero() {
+			sample.Timestamp = time.Now()
+		}
+		sample.SourceID = &source.ID
+		sample.DestinationID = &destination.ID
+		resolvedSamples = append(resolvedSamples, sample)
+
+		entry := &models.Ranging{
+			SourceID:       &source.ID,
+			DestinationID:  &destination.ID,
+			RawDistance:    sample.RawDistance,
+			ScaledDistance: sample.ScaledDistance,
+		}
+
+		if s.filterService != nil {
+			accepted, filteredDistance := s.filterService.Apply(sample)
+			if !accepted {
+				continue
+			}
+			entry.FilteredDistance = &filteredDistance
+		}
+
+		key := [2]uint{source.ID, destination.ID}
+		if index, exists := pairs[key]; exists {
+			batch[index] = entry
+			continue
+		}
+
+		pairs[key] = len(batch)
+		batch = append(batch, entry)
+	}
+
+	persisted, err := s.rangingRepository.UpsertBatch(ctx, batch, resolvedSamples, includes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert rangings: %w", err)
+	}
+
+	if s.eventPublisher != nil {
+		for _, ranging := range persisted {
+			if err := s.eventPublisher.PublishRangingEvent(ctx, ranging); err != nil {
+				s.log.Error().Err(err).Uint("id", ranging.ID).Msg("Failed to publish ranging event")
+			}
+		}
+	}
+
+	return persisted, nil
+}
+
+func (s *RangingService) GetHistory(ctx context.Context, filter repositories.RangingSampleFilter, includeParam *string) ([]*models.RangingSamplePoint, bool, error) {
+	includes := dto.ParseIncludes(includeParam)
+	return s.rangingRepository.FindSamples(ctx, filter, includes)
+}
+
+func (s *RangingService) GetStats(ctx context.Context, filter repositories.RangingStatsFilter, expectedRate float64, includeParam *string) ([]*models.RangingPairStats, error) {
+	includes := dto.ParseIncludes(includeParam)
+
+	if expectedRate <= 0 {
+		expectedRate = s.config.ExpectedRate
+	}
+
+	stats, err := s.rangingRepository.FindPairStats(ctx, filter, includes)
+	if err != nil {
+		return nil, err
+	}
+
+	window := filter.To.Sub(filter.From).Seconds()
+	for _, pairStats := range stats {
+		pairStats.ExpectedRate = expectedRate
+
+		span := pairStats.LastSeen.Sub(pairStats.FirstSeen).Seconds()
+		if pairStats.Count > 1 && span > 0 {
+			pairStats.UpdateRate = float64(pairStats.Count-1) / span
+		}
+
+		if expectedRate > 0 && window > 0 {
+			expectedCount := expectedRate * window
+			pairStats.LossRatio = math.Max(0, math.Min(1, 1-float64(pairStats.Count)/expectedCount))
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *RangingService) GetFilterStatistics() *models.RangingFilterStatistics {
+	if s.filterService == nil {
+		return &models.RangingFilterStatistics{RejectedByStage: map[string]uint64{}}
+	}
+
+	return s.filterService.GetStatistics()
+}
+
+func (s *RangingService) resolveStations(ctx context.Context, samples []*models.RangingSample) (map[string]*models.Station, error) {
+	macSet := make(map[string]bool)
+	for _, sample := range samples {
+		if sample.Source != nil {
+			macSet[sample.Source.MacAddress] = true
+		}
+		if sample.Destination != nil {
+			macSet[sample.Destination.MacAddress] = true
+		}
+	}
+
+	macs := make([]string, 0, len(macSet))
+	for mac := range macSet {
+		macs = append(macs, mac)
+	}
+
+	stationsByMac := make(map[string]*models.Station, len(macs))
+	if len(macs) == 0 {
+		return stationsByMac, nil
+	}
+
+	stations, err := s.stationService.GetByMacs(ctx, macs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve stations: %w", err)
+	}
+
+	for _, station := range stations {
+		stationsByMac[station.MacAddress] = station
+	}
+
+	return stationsByMac, nil
+}
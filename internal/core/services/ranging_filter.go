@@ -0,0 +1,140 @@
+package services
+
+import (
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/filtering"
+	"gps-no-server/internal/core/models"
+	"sort"
+	"sync"
+)
+
+type RangingFilterService struct {
+	chain      *filtering.Chain
+	statistics *models.RangingFilterStatistics
+	pairs      map[filtering.Pair]*models.RangingPairFilterStatistics
+	statsLock  sync.RWMutex
+	log        zerolog.Logger
+}
+
+func NewRangingFilterService(cfg *config.RangingConfig) (*RangingFilterService, error) {
+	chain, err := filtering.BuildChain(cfg.FilterStages, filtering.Options{
+		MinDistance:         cfg.FilterMinDistance,
+		MaxDistance:         cfg.FilterMaxDistance,
+		MaxSpeed:            cfg.FilterMaxSpeed,
+		RateResetAfter:      cfg.FilterRateResetAfter,
+		MedianWindow:        cfg.FilterMedianWindow,
+		HampelWindow:        cfg.FilterHampelWindow,
+		HampelThreshold:     cfg.FilterHampelThreshold,
+		HampelMinDeviation:  cfg.FilterHampelMinDeviation,
+		ReciprocalTolerance: cfg.FilterReciprocalTolerance,
+		ReciprocalMaxAge:    cfg.FilterReciprocalMaxAge,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RangingFilterService{
+		chain: chain,
+		statistics: &models.RangingFilterStatistics{
+			Stages:          chain.Stages(),
+			RejectedByStage: make(map[string]uint64),
+		},
+		pairs: make(map[filtering.Pair]*models.RangingPairFilterStatistics),
+		log:   logger.GetLogger("ranging-filter-service"),
+	}, nil
+}
+
+// Apply runs the filter chain on a resolved sample. Rejected samples are
+// flagged on the sample itself so they can still be persisted for inspection.
+// The returned distance is the value that should be used downstream.
+func (s *RangingFilterService) Apply(sample *models.RangingSample) (bool, float64) {
+	pair := filtering.Pair{SourceID: *sample.SourceID, DestinationID: *sample.DestinationID}
+
+	result := s.chain.Process(filtering.Sample{
+		Pair:      pair,
+		Timestamp: sample.Timestamp,
+		Distance:  sample.RawDistance,
+	})
+
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	pairStats, exists := s.pairs[pair]
+	if !exists {
+		pairStats = &models.RangingPairFilterStatistics{
+			SourceID:        pair.SourceID,
+			DestinationID:   pair.DestinationID,
+			RejectedByStage: make(map[string]uint64),
+		}
+		s.pairs[pair] = pairStats
+	}
+
+	s.statistics.Processed++
+	pairStats.Processed++
+
+	if result.Accepted {
+		s.statistics.Accepted++
+		return true, result.Distance
+	}
+
+	sample.Rejected = true
+	sample.RejectStage = result.Stage
+	sample.RejectReason = result.Reason
+
+	rejectedAt := sample.Timestamp
+	s.statistics.Rejected++
+	s.statistics.RejectedByStage[result.Stage]++
+	pairStats.Rejected++
+	pairStats.RejectedByStage[result.Stage]++
+	pairStats.LastRejectedAt = &rejectedAt
+	pairStats.LastReason = result.Reason
+
+	s.log.Debug().
+		Uint("source_id", pair.SourceID).
+		Uint("destination_id", pair.DestinationID).
+		Float64("distance", sample.RawDistance).
+		Str("stage", result.Stage).
+		Str("reason", result.Reason).
+		Msg("Rejected ranging sample")
+
+	return false, result.Distance
+}
+
+func (s *RangingFilterService) GetStatistics() *models.RangingFilterStatistics {
+	s.statsLock.RLock()
+	defer s.statsLock.RUnlock()
+
+	statistics := &models.RangingFilterStatistics{
+		Stages:          s.statistics.Stages,
+		Processed:       s.statistics.Processed,
+		Accepted:        s.statistics.Accepted,
+		Rejected:        s.statistics.Rejected,
+		RejectedByStage: copyCounters(s.statistics.RejectedByStage),
+		Pairs:           make([]*models.RangingPairFilterStatistics, 0, len(s.pairs)),
+	}
+
+	for _, pairStats := range s.pairs {
+		pairCopy := *pairStats
+		pairCopy.RejectedByStage = copyCounters(pairStats.RejectedByStage)
+		statistics.Pairs = append(statistics.Pairs, &pairCopy)
+	}
+
+	sort.Slice(statistics.Pairs, func(i, j int) bool {
+		if statistics.Pairs[i].SourceID != statistics.Pairs[j].SourceID {
+			return statistics.Pairs[i].SourceID < statistics.Pairs[j].SourceID
+		}
+		return statistics.Pairs[i].DestinationID < statistics.Pairs[j].DestinationID
+	})
+
+	return statistics
+}
+
+func copyCounters(counters map[string]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(counters))
+	for key, value := range counters {
+		result[key] = value
+	}
+	return result
+}
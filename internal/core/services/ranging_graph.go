@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"sort"
+	"time"
+)
+
+type RangingGraphService struct {
+	stationRepository *repositories.StationRepository
+	clusterRepository *repositories.ClusterRepository
+	rangingRepository *repositories.RangingRepository
+	log               zerolog.Logger
+}
+
+func NewRangingGraphService(
+	stationRepository *repositories.StationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	rangingRepository *repositories.RangingRepository,
+) *RangingGraphService {
+	return &RangingGraphService{
+		stationRepository: stationRepository,
+		clusterRepository: clusterRepository,
+		rangingRepository: rangingRepository,
+		log:               logger.GetLogger("ranging-graph-service"),
+	}
+}
+
+// GetGraph builds the connectivity graph of a cluster from the latest ranging
+// of every pair. Pairs without accepted samples between from and to are
+// kept but marked stale, so a station that dropped out is still visible.
+func (s *RangingGraphService) GetGraph(ctx context.Context, clusterId uint, from, to time.Time) (*models.RangingGraph, error) {
+	if _, err := s.clusterRepository.FindById(ctx, clusterId, nil); err != nil {
+		return nil, err
+	}
+
+	stations, err := s.stationRepository.FindByCluster(ctx, clusterId, map[string]bool{"config": true})
+	if err != nil {
+		return nil, err
+	}
+
+	rangings, err := s.rangingRepository.FindByCluster(ctx, clusterId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.rangingRepository.FindPairStats(ctx, repositories.RangingStatsFilter{
+		ClusterID: &clusterId,
+		From:      from,
+		To:        to,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type pairKey struct{ source, destination uint }
+	statsByPair := make(map[pairKey]*models.RangingPairStats, len(stats))
+	for _, pairStats := range stats {
+		statsByPair[pairKey{source: pairStats.SourceID, destination: pairStats.DestinationID}] = pairStats
+	}
+
+	graph := &models.RangingGraph{
+		ClusterID: clusterId,
+		From:      from,
+		To:        to,
+		Nodes:     make([]*models.RangingGraphNode, 0, len(stations)),
+		Edges:     make([]*models.RangingGraphEdge, 0, len(rangings)),
+	}
+
+	window := to.Sub(from).Seconds()
+	peers := make(map[uint]map[uint]bool)
+	for _, ranging := range rangings {
+		edge := &models.RangingGraphEdge{
+			SourceID:      *ranging.SourceID,
+			DestinationID: *ranging.DestinationID,
+			Distance:      ranging.RawDistance,
+			LastSeen:      ranging.UpdatedAt,
+			Stale:         true,
+		}
+
+		if pairStats, exists := statsByPair[pairKey{source: edge.SourceID, destination: edge.DestinationID}]; exists {
+			edge.SampleCount = pairStats.Count
+			edge.Stale = false
+			if window > 0 {
+				edge.SampleRate = float64(pairStats.Count) / window
+			}
+		}
+
+		graph.Edges = append(graph.Edges, edge)
+
+		if edge.Stale || edge.SourceID == edge.DestinationID {
+			continue
+		}
+
+		for _, pair := range [][2]uint{{edge.SourceID, edge.DestinationID}, {edge.DestinationID, edge.SourceID}} {
+			if peers[pair[0]] == nil {
+				peers[pair[0]] = make(map[uint]bool)
+			}
+			peers[pair[0]][pair[1]] = true
+		}
+	}
+
+	for _, station := range stations {
+		node := &models.RangingGraphNode{
+			StationID:  station.ID,
+			Name:       station.Name,
+			MacAddress: station.MacAddress,
+			Mode:       models.NoneMode,
+			Position:   station.Position,
+			Degree:     len(peers[station.ID]),
+		}
+
+		if station.StationConfig != nil {
+			node.Mode = station.StationConfig.UWBMode
+		}
+
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].StationID < graph.Nodes[j].StationID
+	})
+
+	return graph, nil
+}
@@ -0,0 +1,679 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/actor"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minGatedStations is the smallest wave whose stations can range with each
+// other, so its health can be compared with the baseline.
+const minGatedStations = 2
+
+var (
+	ErrRolloutInProgress     = errors.New("cluster already has an active rollout")
+	ErrRolloutNoStations     = errors.New("cluster has no configurable stations")
+	ErrRolloutTooFewStations = errors.New("cluster needs at least two configurable stations to check ranging health")
+	ErrInvalidRolloutWaves   = errors.New("waves must be increasing percentages between 1 and 100 ending at 100")
+	ErrInvalidFailureAction  = errors.New("failure action must be pause or rollback")
+	ErrInvalidRolloutLimits  = errors.New("settle time must be positive and health limits must be between 0 and 1")
+	ErrRolloutNotRunning     = errors.New("rollout is not running")
+	ErrRolloutNotPaused      = errors.New("rollout is not paused")
+	ErrRolloutNotActive      = errors.New("rollout is no longer active")
+)
+
+// RolloutService applies a profile to a cluster wave by wave. A wave is only
+// followed by the next one once the ranging health between the stations
+// updated so far has not degraded compared to before the rollout.
+//
+// Health is only measured between updated stations. A profile that changes
+// the channel or preamble code leaves them unable to range with stations
+// that were not updated yet, so those pairs would drop by construction. The
+// first wave therefore holds at least two stations.
+type RolloutService struct {
+	*BaseService[models.Rollout]
+	rolloutRepository       *repositories.RolloutRepository
+	profileRepository       *repositories.ConfigurationProfileRepository
+	clusterRepository       *repositories.ClusterRepository
+	stationRepository       *repositories.StationRepository
+	stationConfigRepository *repositories.StationConfigurationRepository
+	revisionRepository      *repositories.ConfigurationRevisionRepository
+	rangingRepository       *repositories.RangingRepository
+	profileService          *ConfigurationProfileService
+	stationConfigService    *StationConfigurationService
+	config                  *config.RolloutConfig
+	lock                    sync.Mutex
+	stop                    chan struct{}
+	stopOnce                sync.Once
+	log                     zerolog.Logger
+}
+
+func NewRolloutService(
+	rolloutRepository *repositories.RolloutRepository,
+	profileRepository *repositories.ConfigurationProfileRepository,
+	clusterRepository *repositories.ClusterRepository,
+	stationRepository *repositories.StationRepository,
+	stationConfigRepository *repositories.StationConfigurationRepository,
+	revisionRepository *repositories.ConfigurationRevisionRepository,
+	rangingRepository *repositories.RangingRepository,
+	profileService *ConfigurationProfileService,
+	stationConfigService *StationConfigurationService,
+	cfg *config.RolloutConfig,
+) *RolloutService {
+	baseService := NewBaseService[models.Rollout](
+		rolloutRepository,
+		"rollout",
+	)
+
+	return &RolloutService{
+		BaseService:             baseService,
+		rolloutRepository:       rolloutRepository,
+		profileRepository:       profileRepository,
+		clusterRepository:       clusterRepository,
+		stationRepository:       stationRepository,
+		stationConfigRepository: stationConfigRepository,
+		revisionRepository:      revisionRepository,
+		rangingRepository:       rangingRepository,
+		profileService:          profileService,
+		stationConfigService:    stationConfigService,
+		config:                  cfg,
+		stop:                    make(chan struct{}),
+		log:                     logger.GetLogger("rollout-service"),
+	}
+}
+
+func (s *RolloutService) GetByStatus(ctx context.Context, status models.RolloutStatus) ([]*models.Rollout, error) {
+	return s.rolloutRepository.FindByStatus(ctx, status)
+}
+
+// Create splits the stations of the cluster into the waves of the rollout,
+// records the ranging health between them as a baseline per wave and
+// applies the first wave.
+// Only the percentages of the given waves are used, unset limits fall back
+// to the configured defaults.
+func (s *RolloutService) Create(ctx context.Context, rollout *models.Rollout, includeParam *string) (*models.Rollout, error) {
+	s.applyDefaults(rollout)
+	if err := validateRollout(rollout); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.profileRepository.FindById(ctx, rollout.ProfileID, nil); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.clusterRepository.FindById(ctx, rollout.ClusterID, nil); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	active, err := s.rolloutRepository.FindActiveByCluster(ctx, rollout.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > 0 {
+		return nil, ErrRolloutInProgress
+	}
+
+	stations, err := s.stationRepository.FindByCluster(ctx, rollout.ClusterID, map[string]bool{"config": true})
+	if err != nil {
+		return nil, err
+	}
+
+	stationIds := make([]uint, 0, len(stations))
+	for _, station := range stations {
+		if station.StationConfig != nil {
+			stationIds = append(stationIds, station.ID)
+		}
+	}
+	if len(stationIds) == 0 {
+		return nil, ErrRolloutNoStations
+	}
+	if len(stationIds) < minGatedStations {
+		return nil, ErrRolloutTooFewStations
+	}
+	sort.Slice(stationIds, func(i, j int) bool { return stationIds[i] < stationIds[j] })
+
+	now := time.Now()
+	assigned := 0
+	for i := range rollout.Waves {
+		wave := &rollout.Waves[i]
+		count := waveEnd(len(stationIds), assigned, wave.Percent)
+
+		wave.StationIDs = stationIds[assigned:count]
+		wave.Status = models.WavePending
+		assigned = count
+
+		baseline, err := s.measure(ctx, stationIds[:count], now.Add(-rollout.SettleTime), now)
+		if err != nil {
+			return nil, err
+		}
+		wave.Baseline = baseline
+	}
+
+	rollout.Status = models.RolloutRunning
+	rollout.CurrentWave = 0
+	rollout.Actor = actor.FromContext(ctx)
+
+	if _, err := s.rolloutRepository.Create(ctx, rollout, nil); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().
+		Uint("rollout_id", rollout.ID).
+		Uint("profile_id", rollout.ProfileID).
+		Uint("cluster_id", rollout.ClusterID).
+		Int("stations", len(stationIds)).
+		Int("waves", len(rollout.Waves)).
+		Msg("Started configuration rollout")
+
+	if err := s.advance(ctx, rollout); err != nil {
+		return nil, err
+	}
+
+	return rollout, nil
+}
+
+func (s *RolloutService) Pause(ctx context.Context, id uint) (*models.Rollout, error) {
+	return s.transition(ctx, id, func(rollout *models.Rollout) error {
+		if rollout.Status != models.RolloutRunning {
+			return ErrRolloutNotRunning
+		}
+
+		rollout.Status = models.RolloutPaused
+		rollout.Message = "Paused by " + actor.FromContext(ctx)
+		return nil
+	})
+}
+
+// Resume continues a paused rollout. A wave that was applied or found
+// unhealthy is checked again after another settle time.
+func (s *RolloutService) Resume(ctx context.Context, id uint) (*models.Rollout, error) {
+	return s.transition(ctx, id, func(rollout *models.Rollout) error {
+		if rollout.Status != models.RolloutPaused {
+			return ErrRolloutNotPaused
+		}
+
+		wave := &rollout.Waves[rollout.CurrentWave]
+		if wave.Status == models.WaveApplied || wave.Status == models.WaveUnhealthy {
+			wave.Status = models.WaveApplied
+			nextCheck := time.Now().Add(rollout.SettleTime)
+			rollout.NextCheckAt = &nextCheck
+		}
+
+		rollout.Status = models.RolloutRunning
+		rollout.Message = ""
+		return s.advance(ctx, rollout)
+	})
+}
+
+// Rollback restores every configuration changed by the rollout to the
+// revision it had before its wave was applied.
+func (s *RolloutService) Rollback(ctx context.Context, id uint) (*models.Rollout, error) {
+	return s.transition(ctx, id, func(rollout *models.Rollout) error {
+		if !rollout.Status.IsActive() {
+			return ErrRolloutNotActive
+		}
+
+		s.rollback(ctx, rollout, "Rolled back by "+actor.FromContext(ctx))
+		return nil
+	})
+}
+
+// Abort stops the rollout and keeps the waves applied so far.
+func (s *RolloutService) Abort(ctx context.Context, id uint) (*models.Rollout, error) {
+	return s.transition(ctx, id, func(rollout *models.Rollout) error {
+		if !rollout.Status.IsActive() {
+			return ErrRolloutNotActive
+		}
+
+		rollout.Status = models.RolloutAborted
+		rollout.NextCheckAt = nil
+		rollout.Message = "Aborted by " + actor.FromContext(ctx)
+		return nil
+	})
+}
+
+// Process advances every running rollout whose current wave is due.
+func (s *RolloutService) Process(ctx context.Context) error {
+	rollouts, err := s.rolloutRepository.FindByStatus(ctx, models.RolloutRunning)
+	if err != nil {
+		return err
+	}
+
+	for _, rollout := range rollouts {
+		_, err := s.transition(ctx, rollout.ID, func(rollout *models.Rollout) error {
+			if rollout.Status != models.RolloutRunning {
+				return nil
+			}
+			return s.advance(ctx, rollout)
+		})
+		if err != nil {
+			s.log.Error().Err(err).Uint("rollout_id", rollout.ID).Msg("Failed to advance rollout")
+		}
+	}
+
+	return nil
+}
+
+func (s *RolloutService) Start() {
+	interval := s.config.CheckInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if err := s.Process(ctx); err != nil {
+					s.log.Error().Err(err).Msg("Failed to process rollouts")
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+func (s *RolloutService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// transition reloads the rollout, applies change and stores the result. The
+// lock serialises API actions with the background processing.
+func (s *RolloutService) transition(ctx context.Context, id uint, change func(rollout *models.Rollout) error) (*models.Rollout, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	rollout, err := s.rolloutRepository.FindById(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(rollout); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.rolloutRepository.Save(ctx, rollout, nil); err != nil {
+		return nil, err
+	}
+
+	return rollout, nil
+}
+
+// advance moves a running rollout forward as far as possible: it applies a
+// pending wave, or checks the health of an applied one once it settled and
+// then continues with the next wave, completes, pauses or rolls back.
+func (s *RolloutService) advance(ctx context.Context, rollout *models.Rollout) error {
+	for rollout.Status == models.RolloutRunning {
+		wave := &rollout.Waves[rollout.CurrentWave]
+		now := time.Now()
+
+		switch wave.Status {
+		case models.WavePending:
+			channelMasked, err := s.applyWave(ctx, rollout, wave, now)
+			if err != nil {
+				rollout.Status = models.RolloutPaused
+				rollout.Message = fmt.Sprintf("Failed to apply wave %d: %s", rollout.CurrentWave+1, err.Error())
+				s.log.Error().Err(err).Uint("rollout_id", rollout.ID).Int("wave", rollout.CurrentWave+1).Msg("Failed to apply rollout wave")
+				break
+			}
+
+			if len(channelMasked) > 0 {
+				s.fail(ctx, rollout, fmt.Sprintf("Wave %d left the channel or preamble code of stations %v to their overrides, e.g. from a channel plan", rollout.CurrentWave+1, channelMasked))
+				break
+			}
+
+			nextCheck := now.Add(rollout.SettleTime)
+			rollout.NextCheckAt = &nextCheck
+			return s.save(ctx, rollout)
+
+		case models.WaveApplied:
+			if rollout.NextCheckAt != nil && now.Before(*rollout.NextCheckAt) {
+				return nil
+			}
+
+			from := now.Add(-rollout.SettleTime)
+			if rollout.NextCheckAt != nil {
+				from = rollout.NextCheckAt.Add(-rollout.SettleTime)
+			}
+
+			stationIds := rollout.AppliedStationIDs()
+			health, err := s.measure(ctx, stationIds, from, now)
+			if err != nil {
+				return err
+			}
+			evaluateHealth(health, wave.Baseline, stationIds, rollout)
+
+			wave.Health = health
+			wave.CheckedAt = &now
+
+			if health.Degraded {
+				wave.Status = models.WaveUnhealthy
+				s.fail(ctx, rollout, fmt.Sprintf("Wave %d degraded ranging health: %s", rollout.CurrentWave+1, strings.Join(health.Reasons, ", ")))
+				break
+			}
+
+			wave.Status = models.WaveHealthy
+			if rollout.CurrentWave == len(rollout.Waves)-1 {
+				rollout.Status = models.RolloutCompleted
+				rollout.NextCheckAt = nil
+				rollout.Message = ""
+				s.log.Info().Uint("rollout_id", rollout.ID).Msg("Completed configuration rollout")
+				break
+			}
+
+			rollout.CurrentWave++
+
+		default:
+			return nil
+		}
+	}
+
+	return s.save(ctx, rollout)
+}
+
+// applyWave assigns the profile to the stations of the wave and remembers
+// the revision every changed configuration is restored to on rollback. A
+// configuration without history gets a baseline revision, version one. It
+// returns the stations whose overrides keep the channel or preamble code of
+// the profile from taking effect, which would leave them unable to range
+// with the rest of the wave.
+func (s *RolloutService) applyWave(ctx context.Context, rollout *models.Rollout, wave *models.RolloutWave, now time.Time) ([]uint, error) {
+	stationConfigs, err := s.stationConfigRepository.FindByStationIds(ctx, wave.StationIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	configurationIds := make([]uint, len(stationConfigs))
+	for i, stationConfig := range stationConfigs {
+		configurationIds[i] = stationConfig.ID
+	}
+
+	versions, err := s.revisionRepository.FindLatestVersions(ctx, configurationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.profileService.AssignToStations(ctx, rollout.ProfileID, wave.StationIDs, models.RolloutRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	wave.Restore = make(map[uint]uint)
+	wave.Invalid = make([]uint, 0)
+	wave.Masked = make([]uint, 0)
+	channelMasked := make([]uint, 0)
+	for _, result := range report.Results {
+		if result.Effective != nil && len(result.Effective.Masked) > 0 {
+			wave.Masked = append(wave.Masked, result.StationID)
+			if slices.Contains(result.Effective.Masked, "uwb_channel") || slices.Contains(result.Effective.Masked, "uwb_preamble_code") {
+				channelMasked = append(channelMasked, result.StationID)
+			}
+		}
+
+		switch result.Status {
+		case models.ConfigApplied:
+			configurationId := result.Effective.Configuration.ID
+			wave.Restore[configurationId] = max(versions[configurationId], 1)
+		case models.ConfigInvalid, models.ConfigSkipped:
+			wave.Invalid = append(wave.Invalid, result.StationID)
+		}
+	}
+
+	wave.Status = models.WaveApplied
+	wave.AppliedAt = &now
+
+	s.log.Info().
+		Uint("rollout_id", rollout.ID).
+		Int("wave", rollout.CurrentWave+1).
+		Int("applied", report.Applied).
+		Int("unchanged", report.Unchanged).
+		Int("invalid", report.Invalid).
+		Int("skipped", report.Skipped).
+		Int("masked", report.Masked).
+		Msg("Applied rollout wave")
+
+	return channelMasked, nil
+}
+
+func (s *RolloutService) fail(ctx context.Context, rollout *models.Rollout, reason string) {
+	s.log.Warn().Uint("rollout_id", rollout.ID).Str("reason", reason).Msg("Rollout wave is unhealthy")
+
+	if rollout.FailureAction == models.RollbackOnFailure {
+		s.rollback(ctx, rollout, reason)
+		return
+	}
+
+	rollout.Status = models.RolloutPaused
+	rollout.Message = reason
+}
+
+// rollback restores the applied waves in reverse order. Configurations that
+// fail to restore are named in the message and left for manual rollback.
+func (s *RolloutService) rollback(ctx context.Context, rollout *models.Rollout, reason string) {
+	failed := make([]string, 0)
+
+	for i := len(rollout.Waves) - 1; i >= 0; i-- {
+		wave := &rollout.Waves[i]
+		if wave.Status == models.WavePending || wave.Status == models.WaveRolledBack {
+			continue
+		}
+
+		for configurationId, version := range wave.Restore {
+			if _, err := s.stationConfigService.Rollback(ctx, configurationId, version, nil); err != nil {
+				s.log.Error().Err(err).Uint("rollout_id", rollout.ID).Uint("configuration_id", configurationId).Msg("Failed to roll back station configuration")
+				failed = append(failed, fmt.Sprintf("%d", configurationId))
+			}
+		}
+
+		wave.Status = models.WaveRolledBack
+	}
+
+	rollout.Status = models.RolloutRolledBack
+	rollout.NextCheckAt = nil
+	rollout.Message = reason
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		rollout.Message += "; failed to roll back configurations " + strings.Join(failed, ", ")
+	}
+
+	s.log.Info().Uint("rollout_id", rollout.ID).Int("failed", len(failed)).Msg("Rolled back configuration rollout")
+}
+
+func (s *RolloutService) save(ctx context.Context, rollout *models.Rollout) error {
+	_, err := s.rolloutRepository.Save(ctx, rollout, nil)
+	return err
+}
+
+// waveEnd returns how many of the stations are updated once the wave with
+// the given percentage is applied, when assigned were updated before it.
+// Every wave adds at least one station while any are left, and the first
+// holds at least minGatedStations.
+func waveEnd(stations int, assigned int, percent uint) int {
+	count := int(math.Ceil(float64(stations) * float64(percent) / 100))
+	return min(max(count, assigned+1, minGatedStations), stations)
+}
+
+// measure summarises the samples exchanged between the stations from from
+// to to, per pair and in total.
+func (s *RolloutService) measure(ctx context.Context, stationIds []uint, from time.Time, to time.Time) (*models.RolloutHealth, error) {
+	counts, err := s.rangingRepository.CountPairSamples(ctx, stationIds, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]models.RolloutPairHealth, len(counts))
+	for i, count := range counts {
+		pairs[i] = models.RolloutPairHealth{
+			SourceID:      count.SourceID,
+			DestinationID: count.DestinationID,
+			Samples:       count.Samples,
+			Rejected:      count.Rejected,
+		}
+	}
+
+	return summariseHealth(len(stationIds), pairs, from, to), nil
+}
+
+func summariseHealth(stations int, pairs []models.RolloutPairHealth, from time.Time, to time.Time) *models.RolloutHealth {
+	health := &models.RolloutHealth{
+		Stations: stations,
+		Pairs:    pairs,
+		From:     from,
+		To:       to,
+	}
+
+	for _, pair := range pairs {
+		health.Samples += pair.Samples
+		health.Rejected += pair.Rejected
+	}
+
+	if seconds := to.Sub(from).Seconds(); seconds > 0 && len(pairs) > 0 {
+		health.SampleRate = float64(health.Samples) / seconds / float64(len(pairs))
+	}
+	if health.Samples > 0 {
+		health.ErrorRate = float64(health.Rejected) / float64(health.Samples)
+	}
+
+	return health
+}
+
+// restrictHealth keeps the pairs of the health whose stations are both in
+// the given set and summarises them again. Pairs missing from the observed
+// health are kept with no samples when observed is given, so a pair that
+// stopped ranging counts as a drop.
+func restrictHealth(health *models.RolloutHealth, stationIds []uint, observed *models.RolloutHealth) *models.RolloutHealth {
+	stations := make(map[uint]bool, len(stationIds))
+	for _, stationId := range stationIds {
+		stations[stationId] = true
+	}
+
+	var counts map[[2]uint]models.RolloutPairHealth
+	if observed != nil {
+		counts = make(map[[2]uint]models.RolloutPairHealth, len(observed.Pairs))
+		for _, pair := range observed.Pairs {
+			counts[[2]uint{pair.SourceID, pair.DestinationID}] = pair
+		}
+	}
+
+	pairs := make([]models.RolloutPairHealth, 0, len(health.Pairs))
+	for _, pair := range health.Pairs {
+		if !stations[pair.SourceID] || !stations[pair.DestinationID] {
+			continue
+		}
+
+		if counts != nil {
+			pair = models.RolloutPairHealth{SourceID: pair.SourceID, DestinationID: pair.DestinationID}
+			if count, exists := counts[[2]uint{pair.SourceID, pair.DestinationID}]; exists {
+				pair = count
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+
+	from, to := health.From, health.To
+	if observed != nil {
+		from, to = observed.From, observed.To
+	}
+
+	return summariseHealth(len(stationIds), pairs, from, to)
+}
+
+func (s *RolloutService) applyDefaults(rollout *models.Rollout) {
+	if len(rollout.Waves) == 0 {
+		for _, percent := range s.config.Waves {
+			rollout.Waves = append(rollout.Waves, models.RolloutWave{Percent: uint(max(percent, 0))})
+		}
+	}
+	if rollout.FailureAction == "" {
+		rollout.FailureAction = models.RolloutFailureAction(s.config.FailureAction)
+	}
+	if rollout.SettleTime == 0 {
+		rollout.SettleTime = s.config.SettleTime
+	}
+	if rollout.MaxRateDrop == 0 {
+		rollout.MaxRateDrop = s.config.MaxRateDrop
+	}
+	if rollout.MaxErrorIncrease == 0 {
+		rollout.MaxErrorIncrease = s.config.MaxErrorIncrease
+	}
+}
+
+func validateRollout(rollout *models.Rollout) error {
+	if !rollout.FailureAction.IsValid() {
+		return ErrInvalidFailureAction
+	}
+
+	if rollout.SettleTime <= 0 ||
+		rollout.MaxRateDrop < 0 || rollout.MaxRateDrop > 1 ||
+		rollout.MaxErrorIncrease < 0 || rollout.MaxErrorIncrease > 1 {
+		return ErrInvalidRolloutLimits
+	}
+
+	previous := uint(0)
+	for _, wave := range rollout.Waves {
+		if wave.Percent <= previous || wave.Percent > 100 {
+			return ErrInvalidRolloutWaves
+		}
+		previous = wave.Percent
+	}
+	if previous != 100 {
+		return ErrInvalidRolloutWaves
+	}
+
+	return nil
+}
+
+// evaluateHealth marks the health as degraded when the per-pair sample rate
+// of the pairs that ranged before the rollout dropped, or the rejected ratio
+// between the stations rose, beyond the limits of the rollout. Only pairs
+// between the given stations are compared. Without a baseline pair between
+// them nothing can be compared, which counts as degraded rather than
+// letting the wave pass unchecked.
+func evaluateHealth(health *models.RolloutHealth, baseline *models.RolloutHealth, stationIds []uint, rollout *models.Rollout) {
+	health.Reasons = make([]string, 0)
+
+	if baseline == nil {
+		baseline = &models.RolloutHealth{}
+	}
+	before := restrictHealth(baseline, stationIds, nil)
+	after := restrictHealth(baseline, stationIds, health)
+
+	if len(before.Pairs) == 0 {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("no ranging between the %d updated stations before the rollout to compare against", len(stationIds)))
+		health.Degraded = true
+		return
+	}
+
+	if after.SampleRate < before.SampleRate*(1-rollout.MaxRateDrop) {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("sample rate of %d pairs dropped from %.2f Hz to %.2f Hz per pair", len(before.Pairs), before.SampleRate, after.SampleRate))
+	}
+
+	if health.ErrorRate > before.ErrorRate+rollout.MaxErrorIncrease {
+		health.Reasons = append(health.Reasons, fmt.Sprintf("error rate rose from %.1f%% to %.1f%%", before.ErrorRate*100, health.ErrorRate*100))
+	}
+
+	health.Degraded = len(health.Reasons) > 0
+}
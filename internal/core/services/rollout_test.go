@@ -0,0 +1,188 @@
+package services
+
+import (
+	"gps-no-server/internal/core/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func rolloutHealth(from time.Time, pairs ...models.RolloutPairHealth) *models.RolloutHealth {
+	return summariseHealth(0, pairs, from, from.Add(10*time.Second))
+}
+
+func TestRestrictHealth(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	baseline := rolloutHealth(from,
+		models.RolloutPairHealth{SourceID: 1, DestinationID: 2, Samples: 100, Rejected: 10},
+		models.RolloutPairHealth{SourceID: 1, DestinationID: 3, Samples: 200},
+		models.RolloutPairHealth{SourceID: 2, DestinationID: 3, Samples: 50},
+	)
+
+	t.Run("keeps pairs between the stations", func(t *testing.T) {
+		restricted := restrictHealth(baseline, []uint{1, 2}, nil)
+
+		if restricted.Stations != 2 || len(restricted.Pairs) != 1 {
+			t.Fatalf("restricted = %+v, want one pair between two stations", restricted)
+		}
+		if restricted.Samples != 100 || restricted.Rejected != 10 {
+			t.Errorf("samples = %d, rejected = %d, want 100 and 10", restricted.Samples, restricted.Rejected)
+		}
+		if math.Abs(restricted.SampleRate-10) > 1e-9 || math.Abs(restricted.ErrorRate-0.1) > 1e-9 {
+			t.Errorf("rates = %f Hz, %f, want 10 Hz and 0.1", restricted.SampleRate, restricted.ErrorRate)
+		}
+		if !restricted.From.Equal(baseline.From) || !restricted.To.Equal(baseline.To) {
+			t.Errorf("window = %s to %s, want the baseline window", restricted.From, restricted.To)
+		}
+	})
+
+	t.Run("counts pairs that stopped ranging as silent", func(t *testing.T) {
+		observed := summariseHealth(3, []models.RolloutPairHealth{
+			{SourceID: 1, DestinationID: 3, Samples: 40},
+			{SourceID: 3, DestinationID: 4, Samples: 500},
+		}, from.Add(time.Minute), from.Add(time.Minute+20*time.Second))
+
+		restricted := restrictHealth(baseline, []uint{1, 2, 3}, observed)
+
+		if len(restricted.Pairs) != 3 {
+			t.Fatalf("pairs = %+v, want the three baseline pairs", restricted.Pairs)
+		}
+		if restricted.Samples != 40 {
+			t.Errorf("samples = %d, want only the observed samples of baseline pairs", restricted.Samples)
+		}
+		if math.Abs(restricted.SampleRate-40.0/20/3) > 1e-9 {
+			t.Errorf("sample rate = %f Hz, want %f Hz", restricted.SampleRate, 40.0/20/3)
+		}
+		if !restricted.From.Equal(observed.From) || !restricted.To.Equal(observed.To) {
+			t.Errorf("window = %s to %s, want the observed window", restricted.From, restricted.To)
+		}
+	})
+}
+
+func TestEvaluateHealth(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	baseline := rolloutHealth(from,
+		models.RolloutPairHealth{SourceID: 1, DestinationID: 2, Samples: 100, Rejected: 5},
+		models.RolloutPairHealth{SourceID: 2, DestinationID: 1, Samples: 100, Rejected: 5},
+		models.RolloutPairHealth{SourceID: 2, DestinationID: 3, Samples: 100},
+	)
+	rollout := &models.Rollout{MaxRateDrop: 0.3, MaxErrorIncrease: 0.1}
+
+	tests := []struct {
+		name     string
+		baseline *models.RolloutHealth
+		pairs    []models.RolloutPairHealth
+		reasons  int
+	}{
+		{
+			name:     "unchanged",
+			baseline: baseline,
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 100, Rejected: 5},
+				{SourceID: 2, DestinationID: 1, Samples: 100, Rejected: 5},
+			},
+		},
+		{
+			name:     "rate drop within the limit",
+			baseline: baseline,
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 80, Rejected: 4},
+				{SourceID: 2, DestinationID: 1, Samples: 80, Rejected: 4},
+			},
+		},
+		{
+			name:     "pair stopped ranging",
+			baseline: baseline,
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 100, Rejected: 5},
+			},
+			reasons: 1,
+		},
+		{
+			name:     "error rate rose",
+			baseline: baseline,
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 100, Rejected: 30},
+				{SourceID: 2, DestinationID: 1, Samples: 100, Rejected: 30},
+			},
+			reasons: 1,
+		},
+		{
+			name:     "rate dropped and error rate rose",
+			baseline: baseline,
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 10, Rejected: 5},
+			},
+			reasons: 2,
+		},
+		{
+			name: "pairs new since the baseline do not count towards the rate",
+			baseline: rolloutHealth(from,
+				models.RolloutPairHealth{SourceID: 1, DestinationID: 2, Samples: 100},
+			),
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 100},
+				{SourceID: 2, DestinationID: 1, Samples: 1},
+			},
+		},
+		{
+			name:     "no baseline",
+			baseline: nil,
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 100},
+			},
+			reasons: 1,
+		},
+		{
+			name: "no baseline pair between the stations",
+			baseline: rolloutHealth(from,
+				models.RolloutPairHealth{SourceID: 2, DestinationID: 3, Samples: 100},
+			),
+			pairs: []models.RolloutPairHealth{
+				{SourceID: 1, DestinationID: 2, Samples: 100},
+			},
+			reasons: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			health := summariseHealth(2, test.pairs, from.Add(time.Minute), from.Add(time.Minute+10*time.Second))
+			evaluateHealth(health, test.baseline, []uint{1, 2}, rollout)
+
+			if len(health.Reasons) != test.reasons {
+				t.Fatalf("reasons = %v, want %d", health.Reasons, test.reasons)
+			}
+			if health.Degraded != (test.reasons > 0) {
+				t.Errorf("degraded = %t with reasons %v", health.Degraded, health.Reasons)
+			}
+		})
+	}
+}
+
+func TestWaveEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		stations int
+		percents []uint
+		expected []int
+	}{
+		{name: "even split", stations: 10, percents: []uint{10, 50, 100}, expected: []int{2, 5, 10}},
+		{name: "first wave of one station grows to two", stations: 20, percents: []uint{5, 100}, expected: []int{2, 20}},
+		{name: "every wave adds a station while any are left", stations: 3, percents: []uint{1, 2, 3, 100}, expected: []int{2, 3, 3, 3}},
+		{name: "rounds up", stations: 7, percents: []uint{50, 100}, expected: []int{4, 7}},
+		{name: "two stations", stations: 2, percents: []uint{10, 100}, expected: []int{2, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assigned := 0
+			for i, percent := range test.percents {
+				assigned = waveEnd(test.stations, assigned, percent)
+				if assigned != test.expected[i] {
+					t.Errorf("wave %d ends at %d stations, want %d", i+1, assigned, test.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"gps-no-server/internal/core/repositories"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidPinnedAnchors = errors.New("pinned stations must be one to three positioned anchors of the cluster")
+	ErrNoSelfLocalization   = errors.New("no self-localization proposal for cluster")
+	ErrStationNotInProposal = errors.New("station is not part of the self-localization proposal")
+)
+
+type SelfLocalizationService struct {
+	stationRepository *repositories.StationRepository
+	clusterRepository *repositories.ClusterRepository
+	rangingRepository *repositories.RangingRepository
+	config            *config.PositioningConfig
+	proposals         map[uint]*models.SelfLocalization
+	proposalLock      sync.RWMutex
+	log               zerolog.Logger
+}
+
+func NewSelfLocalizationService(
+	stationRepository *repositories.StationRepository,
+	clusterRepository *repositories.ClusterRepository,
+	rangingRepository *repositories.RangingRepository,
+	cfg *config.PositioningConfig,
+) *SelfLocalizationService {
+	return &SelfLocalizationService{
+		stationRepository: stationRepository,
+		clusterRepository: clusterRepository,
+		rangingRepository: rangingRepository,
+		config:            cfg,
+		proposals:         make(map[uint]*models.SelfLocalization),
+		log:               logger.GetLogger("self-localization-service"),
+	}
+}
+
+// Localize proposes coordinates for every anchor of the cluster using the
+// median anchor-to-anchor distances of the configured window. The proposal
+// is kept until it is accepted or replaced.
+func (s *SelfLocalizationService) Localize(ctx context.Context, clusterId uint, pinnedIds []uint, dimension int, window time.Duration) (*models.SelfLocalization, error) {
+	if len(pinnedIds) == 0 || len(pinnedIds) > 3 {
+		return nil, ErrInvalidPinnedAnchors
+	}
+
+	if window <= 0 {
+		window = s.config.SelfLocalizationWindow
+	}
+
+	cluster, err := s.clusterRepository.FindById(ctx, clusterId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rangings are in metres while station coordinates use the cluster units.
+	scale := unitScale(cluster)
+
+	stations, err := s.stationRepository.FindByCluster(ctx, clusterId, map[string]bool{"config": true})
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := make(map[uint]*models.Station)
+	for _, station := range stations {
+		if station.StationConfig != nil && station.StationConfig.UWBMode == models.AnchorMode {
+			anchors[station.ID] = station
+		}
+	}
+
+	to := time.Now()
+	from := to.Add(-window)
+	stats, err := s.rangingRepository.FindPairStats(ctx, repositories.RangingStatsFilter{
+		ClusterID: &clusterId,
+		From:      from,
+		To:        to,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both directions of a pair are averaged into one symmetric distance.
+	type pairKey struct{ a, b uint }
+	sums := make(map[pairKey]float64)
+	counts := make(map[pairKey]int)
+	connected := make(map[uint]bool)
+	for _, stat := range stats {
+		if anchors[stat.SourceID] == nil || anchors[stat.DestinationID] == nil || stat.SourceID == stat.DestinationID {
+			continue
+		}
+
+		key := pairKey{a: min(stat.SourceID, stat.DestinationID), b: max(stat.SourceID, stat.DestinationID)}
+		sums[key] += stat.Median
+		counts[key]++
+		connected[stat.SourceID] = true
+		connected[stat.DestinationID] = true
+	}
+
+	result := &models.SelfLocalization{
+		ClusterID: clusterId,
+		Warnings:  make([]string, 0),
+		From:      from,
+		To:        to,
+		Timestamp: to,
+	}
+
+	ids := make([]uint, 0, len(anchors))
+	for id := range anchors {
+		if !connected[id] {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("anchor %d has no anchor rangings and was skipped", id))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	sort.Strings(result.Warnings)
+
+	index := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	pinned := make(map[int]positioning.Vector, len(pinnedIds))
+	for _, id := range pinnedIds {
+		i, exists := index[id]
+		if !exists || !anchors[id].Position.IsSet() {
+			return nil, ErrInvalidPinnedAnchors
+		}
+
+		position := positioning.Vector{X: *anchors[id].Position.X * scale, Y: *anchors[id].Position.Y * scale}
+		if anchors[id].Position.Z != nil {
+			position.Z = *anchors[id].Position.Z * scale
+		}
+		pinned[i] = position
+	}
+
+	distances := make([][]float64, len(ids))
+	for i := range distances {
+		distances[i] = make([]float64, len(ids))
+		for j := range distances[i] {
+			distances[i][j] = math.NaN()
+		}
+	}
+	for key, sum := range sums {
+		i, j := index[key.a], index[key.b]
+		distances[i][j] = sum / float64(counts[key])
+		distances[j][i] = distances[i][j]
+	}
+
+	if dimension != 3 {
+		dimension = 2
+	}
+
+	solution, err := positioning.Localize(distances, pinned, positioning.LocalizationOptions{Dimension: dimension})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Dimension = dimension
+	result.Residual = solution.Residual / scale
+	result.Mirrored = solution.Mirrored
+	result.Ambiguous = solution.Ambiguous
+	result.Anchors = make([]*models.SelfLocalizedAnchor, 0, len(ids))
+
+	for i, id := range ids {
+		_, isPinned := pinned[i]
+		result.Anchors = append(result.Anchors, &models.SelfLocalizedAnchor{
+			StationID:  id,
+			X:          solution.Positions[i].X / scale,
+			Y:          solution.Positions[i].Y / scale,
+			Z:          solution.Positions[i].Z / scale,
+			Error:      solution.Errors[i] / scale,
+			Pinned:     isPinned,
+			Neighbours: solution.Neighbours[i],
+		})
+
+		if solution.Neighbours[i] < dimension+1 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("anchor %d has only %d neighbours, its position is weakly constrained", id, solution.Neighbours[i]))
+		}
+	}
+
+	if solution.Ambiguous {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d pinned anchors do not fully determine the orientation of a %dD layout", len(pinned), dimension))
+	}
+
+	s.proposalLock.Lock()
+	s.proposals[clusterId] = result
+	s.proposalLock.Unlock()
+
+	s.log.Info().
+		Uint("cluster_id", clusterId).
+		Int("anchors", len(result.Anchors)).
+		Float64("residual", result.Residual).
+		Msg("Computed anchor self-localization")
+
+	return result, nil
+}
+
+func (s *SelfLocalizationService) GetLatest(clusterId uint) (*models.SelfLocalization, bool) {
+	s.proposalLock.RLock()
+	defer s.proposalLock.RUnlock()
+
+	result, exists := s.proposals[clusterId]
+	return result, exists
+}
+
+// Accept writes the latest proposal into the station positions as estimated
+// coordinates. Pinned anchors already carry their surveyed position and are
+// left untouched. An empty station list accepts every anchor of the proposal.
+func (s *SelfLocalizationService) Accept(ctx context.Context, clusterId uint, stationIds []uint) (*models.SelfLocalization, error) {
+	result, exists := s.GetLatest(clusterId)
+	if !exists {
+		return nil, ErrNoSelfLocalization
+	}
+
+	selected := make(map[uint]bool, len(stationIds))
+	for _, id := range stationIds {
+		selected[id] = true
+	}
+
+	proposed := make(map[uint]bool, len(result.Anchors))
+	for _, anchor := range result.Anchors {
+		proposed[anchor.StationID] = true
+	}
+
+	for id := range selected {
+		if !proposed[id] {
+			return nil, fmt.Errorf("station %d: %w", id, ErrStationNotInProposal)
+		}
+	}
+
+	positions := make(map[uint]models.Position)
+	for _, anchor := range result.Anchors {
+		if anchor.Pinned || (len(selected) > 0 && !selected[anchor.StationID]) {
+			continue
+		}
+
+		x, y, z := anchor.X, anchor.Y, anchor.Z
+		position := models.Position{X: &x, Y: &y, Frame: models.LocalFrame, Source: models.EstimatedPosition}
+		if result.Dimension == 3 {
+			position.Z = &z
+		}
+
+		positions[anchor.StationID] = position
+	}
+
+	if err := s.stationRepository.UpdatePositions(ctx, positions); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().
+		Uint("cluster_id", clusterId).
+		Int("anchors", len(positions)).
+		Msg("Accepted anchor self-localization")
+
+	return result, nil
+}
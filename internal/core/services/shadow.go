@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"sync"
+	"time"
+)
+
+// ShadowService compares the desired configuration of each station with the
+// one its device reported and re-sends the desired one to drifted devices,
+// e.g. after a reboot into factory defaults.
+type ShadowService struct {
+	stationConfigRepository *repositories.StationConfigurationRepository
+	configSyncService       *ConfigSyncService
+	config                  *config.ShadowConfig
+	stop                    chan struct{}
+	stopOnce                sync.Once
+	log                     zerolog.Logger
+}
+
+func NewShadowService(
+	stationConfigRepository *repositories.StationConfigurationRepository,
+	configSyncService *ConfigSyncService,
+	cfg *config.ShadowConfig,
+) *ShadowService {
+	return &ShadowService{
+		stationConfigRepository: stationConfigRepository,
+		configSyncService:       configSyncService,
+		config:                  cfg,
+		stop:                    make(chan struct{}),
+		log:                     logger.GetLogger("shadow-service"),
+	}
+}
+
+func (s *ShadowService) GetByStationId(ctx context.Context, stationId uint) (*models.StationShadow, error) {
+	stationConfig, err := s.stationConfigRepository.FindByStationId(ctx, stationId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildShadow(stationConfig, time.Now()), nil
+}
+
+func (s *ShadowService) GetAll(ctx context.Context, driftedOnly bool) ([]*models.StationShadow, error) {
+	var stationConfigs []*models.StationConfiguration
+	var err error
+	if driftedOnly {
+		stationConfigs, err = s.stationConfigRepository.FindReported(ctx)
+	} else {
+		stationConfigs, err = s.stationConfigRepository.FindAll(ctx, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shadows := make([]*models.StationShadow, 0, len(stationConfigs))
+	for _, stationConfig := range stationConfigs {
+		shadow := buildShadow(stationConfig, now)
+		if driftedOnly && !shadow.Drifted {
+			continue
+		}
+		shadows = append(shadows, shadow)
+	}
+
+	return shadows, nil
+}
+
+// Reconcile pushes the desired configuration to every drifted station that
+// is not already waiting for an answer and was not pushed to recently.
+// Stations whose device rejected the configuration are left alone, pushing
+// it again would only be rejected again. Stations that did not apply it
+// after the configured number of attempts are given up on as well, until
+// the configuration is pushed explicitly.
+func (s *ShadowService) Reconcile(ctx context.Context) ([]*models.StationShadow, error) {
+	stationConfigs, err := s.stationConfigRepository.FindReported(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resent := make([]*models.StationShadow, 0)
+	for _, stationConfig := range stationConfigs {
+		shadow := buildShadow(stationConfig, now)
+		if !shadow.Drifted || stationConfig.Sync.Status == models.SyncPending || isRejected(stationConfig.Sync) {
+			continue
+		}
+
+		if s.config.MaxAttempts > 0 && stationConfig.Sync.Status != models.SyncApplied && stationConfig.Sync.Attempts >= uint(s.config.MaxAttempts) {
+			s.log.Debug().
+				Uint("station_id", stationConfig.StationID).
+				Uint("attempts", stationConfig.Sync.Attempts).
+				Msg("Giving up on re-sending station configuration")
+			continue
+		}
+
+		if publishedAt := stationConfig.Sync.PublishedAt; publishedAt != nil && now.Sub(*publishedAt) < s.config.ResendAfter {
+			continue
+		}
+
+		s.log.Info().
+			Uint("station_id", stationConfig.StationID).
+			Int("differences", len(shadow.Delta)).
+			Msg("Station configuration drifted, re-sending desired configuration")
+
+		if _, err := s.configSyncService.Resend(ctx, stationConfig); err != nil {
+			s.log.Error().Err(err).Uint("station_id", stationConfig.StationID).Msg("Failed to re-send station configuration")
+			continue
+		}
+
+		shadow.Sync = stationConfig.Sync
+		resent = append(resent, shadow)
+	}
+
+	return resent, nil
+}
+
+func (s *ShadowService) Start() {
+	interval := s.config.ReconcileInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if _, err := s.Reconcile(ctx); err != nil {
+					s.log.Error().Err(err).Msg("Failed to reconcile station shadows")
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+func (s *ShadowService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+func buildShadow(stationConfig *models.StationConfiguration, timestamp time.Time) *models.StationShadow {
+	shadow := &models.StationShadow{
+		StationID:       stationConfig.StationID,
+		ConfigurationID: stationConfig.ID,
+		Desired:         stationConfig.DesiredState(),
+		ReportedAt:      stationConfig.Reported.Timestamp,
+		Delta:           make([]models.ShadowDifference, 0),
+		Sync:            stationConfig.Sync,
+		Timestamp:       timestamp,
+	}
+
+	if reported, exists := stationConfig.ReportedState(); exists {
+		shadow.Reported = &reported
+		shadow.Delta = shadow.Desired.Diff(reported)
+		shadow.Stale = isStaleReport(stationConfig)
+		shadow.Drifted = len(shadow.Delta) > 0 && !shadow.Stale
+	}
+
+	return shadow
+}
+
+// isRejected reports whether the device answered the current revision with
+// a failure. Failed publishes carry no acknowledgement and can be retried.
+func isRejected(sync models.ConfigSync) bool {
+	return sync.Status == models.SyncFailed && sync.AcknowledgedAt != nil
+}
+
+// isStaleReport reports whether the device acknowledged the current revision
+// after its last report. Acknowledging does not re-report the configuration,
+// so the stored report still describes the state before the push.
+func isStaleReport(stationConfig *models.StationConfiguration) bool {
+	sync := stationConfig.Sync
+	if sync.Status != models.SyncApplied || sync.AcknowledgedAt == nil {
+		return false
+	}
+
+	reportedAt := stationConfig.Reported.Timestamp
+	return reportedAt == nil || reportedAt.Before(*sync.AcknowledgedAt)
+}
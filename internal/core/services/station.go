@@ -36,6 +36,11 @@ func (s *StationService) GetByMac(ctx context.Context, mac string, includeParam
 	return s.stationRepository.FindByMac(ctx, mac, includes)
 }
 
+func (s *StationService) GetByMacs(ctx context.Context, macs []string, includeParam *string) ([]*models.Station, error) {
+	includes := dto.ParseIncludes(includeParam)
+	return s.stationRepository.FindByMacs(ctx, macs, includes)
+}
+
 func (s *StationService) GetByIdentifier(ctx context.Context, identifier string, includeParam *string) (*models.Station, error) {
 	includes := dto.ParseIncludes(includeParam)
 	return s.stationRepository.FindByIdentifier(ctx, identifier, includes)
@@ -63,3 +68,64 @@ func (s *StationService) UpdateOrCreate(ctx context.Context, station *models.Sta
 
 	return nil, fmt.Errorf("unexpected condition in UpdateOrCreate")
 }
+
+func (s *StationService) Create(ctx context.Context, station *models.Station, includeParam *string) (*models.Station, error) {
+	if err := validatePosition(station.Position); err != nil {
+		return nil, err
+	}
+
+	return s.BaseService.Create(ctx, station, includeParam)
+}
+
+func (s *StationService) UpdateFields(ctx context.Context, station *models.Station, fields []string, includeParam *string) (*models.Station, error) {
+	expandedFields := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		if field != "position" {
+			expandedFields = append(expandedFields, field)
+			continue
+		}
+
+		if err := validatePosition(station.Position); err != nil {
+			return nil, err
+		}
+
+		expandedFields = append(expandedFields, "position_x", "position_y", "position_z", "position_frame", "position_source")
+	}
+
+	return s.BaseService.UpdateFields(ctx, station, expandedFields, includeParam)
+}
+
+func (s *StationService) UpdatePosition(ctx context.Context, stationId uint, position models.Position) error {
+	if err := validatePosition(position); err != nil {
+		return err
+	}
+
+	return s.stationRepository.UpdatePosition(ctx, stationId, position)
+}
+
+func (s *StationService) UpdateReportedPosition(ctx context.Context, station *models.Station, position models.Position) error {
+	if station.Position.Source == models.SurveyedPosition {
+		s.log.Debug().Str("mac", station.MacAddress).Msg("Ignoring reported position for surveyed station")
+		return nil
+	}
+
+	position.Source = models.ReportedPosition
+	if position.Frame == "" {
+		position.Frame = models.LocalFrame
+	}
+
+	return s.UpdatePosition(ctx, station.ID, position)
+}
+
+func validatePosition(position models.Position) error {
+	if position.Source != "" && !position.Source.IsValid() {
+		return fmt.Errorf("invalid position source: %s", position.Source)
+	}
+
+	if (position.X == nil) != (position.Y == nil) {
+		return fmt.Errorf("position requires both x and y coordinates")
+	}
+
+	return nil
+}
@@ -3,19 +3,29 @@ package services
 import (
 	"context"
 	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/actor"
 	"gps-no-server/internal/common/logger"
 	"gps-no-server/internal/core/models"
 	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/core/validation"
 	"gps-no-server/internal/infrastructure/http/dto"
+	"strings"
 )
 
 type StationConfigurationService struct {
 	*BaseService[models.StationConfiguration]
 	stationConfigurationRepository *repositories.StationConfigurationRepository
+	revisionRepository             *repositories.ConfigurationRevisionRepository
+	configSyncService              *ConfigSyncService
+	stationConfigValidator         *validation.StationConfigValidator
 	log                            zerolog.Logger
 }
 
-func NewStationConfigService(stationConfigRepository *repositories.StationConfigurationRepository) *StationConfigurationService {
+func NewStationConfigService(
+	stationConfigRepository *repositories.StationConfigurationRepository,
+	revisionRepository *repositories.ConfigurationRevisionRepository,
+	configSyncService *ConfigSyncService,
+) *StationConfigurationService {
 	baseService := NewBaseService[models.StationConfiguration](
 		stationConfigRepository,
 		"station-configuration",
@@ -24,6 +34,9 @@ func NewStationConfigService(stationConfigRepository *repositories.StationConfig
 	return &StationConfigurationService{
 		BaseService:                    baseService,
 		stationConfigurationRepository: stationConfigRepository,
+		revisionRepository:             revisionRepository,
+		configSyncService:              configSyncService,
+		stationConfigValidator:         validation.NewStationConfigValidator(),
 		log:                            logger.GetLogger("station-configuration-service"),
 	}
 }
@@ -32,3 +45,194 @@ func (s *StationConfigurationService) GetByStationId(ctx context.Context, statio
 	includes := dto.ParseIncludes(includeParam)
 	return s.stationConfigurationRepository.FindByStationId(ctx, stationId, includes)
 }
+
+func (s *StationConfigurationService) Create(ctx context.Context, stationConfig *models.StationConfiguration, includeParam *string) (*models.StationConfiguration, error) {
+	stationConfig.ApplyDefaults()
+	if err := s.stationConfigValidator.Validate(stationConfig); err != nil {
+		return nil, err
+	}
+
+	created, err := s.stationConfigurationRepository.CreateWithRevision(ctx, stationConfig, s.revise(ctx, models.CreateRevision, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	s.push(ctx, created)
+	return created, nil
+}
+
+func (s *StationConfigurationService) Update(ctx context.Context, stationConfig *models.StationConfiguration, includeParam *string) (*models.StationConfiguration, error) {
+	if err := s.stationConfigValidator.Validate(stationConfig); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.stationConfigurationRepository.UpdateWithRevision(ctx, stationConfig, nil, s.revise(ctx, models.UpdateRevision, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	s.push(ctx, updated)
+	return updated, nil
+}
+
+// UpdateFields validates the stored configuration merged with the updated
+// fields, since a single parameter such as the channel can invalidate the
+// others. The configuration is pushed to the device only when one of the
+// UWB settings was part of the update.
+func (s *StationConfigurationService) UpdateFields(ctx context.Context, stationConfig *models.StationConfiguration, fields []string, includeParam *string) (*models.StationConfiguration, error) {
+	return s.updateFields(ctx, stationConfig, fields, includeParam, models.UpdateRevision, nil)
+}
+
+func (s *StationConfigurationService) GetRevisions(ctx context.Context, configurationId uint) ([]*models.ConfigurationRevision, error) {
+	if _, err := s.stationConfigurationRepository.FindById(ctx, configurationId, nil); err != nil {
+		return nil, err
+	}
+
+	return s.revisionRepository.FindByConfiguration(ctx, configurationId)
+}
+
+func (s *StationConfigurationService) GetRevision(ctx context.Context, configurationId uint, version uint) (*models.ConfigurationRevision, error) {
+	return s.revisionRepository.FindByVersion(ctx, configurationId, version)
+}
+
+// Rollback restores the configuration recorded in a revision. It goes
+// through the regular update path, so the restored state is validated,
+// recorded as a new revision and pushed to the device.
+func (s *StationConfigurationService) Rollback(ctx context.Context, configurationId uint, version uint, includeParam *string) (*models.StationConfiguration, error) {
+	revision, err := s.revisionRepository.FindByVersion(ctx, configurationId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	stationConfig := &models.StationConfiguration{}
+	stationConfig.ID = configurationId
+	stationConfig.StationID = revision.StationID
+	stationConfig.Restore(revision.Snapshot)
+
+	return s.updateFields(ctx, stationConfig, models.SnapshotFields, includeParam, models.RollbackRevision, &revision.Version)
+}
+
+func (s *StationConfigurationService) updateFields(
+	ctx context.Context,
+	stationConfig *models.StationConfiguration,
+	fields []string,
+	includeParam *string,
+	source models.RevisionSource,
+	restoredVersion *uint,
+) (*models.StationConfiguration, error) {
+	existing, err := s.stationConfigurationRepository.FindById(ctx, stationConfig.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	radioChanged := false
+	for _, field := range fields {
+		if strings.HasPrefix(field, "uwb_") {
+			radioChanged = true
+			break
+		}
+	}
+
+	if radioChanged {
+		if err := s.stationConfigValidator.Validate(mergeRadioFields(existing, stationConfig, fields)); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.stationConfigurationRepository.UpdateWithRevision(ctx, stationConfig, fields, s.revise(ctx, source, restoredVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	if radioChanged {
+		s.push(ctx, updated)
+	}
+
+	return updated, nil
+}
+
+// revise describes the configuration state after a change, which the
+// repository stores together with the change. The repository passes the
+// state it replaced, read in the same transaction, or nil for a new
+// configuration. Updates that leave every tracked field untouched are not
+// recorded.
+func (s *StationConfigurationService) revise(
+	ctx context.Context,
+	source models.RevisionSource,
+	restoredVersion *uint,
+) func(before *models.StationConfiguration, after *models.StationConfiguration) *models.ConfigurationRevision {
+	return func(before *models.StationConfiguration, after *models.StationConfiguration) *models.ConfigurationRevision {
+		revision := newRevision(ctx, before, after, source)
+		if revision != nil {
+			revision.RestoredVersion = restoredVersion
+		}
+		return revision
+	}
+}
+
+func (s *StationConfigurationService) push(ctx context.Context, stationConfig *models.StationConfiguration) {
+	if s.configSyncService == nil {
+		return
+	}
+
+	if _, err := s.configSyncService.Push(ctx, stationConfig); err != nil {
+		s.log.Error().Err(err).Uint("station_id", stationConfig.StationID).Msg("Failed to push station configuration")
+	}
+}
+
+func mergeRadioFields(existing *models.StationConfiguration, update *models.StationConfiguration, fields []string) *models.StationConfiguration {
+	merged := *existing
+
+	for _, field := range fields {
+		switch field {
+		case "uwb_mode":
+			merged.UWBMode = update.UWBMode
+		case "uwb_channel":
+			merged.UWBChannel = update.UWBChannel
+		case "uwb_preamble_code":
+			merged.UWBPreambleCode = update.UWBPreambleCode
+		case "uwb_preamble_len":
+			merged.UWBPreambleLen = update.UWBPreambleLen
+		case "uwb_chip":
+			merged.UWBChip = update.UWBChip
+		case "uwb_data_rate":
+			merged.UWBDataRate = update.UWBDataRate
+		case "uwb_prf":
+			merged.UWBPRF = update.UWBPRF
+		case "uwb_sfd":
+			merged.UWBSFD = update.UWBSFD
+		case "uwb_tx_power":
+			merged.UWBTxPower = update.UWBTxPower
+		case "uwb_antenna_delay":
+			merged.UWBAntennaDelay = update.UWBAntennaDelay
+		}
+	}
+
+	return &merged
+}
+
+// newRevision describes the change from before to after, attributed to the
+// actor of the request. It returns nil when nothing tracked changed. A nil
+// before marks a newly created configuration.
+func newRevision(ctx context.Context, before *models.StationConfiguration, after *models.StationConfiguration, source models.RevisionSource) *models.ConfigurationRevision {
+	revision := &models.ConfigurationRevision{
+		StationConfigurationID: after.ID,
+		StationID:              after.StationID,
+		Actor:                  actor.FromContext(ctx),
+		Source:                 source,
+		Snapshot:               after.Snapshot(),
+		Changes:                make([]models.ConfigChange, 0),
+	}
+
+	if before != nil {
+		previous := before.Snapshot()
+		revision.Previous = &previous
+		revision.Changes = previous.Diff(revision.Snapshot)
+
+		if len(revision.Changes) == 0 {
+			return nil
+		}
+	}
+
+	return revision
+}
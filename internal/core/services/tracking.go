@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/core/tracking"
+	"sync"
+)
+
+type TrackingService struct {
+	clusterRepository *repositories.ClusterRepository
+	config            *config.TrackingConfig
+	trackers          map[uint]*tracking.Tracker
+	positions         map[uint]*models.TrackedPosition
+	trackerLock       sync.RWMutex
+	log               zerolog.Logger
+}
+
+func NewTrackingService(clusterRepository *repositories.ClusterRepository, cfg *config.TrackingConfig) *TrackingService {
+	return &TrackingService{
+		clusterRepository: clusterRepository,
+		config:            cfg,
+		trackers:          make(map[uint]*tracking.Tracker),
+		positions:         make(map[uint]*models.TrackedPosition),
+		log:               logger.GetLogger("tracking-service"),
+	}
+}
+
+func (s *TrackingService) GetLatest(stationId uint) (*models.TrackedPosition, bool) {
+	s.trackerLock.RLock()
+	defer s.trackerLock.RUnlock()
+
+	position, exists := s.positions[stationId]
+	return position, exists
+}
+
+func (s *TrackingService) Reset(stationId uint) {
+	s.trackerLock.Lock()
+	defer s.trackerLock.Unlock()
+
+	delete(s.trackers, stationId)
+	delete(s.positions, stationId)
+}
+
+func (s *TrackingService) Track(ctx context.Context, fix *models.PositionFix) (*models.TrackedPosition, error) {
+	cluster := s.clusterFor(ctx, fix.ClusterID)
+	parameters := s.parametersFor(cluster)
+	if err := parameters.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.trackerLock.Lock()
+	defer s.trackerLock.Unlock()
+
+	tracker, exists := s.trackers[fix.StationID]
+	if !exists {
+		tracker = tracking.NewTracker(parameters)
+		s.trackers[fix.StationID] = tracker
+	} else {
+		tracker.SetParameters(parameters)
+	}
+
+	measurement := positioning.Vector{X: fix.X, Y: fix.Y, Z: fix.Z}
+	state := tracker.Update(measurement, fix.Residual, fix.Timestamp)
+
+	position := &models.TrackedPosition{
+		StationID:  fix.StationID,
+		ClusterID:  fix.ClusterID,
+		X:          state.Position.X,
+		Y:          state.Position.Y,
+		Z:          state.Position.Z,
+		VX:         state.Velocity.X,
+		VY:         state.Velocity.Y,
+		VZ:         state.Velocity.Z,
+		Covariance: state.Covariance,
+		Model:      string(parameters.Model),
+		Updates:    state.Updates,
+		Fix:        fix,
+		Level:      fix.Level,
+		Geo:        georeference(cluster, state.Position.X, state.Position.Y, state.Position.Z),
+		Timestamp:  state.Timestamp,
+	}
+	s.positions[fix.StationID] = position
+
+	return position, nil
+}
+
+func (s *TrackingService) clusterFor(ctx context.Context, clusterId *uint) *models.Cluster {
+	if clusterId == nil {
+		return nil
+	}
+
+	cluster, err := s.clusterRepository.FindById(ctx, *clusterId, nil)
+	if err != nil {
+		s.log.Warn().Err(err).Uint("cluster_id", *clusterId).Msg("Falling back to default tracking parameters")
+		return nil
+	}
+
+	return cluster
+}
+
+func (s *TrackingService) parametersFor(cluster *models.Cluster) tracking.Parameters {
+	parameters := tracking.Parameters{
+		Model:            tracking.MotionModel(s.config.Model),
+		ProcessNoise:     s.config.ProcessNoise,
+		MeasurementNoise: s.config.MeasurementNoise,
+		ResetTimeout:     s.config.ResetTimeout,
+	}
+
+	if cluster == nil {
+		return parameters
+	}
+
+	if cluster.Tracking.Model != "" {
+		parameters.Model = tracking.MotionModel(cluster.Tracking.Model)
+	}
+
+	if cluster.Tracking.ProcessNoise != nil {
+		parameters.ProcessNoise = *cluster.Tracking.ProcessNoise
+	}
+
+	if cluster.Tracking.MeasurementNoise != nil {
+		parameters.MeasurementNoise = *cluster.Tracking.MeasurementNoise
+	}
+
+	return parameters
+}
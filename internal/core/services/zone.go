@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/core/validation"
+	"gps-no-server/internal/infrastructure/http/dto"
+)
+
+type ZoneService struct {
+	*BaseService[models.Zone]
+	zoneRepository *repositories.ZoneRepository
+	zoneValidator  *validation.ZoneValidator
+	log            zerolog.Logger
+}
+
+func NewZoneService(zoneRepository *repositories.ZoneRepository) *ZoneService {
+	baseService := NewBaseService[models.Zone](
+		zoneRepository,
+		"zone",
+	)
+
+	return &ZoneService{
+		BaseService:    baseService,
+		zoneRepository: zoneRepository,
+		zoneValidator:  validation.NewZoneValidator(),
+		log:            logger.GetLogger("zone-service"),
+	}
+}
+
+func (z *ZoneService) GetByCluster(ctx context.Context, clusterId uint, includeParam *string) ([]*models.Zone, error) {
+	includes := dto.ParseIncludes(includeParam)
+
+	return z.zoneRepository.FindByCluster(ctx, clusterId, includes)
+}
+
+func (z *ZoneService) Create(ctx context.Context, zone *models.Zone, includeParam *string) (*models.Zone, error) {
+	if zone.Type == "" {
+		zone.Type = models.GenericZone
+	}
+
+	if err := z.zoneValidator.ValidateCreate(zone); err != nil {
+		return nil, err
+	}
+
+	return z.BaseService.Create(ctx, zone, includeParam)
+}
+
+func (z *ZoneService) UpdateFields(ctx context.Context, zone *models.Zone, fields []string, includeParam *string) (*models.Zone, error) {
+	if err := z.zoneValidator.ValidateFields(zone, fields); err != nil {
+		return nil, err
+	}
+
+	return z.BaseService.UpdateFields(ctx, zone, fields, includeParam)
+}
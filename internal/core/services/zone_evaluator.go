@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/repositories"
+	"gps-no-server/internal/events"
+	"sync"
+	"time"
+)
+
+const ZoneEventType = "zone"
+
+type zoneOccupancy struct {
+	clusterId     uint
+	zone          *models.Zone
+	enteredAt     time.Time
+	dwellNotified bool
+	// Last position of the tag inside the zone, in the cluster's units.
+	x, y, z float64
+}
+
+func (o *zoneOccupancy) event(eventType events.ZoneEventType, stationId uint, timestamp time.Time) *events.ZoneEvent {
+	return &events.ZoneEvent{
+		Type:         eventType,
+		ClusterId:    o.clusterId,
+		ZoneId:       o.zone.ID,
+		ZoneName:     o.zone.Name,
+		ZoneType:     string(o.zone.Type),
+		StationId:    stationId,
+		X:            o.x,
+		Y:            o.y,
+		Z:            o.z,
+		EnteredAt:    o.enteredAt,
+		DwellSeconds: timestamp.Sub(o.enteredAt).Seconds(),
+		Timestamp:    timestamp,
+	}
+}
+
+// ZoneEvaluator keeps track of which zones every tag is currently in and
+// publishes enter, exit and dwell-exceeded transitions on the zone event bus.
+type ZoneEvaluator struct {
+	zoneRepository *repositories.ZoneRepository
+	eventBus       *events.ZoneEventBus
+	occupancy      map[uint]map[uint]*zoneOccupancy
+	occupancyLock  sync.Mutex
+	log            zerolog.Logger
+}
+
+func NewZoneEvaluator(zoneRepository *repositories.ZoneRepository, eventBus *events.ZoneEventBus) *ZoneEvaluator {
+	return &ZoneEvaluator{
+		zoneRepository: zoneRepository,
+		eventBus:       eventBus,
+		occupancy:      make(map[uint]map[uint]*zoneOccupancy),
+		log:            logger.GetLogger("zone-evaluator"),
+	}
+}
+
+// Evaluate updates the zones a tag is in from its latest position. The
+// vertical limits of a zone are ignored for 2D positions, whose height is
+// not measured. Zones the tag occupied that no longer belong to its
+// cluster are exited.
+func (e *ZoneEvaluator) Evaluate(ctx context.Context, stationId uint, clusterId uint, x, y, z float64, dimension int, timestamp time.Time) ([]*events.ZoneEvent, error) {
+	zones, err := e.zoneRepository.FindByCluster(ctx, clusterId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	e.occupancyLock.Lock()
+	defer e.occupancyLock.Unlock()
+
+	current, exists := e.occupancy[stationId]
+	if !exists {
+		current = make(map[uint]*zoneOccupancy)
+		e.occupancy[stationId] = current
+	}
+
+	emitted := make([]*events.ZoneEvent, 0)
+	seen := make(map[uint]bool, len(zones))
+
+	for _, zone := range zones {
+		seen[zone.ID] = true
+		occupancy, inside := current[zone.ID]
+
+		contains := zone.Contains(x, y, z)
+		if dimension == 2 {
+			contains = zone.ContainsHorizontal(x, y)
+		}
+
+		if !contains {
+			if inside {
+				occupancy.x, occupancy.y, occupancy.z = x, y, z
+				emitted = append(emitted, occupancy.event(events.ZoneExited, stationId, timestamp))
+				delete(current, zone.ID)
+			}
+			continue
+		}
+
+		if !inside {
+			occupancy = &zoneOccupancy{clusterId: clusterId, enteredAt: timestamp}
+			current[zone.ID] = occupancy
+		}
+		occupancy.zone = zone
+		occupancy.x, occupancy.y, occupancy.z = x, y, z
+
+		if !inside {
+			emitted = append(emitted, occupancy.event(events.ZoneEntered, stationId, timestamp))
+		}
+
+		limit := zone.DwellLimit
+		if limit != nil && !occupancy.dwellNotified && timestamp.Sub(occupancy.enteredAt) > time.Duration(*limit)*time.Second {
+			occupancy.dwellNotified = true
+			emitted = append(emitted, occupancy.event(events.ZoneDwellExceeded, stationId, timestamp))
+		}
+	}
+
+	// The tag moved to another cluster, or the zone was deleted or moved.
+	for zoneId, occupancy := range current {
+		if !seen[zoneId] {
+			emitted = append(emitted, occupancy.event(events.ZoneExited, stationId, timestamp))
+			delete(current, zoneId)
+		}
+	}
+
+	e.publish(emitted)
+	return emitted, nil
+}
+
+// Exit leaves every zone the tag is in, for example when it lost its fix.
+// The exit events carry the last position seen inside each zone.
+func (e *ZoneEvaluator) Exit(stationId uint, timestamp time.Time) []*events.ZoneEvent {
+	e.occupancyLock.Lock()
+	defer e.occupancyLock.Unlock()
+
+	emitted := make([]*events.ZoneEvent, 0, len(e.occupancy[stationId]))
+	for _, occupancy := range e.occupancy[stationId] {
+		emitted = append(emitted, occupancy.event(events.ZoneExited, stationId, timestamp))
+	}
+	delete(e.occupancy, stationId)
+
+	e.publish(emitted)
+	return emitted
+}
+
+func (e *ZoneEvaluator) publish(emitted []*events.ZoneEvent) {
+	for _, event := range emitted {
+		e.log.Info().
+			Str("type", string(event.Type)).
+			Uint("zone_id", event.ZoneId).
+			Uint("station_id", event.StationId).
+			Msg("Zone transition")
+
+		e.eventBus.Publish(event)
+	}
+}
@@ -0,0 +1,187 @@
+package tracking
+
+import (
+	"fmt"
+	"gps-no-server/internal/common/linalg"
+	"gps-no-server/internal/core/positioning"
+	"math"
+	"time"
+)
+
+type MotionModel string
+
+const (
+	ConstantVelocity MotionModel = "constant_velocity"
+	ConstantPosition MotionModel = "constant_position"
+)
+
+const stateSize = 6
+
+func (m MotionModel) IsValid() bool {
+	return m == ConstantVelocity || m == ConstantPosition
+}
+
+type Parameters struct {
+	Model MotionModel
+	// ProcessNoise is the white acceleration spectral density (m²/s³) for the
+	// constant-velocity model and the position random-walk density (m²/s) otherwise.
+	ProcessNoise float64
+	// MeasurementNoise is the standard deviation of a single fix in metres.
+	MeasurementNoise float64
+	ResetTimeout     time.Duration
+}
+
+func (p Parameters) Validate() error {
+	if !p.Model.IsValid() {
+		return fmt.Errorf("unknown motion model: %s", p.Model)
+	}
+
+	if p.ProcessNoise <= 0 || p.MeasurementNoise <= 0 {
+		return fmt.Errorf("process and measurement noise must be positive")
+	}
+
+	return nil
+}
+
+type State struct {
+	Position   positioning.Vector
+	Velocity   positioning.Vector
+	Covariance [][]float64
+	Timestamp  time.Time
+	Updates    int
+}
+
+// Tracker is a Kalman filter over the state [x y z vx vy vz].
+type Tracker struct {
+	parameters Parameters
+	state      []float64
+	covariance *linalg.Matrix
+	timestamp  time.Time
+	updates    int
+}
+
+func NewTracker(parameters Parameters) *Tracker {
+	return &Tracker{
+		parameters: parameters,
+	}
+}
+
+func (t *Tracker) SetParameters(parameters Parameters) {
+	if t.parameters.Model != parameters.Model {
+		t.Reset()
+	}
+
+	t.parameters = parameters
+}
+
+func (t *Tracker) Reset() {
+	t.state = nil
+	t.covariance = nil
+	t.updates = 0
+}
+
+// Update feeds a new position measurement into the filter. measurementNoise
+// overrides the configured noise when it is larger, which lets poor fixes
+// carry less weight. Duplicate or out-of-order measurements are ignored and
+// the current state is returned unchanged.
+func (t *Tracker) Update(measurement positioning.Vector, measurementNoise float64, timestamp time.Time) State {
+	sigma := math.Max(t.parameters.MeasurementNoise, measurementNoise)
+	variance := sigma * sigma
+
+	dt := timestamp.Sub(t.timestamp).Seconds()
+	if t.state == nil || (t.parameters.ResetTimeout > 0 && dt > t.parameters.ResetTimeout.Seconds()) {
+		t.initialize(measurement, variance, timestamp)
+		return t.State()
+	}
+
+	if dt <= 0 {
+		return t.State()
+	}
+
+	t.predict(dt)
+	t.correct(measurement, variance)
+	t.timestamp = timestamp
+	t.updates++
+
+	return t.State()
+}
+
+func (t *Tracker) State() State {
+	covariance := make([][]float64, stateSize)
+	for i := 0; i < stateSize; i++ {
+		covariance[i] = t.covariance.Row(i)
+	}
+
+	return State{
+		Position:   positioning.Vector{X: t.state[0], Y: t.state[1], Z: t.state[2]},
+		Velocity:   positioning.Vector{X: t.state[3], Y: t.state[4], Z: t.state[5]},
+		Covariance: covariance,
+		Timestamp:  t.timestamp,
+		Updates:    t.updates,
+	}
+}
+
+func (t *Tracker) initialize(measurement positioning.Vector, variance float64, timestamp time.Time) {
+	t.state = []float64{measurement.X, measurement.Y, measurement.Z, 0, 0, 0}
+
+	velocityVariance := 0.0
+	if t.parameters.Model == ConstantVelocity {
+		velocityVariance = 1.0
+	}
+	t.covariance = linalg.Diagonal(variance, variance, variance, velocityVariance, velocityVariance, velocityVariance)
+	t.timestamp = timestamp
+	t.updates = 1
+}
+
+func (t *Tracker) predict(dt float64) {
+	transition := linalg.Identity(stateSize)
+	process := linalg.NewMatrix(stateSize, stateSize)
+	q := t.parameters.ProcessNoise
+
+	switch t.parameters.Model {
+	case ConstantVelocity:
+		for axis := 0; axis < 3; axis++ {
+			transition.Set(axis, axis+3, dt)
+
+			process.Set(axis, axis, q*dt*dt*dt/3)
+			process.Set(axis, axis+3, q*dt*dt/2)
+			process.Set(axis+3, axis, q*dt*dt/2)
+			process.Set(axis+3, axis+3, q*dt)
+		}
+	case ConstantPosition:
+		for axis := 0; axis < 3; axis++ {
+			process.Set(axis, axis, q*dt)
+		}
+		t.state[3], t.state[4], t.state[5] = 0, 0, 0
+	}
+
+	t.state = transition.MulVec(t.state)
+	t.covariance = transition.Mul(t.covariance).Mul(transition.T()).Add(process)
+}
+
+func (t *Tracker) correct(measurement positioning.Vector, variance float64) {
+	observation := linalg.NewMatrix(3, stateSize)
+	for axis := 0; axis < 3; axis++ {
+		observation.Set(axis, axis, 1)
+	}
+
+	innovation := []float64{
+		measurement.X - t.state[0],
+		measurement.Y - t.state[1],
+		measurement.Z - t.state[2],
+	}
+
+	innovationCovariance := observation.Mul(t.covariance).Mul(observation.T()).Add(linalg.Diagonal(variance, variance, variance))
+	inverse, err := innovationCovariance.Inverse()
+	if err != nil {
+		return
+	}
+
+	gain := t.covariance.Mul(observation.T()).Mul(inverse)
+	correction := gain.MulVec(innovation)
+	for i := range t.state {
+		t.state[i] += correction[i]
+	}
+
+	t.covariance = linalg.Identity(stateSize).Sub(gain.Mul(observation)).Mul(t.covariance)
+}
@@ -0,0 +1,104 @@
+package tracking
+
+import (
+	"gps-no-server/internal/core/positioning"
+	"math"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func constantVelocity() Parameters {
+	return Parameters{
+		Model:            ConstantVelocity,
+		ProcessNoise:     0.1,
+		MeasurementNoise: 0.1,
+		ResetTimeout:     10 * time.Second,
+	}
+}
+
+func TestTrackerInitialize(t *testing.T) {
+	tracker := NewTracker(constantVelocity())
+
+	state := tracker.Update(positioning.Vector{X: 1, Y: 2, Z: 3}, 0, start)
+	if state.Position != (positioning.Vector{X: 1, Y: 2, Z: 3}) {
+		t.Errorf("position = %+v, want the first measurement", state.Position)
+	}
+	if state.Velocity != (positioning.Vector{}) {
+		t.Errorf("velocity = %+v, want zero", state.Velocity)
+	}
+	if state.Updates != 1 {
+		t.Errorf("updates = %d, want 1", state.Updates)
+	}
+}
+
+func TestTrackerConstantVelocity(t *testing.T) {
+	tracker := NewTracker(constantVelocity())
+
+	var state State
+	for i := 0; i <= 50; i++ {
+		elapsed := float64(i) * 0.1
+		state = tracker.Update(positioning.Vector{X: 2 * elapsed, Y: -elapsed}, 0, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	if math.Abs(state.Velocity.X-2) > 0.05 || math.Abs(state.Velocity.Y+1) > 0.05 {
+		t.Errorf("velocity = %+v, want about (2, -1, 0)", state.Velocity)
+	}
+	if math.Abs(state.Position.X-10) > 0.05 || math.Abs(state.Position.Y+5) > 0.05 {
+		t.Errorf("position = %+v, want about (10, -5, 0)", state.Position)
+	}
+}
+
+func TestTrackerIgnoresStaleMeasurements(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+	}{
+		{name: "duplicate", timestamp: start.Add(time.Second)},
+		{name: "out of order", timestamp: start.Add(500 * time.Millisecond)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracker := NewTracker(constantVelocity())
+			tracker.Update(positioning.Vector{X: 0}, 0, start)
+			before := tracker.Update(positioning.Vector{X: 1}, 0, start.Add(time.Second))
+
+			after := tracker.Update(positioning.Vector{X: 50}, 0, test.timestamp)
+			if after.Position != before.Position || after.Velocity != before.Velocity {
+				t.Errorf("state = %+v, want unchanged %+v", after, before)
+			}
+			if after.Updates != before.Updates || !after.Timestamp.Equal(before.Timestamp) {
+				t.Errorf("updates = %d at %s, want %d at %s", after.Updates, after.Timestamp, before.Updates, before.Timestamp)
+			}
+		})
+	}
+}
+
+func TestTrackerResetTimeout(t *testing.T) {
+	tracker := NewTracker(constantVelocity())
+	tracker.Update(positioning.Vector{X: 0}, 0, start)
+	tracker.Update(positioning.Vector{X: 1}, 0, start.Add(time.Second))
+
+	state := tracker.Update(positioning.Vector{X: 100}, 0, start.Add(time.Minute))
+	if state.Position != (positioning.Vector{X: 100}) || state.Updates != 1 {
+		t.Errorf("state = %+v, want a reset to the new measurement", state)
+	}
+}
+
+func TestTrackerMeasurementNoise(t *testing.T) {
+	precise := NewTracker(constantVelocity())
+	noisy := NewTracker(constantVelocity())
+
+	for _, tracker := range []*Tracker{precise, noisy} {
+		tracker.Update(positioning.Vector{X: 0}, 0, start)
+	}
+
+	preciseState := precise.Update(positioning.Vector{X: 1}, 0, start.Add(time.Second))
+	noisyState := noisy.Update(positioning.Vector{X: 1}, 5, start.Add(time.Second))
+
+	if noisyState.Position.X >= preciseState.Position.X {
+		t.Errorf("noisy fix moved the estimate to %f, want less than %f", noisyState.Position.X, preciseState.Position.X)
+	}
+}
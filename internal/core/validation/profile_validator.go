@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"gps-no-server/internal/core/models"
+)
+
+type ProfileValidator struct {
+	stationConfigValidator *StationConfigValidator
+}
+
+func NewProfileValidator() *ProfileValidator {
+	return &ProfileValidator{
+		stationConfigValidator: NewStationConfigValidator(),
+	}
+}
+
+// Validate accepts a profile when its radio parameters are valid for at
+// least one supported chip. Chip specific checks happen when the profile is
+// applied to a station.
+func (p *ProfileValidator) Validate(profile *models.ConfigurationProfile) error {
+	var errors ValidationErrors
+
+	if profile.Name == "" {
+		errors = append(errors, ValidationError{Field: "name", Message: "Name cannot be empty"})
+	}
+
+	var best ValidationErrors
+	for _, chip := range []models.UWBChip{models.DW1000Chip, models.DW3000Chip} {
+		config := &models.StationConfiguration{UWBChip: chip}
+		config.ApplyDefaults()
+		config.ApplyProfile(profile)
+
+		err := p.stationConfigValidator.Validate(config)
+		if err == nil {
+			best = nil
+			break
+		}
+
+		chipErrors := err.(ValidationErrors)
+		if best == nil || len(chipErrors) < len(best) {
+			best = chipErrors
+		}
+	}
+
+	errors = append(errors, best...)
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"fmt"
+	"gps-no-server/internal/core/models"
+	"math"
+	"strings"
+)
+
+type StationConfigValidator struct{}
+
+func NewStationConfigValidator() *StationConfigValidator {
+	return &StationConfigValidator{}
+}
+
+// Validate checks the complete radio parameter set against the capabilities
+// of the configured chip, including the channel/preamble code combination.
+func (v *StationConfigValidator) Validate(config *models.StationConfiguration) error {
+	var errors ValidationErrors
+
+	if !config.UWBMode.IsValid() {
+		errors = append(errors, ValidationError{Field: "uwb_mode", Message: "Mode must be ANCHOR, TAG or NONE"})
+	}
+
+	capabilities, exists := GetPhyCapabilities(config.UWBChip)
+	if !exists {
+		errors = append(errors, ValidationError{Field: "uwb_chip", Message: "Chip must be DW1000 or DW3000"})
+		return errors
+	}
+
+	channelValid := contains(capabilities.Channels, config.UWBChannel)
+	if !channelValid {
+		errors = append(errors, ValidationError{
+			Field:   "uwb_channel",
+			Message: fmt.Sprintf("Channel %d is not supported by %s, expected one of %s", config.UWBChannel, config.UWBChip, join(capabilities.Channels)),
+		})
+	}
+
+	prfValid := contains(capabilities.PRFs, config.UWBPRF)
+	if !prfValid {
+		errors = append(errors, ValidationError{
+			Field:   "uwb_prf",
+			Message: fmt.Sprintf("PRF must be one of %s MHz", join(capabilities.PRFs)),
+		})
+	}
+
+	if channelValid && prfValid {
+		codes := PreambleCodes(config.UWBChannel, config.UWBPRF)
+		if !contains(codes, config.UWBPreambleCode) {
+			errors = append(errors, ValidationError{
+				Field: "uwb_preamble_code",
+				Message: fmt.Sprintf("Preamble code %d is not allowed on channel %d at %d MHz PRF, expected one of %s",
+					config.UWBPreambleCode, config.UWBChannel, config.UWBPRF, join(codes)),
+			})
+		}
+	}
+
+	if !contains(capabilities.PreambleLengths, config.UWBPreambleLen) {
+		errors = append(errors, ValidationError{
+			Field:   "uwb_preamble_len",
+			Message: fmt.Sprintf("Preamble length %q is not supported by %s, expected one of %s", config.UWBPreambleLen, config.UWBChip, join(capabilities.PreambleLengths)),
+		})
+	}
+
+	if !contains(capabilities.DataRates, config.UWBDataRate) {
+		errors = append(errors, ValidationError{
+			Field:   "uwb_data_rate",
+			Message: fmt.Sprintf("Data rate %d kbps is not supported by %s, expected one of %s", config.UWBDataRate, config.UWBChip, join(capabilities.DataRates)),
+		})
+	}
+
+	if !contains(capabilities.SFDs, config.UWBSFD) {
+		errors = append(errors, ValidationError{
+			Field:   "uwb_sfd",
+			Message: fmt.Sprintf("SFD %q is not supported by %s, expected one of %s", config.UWBSFD, config.UWBChip, join(capabilities.SFDs)),
+		})
+	}
+
+	if config.UWBTxPower != nil {
+		power := *config.UWBTxPower
+		if power < 0 || power > capabilities.MaxTxPower {
+			errors = append(errors, ValidationError{
+				Field:   "uwb_tx_power",
+				Message: fmt.Sprintf("TX power must be between 0 and %.1f dB", capabilities.MaxTxPower),
+			})
+		} else if step := capabilities.TxPowerStep; step > 0 && math.Abs(power/step-math.Round(power/step)) > 1e-9 {
+			errors = append(errors, ValidationError{
+				Field:   "uwb_tx_power",
+				Message: fmt.Sprintf("TX power must be a multiple of %.1f dB on %s", step, config.UWBChip),
+			})
+		}
+	}
+
+	if config.UWBAntennaDelay == 0 {
+		errors = append(errors, ValidationError{Field: "uwb_antenna_delay", Message: "Antenna delay must be a positive number of device time units"})
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func join[T any](values []T) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"errors"
+	"gps-no-server/internal/core/models"
+	"testing"
+)
+
+func validStationConfig(chip models.UWBChip) *models.StationConfiguration {
+	return &models.StationConfiguration{
+		UWBMode:         models.AnchorMode,
+		UWBChip:         chip,
+		UWBChannel:      5,
+		UWBPreambleCode: 9,
+		UWBPreambleLen:  "128",
+		UWBDataRate:     6800,
+		UWBPRF:          64,
+		UWBSFD:          models.StandardSFD,
+		UWBAntennaDelay: models.DefaultUWBAntennaDelay,
+	}
+}
+
+func TestStationConfigValidator(t *testing.T) {
+	power := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name   string
+		chip   models.UWBChip
+		modify func(config *models.StationConfiguration)
+		fields []string
+	}{
+		{name: "valid DW1000", chip: models.DW1000Chip},
+		{name: "valid DW3000", chip: models.DW3000Chip},
+		{
+			name: "DW3000 on channel 9 with 4z SFD",
+			chip: models.DW3000Chip,
+			modify: func(config *models.StationConfiguration) {
+				config.UWBChannel = 9
+				config.UWBSFD = models.IEEE4zSFD
+				config.UWBPreambleLen = "72"
+			},
+		},
+		{
+			name: "DW1000 at 16 MHz PRF on channel 2",
+			chip: models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) {
+				config.UWBChannel = 2
+				config.UWBPRF = 16
+				config.UWBPreambleCode = 4
+				config.UWBDataRate = 110
+				config.UWBPreambleLen = "1024"
+			},
+		},
+		{
+			name:   "unknown mode",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBMode = "ROUTER" },
+			fields: []string{"uwb_mode"},
+		},
+		{
+			name:   "DW1000 does not support channel 9",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBChannel = 9 },
+			fields: []string{"uwb_channel"},
+		},
+		{
+			name:   "DW3000 does not support channel 2",
+			chip:   models.DW3000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBChannel = 2 },
+			fields: []string{"uwb_channel"},
+		},
+		{
+			name:   "preamble code of the other PRF",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBPreambleCode = 3 },
+			fields: []string{"uwb_preamble_code"},
+		},
+		{
+			name:   "preamble code of another channel",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBPreambleCode = 17 },
+			fields: []string{"uwb_preamble_code"},
+		},
+		{
+			name:   "unsupported PRF skips the preamble code check",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBPRF = 32 },
+			fields: []string{"uwb_prf"},
+		},
+		{
+			name: "DW1000 specific limits on a DW3000",
+			chip: models.DW3000Chip,
+			modify: func(config *models.StationConfiguration) {
+				config.UWBDataRate = 110
+				config.UWBPreambleLen = "32"
+			},
+			fields: []string{"uwb_data_rate"},
+		},
+		{
+			name:   "DW3000 preamble length on a DW1000",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBPreambleLen = "72" },
+			fields: []string{"uwb_preamble_len"},
+		},
+		{
+			name:   "DW3000 SFD on a DW1000",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBSFD = models.IEEE4zSFD },
+			fields: []string{"uwb_sfd"},
+		},
+		{
+			name:   "TX power above the maximum",
+			chip:   models.DW3000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBTxPower = power(34) },
+			fields: []string{"uwb_tx_power"},
+		},
+		{
+			name:   "TX power off the DW1000 step",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBTxPower = power(10.3) },
+			fields: []string{"uwb_tx_power"},
+		},
+		{
+			name:   "TX power on the DW1000 step",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBTxPower = power(10.5) },
+		},
+		{
+			name:   "unquantised TX power on a DW3000",
+			chip:   models.DW3000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBTxPower = power(10.3) },
+		},
+		{
+			name:   "missing antenna delay",
+			chip:   models.DW1000Chip,
+			modify: func(config *models.StationConfiguration) { config.UWBAntennaDelay = 0 },
+			fields: []string{"uwb_antenna_delay"},
+		},
+		{
+			name:   "unknown chip stops further checks",
+			chip:   "DW2000",
+			modify: func(config *models.StationConfiguration) { config.UWBChannel = 42 },
+			fields: []string{"uwb_chip"},
+		},
+	}
+
+	validator := NewStationConfigValidator()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := validStationConfig(test.chip)
+			if test.modify != nil {
+				test.modify(config)
+			}
+
+			err := validator.Validate(config)
+			if len(test.fields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErrors ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("error = %v, want validation errors", err)
+			}
+
+			if len(validationErrors) != len(test.fields) {
+				t.Fatalf("errors = %v, want fields %v", validationErrors, test.fields)
+			}
+			for i, field := range test.fields {
+				if validationErrors[i].Field != field {
+					t.Errorf("error %d field = %s, want %s", i, validationErrors[i].Field, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"gps-no-server/internal/core/models"
+)
+
+// PhyCapabilities lists the radio parameters a transceiver supports.
+type PhyCapabilities struct {
+	Channels        []uint8
+	DataRates       []uint
+	PRFs            []uint8
+	PreambleLengths []string
+	SFDs            []models.UWBSFD
+	// MaxTxPower is the highest transmit gain in dB, TxPowerStep the
+	// granularity of the gain setting or zero when it is not quantised.
+	MaxTxPower  float64
+	TxPowerStep float64
+}
+
+var phyCapabilities = map[models.UWBChip]PhyCapabilities{
+	models.DW1000Chip: {
+		Channels:        []uint8{1, 2, 3, 4, 5, 7},
+		DataRates:       []uint{110, 850, 6800},
+		PRFs:            []uint8{16, 64},
+		PreambleLengths: []string{"64", "128", "256", "512", "1024", "1536", "2048", "4096"},
+		SFDs:            []models.UWBSFD{models.StandardSFD, models.DecawaveSFD},
+		MaxTxPower:      33.5,
+		TxPowerStep:     0.5,
+	},
+	models.DW3000Chip: {
+		Channels:        []uint8{5, 9},
+		DataRates:       []uint{850, 6800},
+		PRFs:            []uint8{16, 64},
+		PreambleLengths: []string{"32", "64", "72", "128", "256", "512", "1024", "1536", "2048", "4096"},
+		SFDs:            []models.UWBSFD{models.StandardSFD, models.DecawaveSFD, models.Decawave16SFD, models.IEEE4zSFD},
+		MaxTxPower:      33.5,
+	},
+}
+
+// preambleCodes follows the IEEE 802.15.4 channel/preamble code assignment
+// used by both transceivers.
+var preambleCodes = map[uint8]map[uint8][]uint8{
+	16: {
+		1: {1, 2},
+		2: {3, 4},
+		3: {5, 6},
+		4: {7, 8},
+		5: {3, 4},
+		7: {7, 8},
+		9: {3, 4},
+	},
+	64: {
+		1: {9, 10, 11, 12},
+		2: {9, 10, 11, 12},
+		3: {9, 10, 11, 12},
+		4: {17, 18, 19, 20},
+		5: {9, 10, 11, 12},
+		7: {17, 18, 19, 20},
+		9: {9, 10, 11, 12},
+	},
+}
+
+func GetPhyCapabilities(chip models.UWBChip) (PhyCapabilities, bool) {
+	capabilities, exists := phyCapabilities[chip]
+	return capabilities, exists
+}
+
+// PreambleCodes returns the preamble codes allowed on a channel at the given
+// pulse repetition frequency.
+func PreambleCodes(channel uint8, prf uint8) []uint8 {
+	return preambleCodes[prf][channel]
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"gps-no-server/internal/core/models"
+	"slices"
+	"testing"
+)
+
+func TestPreambleCodes(t *testing.T) {
+	tests := []struct {
+		channel  uint8
+		prf      uint8
+		expected []uint8
+	}{
+		{channel: 1, prf: 16, expected: []uint8{1, 2}},
+		{channel: 5, prf: 16, expected: []uint8{3, 4}},
+		{channel: 5, prf: 64, expected: []uint8{9, 10, 11, 12}},
+		{channel: 4, prf: 64, expected: []uint8{17, 18, 19, 20}},
+		{channel: 9, prf: 64, expected: []uint8{9, 10, 11, 12}},
+		{channel: 6, prf: 64, expected: nil},
+		{channel: 5, prf: 32, expected: nil},
+	}
+
+	for _, test := range tests {
+		if codes := PreambleCodes(test.channel, test.prf); !slices.Equal(codes, test.expected) {
+			t.Errorf("channel %d at %d MHz: codes = %v, want %v", test.channel, test.prf, codes, test.expected)
+		}
+	}
+}
+
+// Every channel a chip supports must have preamble codes at every PRF it
+// supports, otherwise no configuration on that channel could validate.
+func TestPhyCapabilitiesHavePreambleCodes(t *testing.T) {
+	for _, chip := range []models.UWBChip{models.DW1000Chip, models.DW3000Chip} {
+		capabilities, exists := GetPhyCapabilities(chip)
+		if !exists {
+			t.Fatalf("%s has no capabilities", chip)
+		}
+
+		for _, channel := range capabilities.Channels {
+			for _, prf := range capabilities.PRFs {
+				if len(PreambleCodes(channel, prf)) == 0 {
+					t.Errorf("%s: no preamble codes on channel %d at %d MHz", chip, channel, prf)
+				}
+			}
+		}
+	}
+
+	if _, exists := GetPhyCapabilities("DW2000"); exists {
+		t.Error("unknown chip has capabilities")
+	}
+}
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"gps-no-server/internal/core/models"
+)
+
+type ZoneValidator struct{}
+
+func NewZoneValidator() *ZoneValidator {
+	return &ZoneValidator{}
+}
+
+func (z *ZoneValidator) ValidateCreate(zone *models.Zone) error {
+	return z.ValidateFields(zone, []string{"cluster_id", "name", "type", "polygon", "min_z", "max_z", "dwell_limit"})
+}
+
+// ValidateFields only checks the fields that are part of a partial update.
+func (z *ZoneValidator) ValidateFields(zone *models.Zone, fields []string) error {
+	var errors ValidationErrors
+
+	for _, field := range fields {
+		switch field {
+		case "cluster_id":
+			if zone.ClusterID == nil {
+				errors = append(errors, ValidationError{Field: "cluster_id", Message: "Zone must belong to a cluster"})
+			}
+		case "name":
+			if zone.Name == "" {
+				errors = append(errors, ValidationError{Field: "name", Message: "Name cannot be empty"})
+			}
+		case "type":
+			if !zone.Type.IsValid() {
+				errors = append(errors, ValidationError{Field: "type", Message: "Type must be restricted, safe or generic"})
+			}
+		case "polygon":
+			if len(zone.Polygon) < 3 {
+				errors = append(errors, ValidationError{Field: "polygon", Message: "Polygon requires at least three points"})
+			}
+		case "max_z":
+			if zone.MinZ != nil && zone.MaxZ != nil && *zone.MaxZ < *zone.MinZ {
+				errors = append(errors, ValidationError{Field: "max_z", Message: "max_z must not be below min_z"})
+			}
+		case "dwell_limit":
+			if zone.DwellLimit != nil && *zone.DwellLimit <= 0 {
+				errors = append(errors, ValidationError{Field: "dwell_limit", Message: "Dwell limit must be a positive number of seconds"})
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
@@ -4,38 +4,72 @@ import (
 	"github.com/rs/zerolog/log"
 	"gps-no-server/internal/common/config"
 	"gps-no-server/internal/core/controllers"
+	"gps-no-server/internal/core/models/mappers"
 	"gps-no-server/internal/core/repositories"
 	"gps-no-server/internal/core/services"
 	"gps-no-server/internal/events"
 	"gps-no-server/internal/infrastructure/database"
+	"gps-no-server/internal/infrastructure/gpsd"
 	"gps-no-server/internal/infrastructure/mqtt"
 	"gps-no-server/internal/infrastructure/mqtt/handlers"
 	"gps-no-server/internal/infrastructure/mqtt/subscriptions"
+	"gps-no-server/internal/infrastructure/nmea"
 )
 
 type Container struct {
 	Config             *config.Config
 	Database           *database.GormDB
 	MqttClient         *mqtt.Client
+	NmeaServer         *nmea.Server
+	GpsdServer         *gpsd.Server
 	EventStreamService *services.EventStreamService
 
 	StationEventBus     *events.StationEventBus
+	ZoneEventBus        *events.ZoneEventBus
 	ClusterEventHandler *handlers.ClusterEventHandler
 
 	StationRepository       *repositories.StationRepository
 	StationConfigRepository *repositories.StationConfigurationRepository
 	ClusterRepository       *repositories.ClusterRepository
 	RangingRepository       *repositories.RangingRepository
+	ZoneRepository          *repositories.ZoneRepository
+	ProfileRepository       *repositories.ConfigurationProfileRepository
+	RevisionRepository      *repositories.ConfigurationRevisionRepository
+	RolloutRepository       *repositories.RolloutRepository
 
-	StationService       *services.StationService
-	StationConfigService *services.StationConfigurationService
-	ClusterService       *services.ClusterService
-	RangingService       *services.RangingService
+	StationService           *services.StationService
+	StationConfigService     *services.StationConfigurationService
+	ClusterService           *services.ClusterService
+	RangingService           *services.RangingService
+	RangingFilterService     *services.RangingFilterService
+	PositionService          *services.PositionService
+	TrackingService          *services.TrackingService
+	SelfLocalizationService  *services.SelfLocalizationService
+	ZoneService              *services.ZoneService
+	ZoneEvaluator            *services.ZoneEvaluator
+	CoverageService          *services.CoverageService
+	RangingGraphService      *services.RangingGraphService
+	ClusterFormationService  *services.ClusterFormationService
+	ClusterMembershipService *services.ClusterMembershipService
+	PresenceService          *services.PresenceService
+	ConfigSyncService        *services.ConfigSyncService
+	ShadowService            *services.ShadowService
+	ProfileService           *services.ConfigurationProfileService
+	RolloutService           *services.RolloutService
+	ChannelPlanService       *services.ChannelPlanService
 
 	StationController       *controllers.StationController
 	StationConfigController *controllers.StationConfigController
 	ClusterController       *controllers.ClusterController
 	RangingController       *controllers.RangingController
+	PositionController      *controllers.PositionController
+	ZoneController          *controllers.ZoneController
+	CoverageController      *controllers.CoverageController
+	RangingGraphController  *controllers.RangingGraphController
+	ShadowController        *controllers.ShadowController
+	ProfileController       *controllers.ConfigurationProfileController
+	RolloutController       *controllers.RolloutController
+	ChannelPlanController   *controllers.ChannelPlanController
 }
 
 func NewContainer(cfg *config.Config) (*Container, error) {
@@ -48,10 +82,15 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	}
 
 	container.initRepositories()
-	container.initServices()
-	container.initControllers()
 	container.initMqtt()
+	if err := container.initServices(); err != nil {
+		return nil, err
+	}
+	container.initControllers()
+	container.initSubscriptions()
 	container.initEvents()
+	container.initNmea()
+	container.initGpsd()
 
 	return container, nil
 }
@@ -71,47 +110,142 @@ func (c *Container) initRepositories() {
 	c.StationConfigRepository = repositories.NewStationConfigRepository(c.Database.DB)
 	c.ClusterRepository = repositories.NewClusterRepository(c.Database.DB)
 	c.RangingRepository = repositories.NewRangingRepository(c.Database.DB)
+	c.ZoneRepository = repositories.NewZoneRepository(c.Database.DB)
+	c.ProfileRepository = repositories.NewConfigurationProfileRepository(c.Database.DB)
+	c.RevisionRepository = repositories.NewConfigurationRevisionRepository(c.Database.DB)
+	c.RolloutRepository = repositories.NewRolloutRepository(c.Database.DB)
 }
 
-func (c *Container) initServices() {
+func (c *Container) initServices() error {
+	rangingFilterService, err := services.NewRangingFilterService(&c.Config.Ranging)
+	if err != nil {
+		return err
+	}
+	c.RangingFilterService = rangingFilterService
+
 	c.StationService = services.NewStationService(c.StationRepository)
-	c.StationConfigService = services.NewStationConfigService(c.StationConfigRepository)
 	c.ClusterService = services.NewClusterService(c.ClusterRepository)
-	c.RangingService = services.NewRangingService(c.RangingRepository, c.StationService, c.EventStreamService)
 	c.EventStreamService = services.NewEventStreamService()
+	c.ConfigSyncService = services.NewConfigSyncService(c.StationConfigRepository, c.StationRepository, c.MqttClient, c.EventStreamService, &c.Config.ConfigSync)
+	c.StationConfigService = services.NewStationConfigService(c.StationConfigRepository, c.RevisionRepository, c.ConfigSyncService)
+	c.ShadowService = services.NewShadowService(c.StationConfigRepository, c.ConfigSyncService, &c.Config.Shadow)
+	c.ProfileService = services.NewConfigurationProfileService(c.ProfileRepository, c.StationRepository, c.StationConfigRepository, c.ClusterRepository, c.ConfigSyncService)
+	c.RolloutService = services.NewRolloutService(c.RolloutRepository, c.ProfileRepository, c.ClusterRepository, c.StationRepository, c.StationConfigRepository, c.RevisionRepository, c.RangingRepository, c.ProfileService, c.StationConfigService, &c.Config.Rollout)
+	c.ChannelPlanService = services.NewChannelPlanService(c.StationRepository, c.ClusterRepository, c.RangingRepository, c.ProfileService, &c.Config.Planning)
+	c.RangingService = services.NewRangingService(c.RangingRepository, c.StationService, c.EventStreamService, c.RangingFilterService, &c.Config.Ranging)
+	c.TrackingService = services.NewTrackingService(c.ClusterRepository, &c.Config.Tracking)
+	c.ZoneEventBus = events.NewZoneEventBus()
+	c.ZoneService = services.NewZoneService(c.ZoneRepository)
+	c.ZoneEvaluator = services.NewZoneEvaluator(c.ZoneRepository, c.ZoneEventBus)
+	c.CoverageService = services.NewCoverageService(c.StationRepository, c.ClusterRepository, &c.Config.Positioning)
+	c.RangingGraphService = services.NewRangingGraphService(c.StationRepository, c.ClusterRepository, c.RangingRepository)
+	c.PositionService = services.NewPositionService(c.StationRepository, c.StationConfigRepository, c.ClusterRepository, c.RangingRepository, c.TrackingService, c.ZoneEvaluator, c.EventStreamService, &c.Config.Positioning)
+	c.SelfLocalizationService = services.NewSelfLocalizationService(c.StationRepository, c.ClusterRepository, c.RangingRepository, &c.Config.Positioning)
+	c.StationEventBus = events.NewStationEventBus()
+	c.ClusterFormationService = services.NewClusterFormationService(c.StationRepository, c.ClusterRepository, c.RangingRepository, c.StationEventBus, &c.Config.Ranging)
+	c.ClusterMembershipService = services.NewClusterMembershipService(c.StationRepository, c.ClusterRepository, c.StationEventBus)
+	c.PresenceService = services.NewPresenceService(c.StationRepository, c.StationEventBus, &c.Config.Presence)
+
+	return nil
 }
 
 func (c *Container) initControllers() {
-	c.StationController = controllers.NewStationController(c.StationService)
-	c.StationConfigController = controllers.NewStationConfigController(c.StationConfigService)
-	c.ClusterController = controllers.NewClusterController(c.ClusterService)
+	c.StationController = controllers.NewStationController(c.StationService, c.PresenceService, c.EventStreamService)
+	c.StationConfigController = controllers.NewStationConfigController(c.StationConfigService, c.ConfigSyncService, c.EventStreamService)
+	c.ClusterController = controllers.NewClusterController(c.ClusterService, c.SelfLocalizationService, c.ClusterFormationService)
 	c.RangingController = controllers.NewRangingController(c.RangingService, c.EventStreamService)
+	c.PositionController = controllers.NewPositionController(c.PositionService, c.TrackingService, c.EventStreamService)
+	c.ZoneController = controllers.NewZoneController(c.ZoneService, c.EventStreamService)
+	c.CoverageController = controllers.NewCoverageController(c.CoverageService)
+	c.RangingGraphController = controllers.NewRangingGraphController(c.RangingGraphService)
+	c.ShadowController = controllers.NewShadowController(c.ShadowService)
+	c.ProfileController = controllers.NewConfigurationProfileController(c.ProfileService)
+	c.RolloutController = controllers.NewRolloutController(c.RolloutService)
+	c.ChannelPlanController = controllers.NewChannelPlanController(c.ChannelPlanService)
 }
 
 func (c *Container) initEvents() {
-	c.StationEventBus = events.NewStationEventBus()
-
 	clusterEventHandler := handlers.NewClusterEventHandler(c.MqttClient)
 
 	c.StationEventBus.Subscribe(events.StationAddedToCluster, func(event events.StationEvent) {
 		clusterEventHandler.HandleEvent(&event)
 	})
+
+	c.StationEventBus.Subscribe(events.StationRemovedFromCluster, func(event events.StationEvent) {
+		clusterEventHandler.HandleEvent(&event)
+	})
+
+	c.StationEventBus.Subscribe(events.StationClusterMismatch, func(event events.StationEvent) {
+		clusterEventHandler.HandleEvent(&event)
+	})
+
+	presenceEventHandler := handlers.NewPresenceEventHandler(c.MqttClient)
+
+	for _, eventType := range []events.StationEventType{events.StationWentOnline, events.StationWentOffline} {
+		c.StationEventBus.Subscribe(eventType, func(event events.StationEvent) {
+			presenceEventHandler.HandleEvent(&event)
+
+			if err := c.EventStreamService.Publish(services.PresenceEventType, mappers.FromPresenceEvent(&event)); err != nil {
+				log.Error().Err(err).Msg("Failed to publish presence event to event stream")
+			}
+		})
+	}
+
+	zoneEventHandler := handlers.NewZoneEventHandler(c.MqttClient)
+
+	c.ZoneEventBus.Subscribe(func(event events.ZoneEvent) {
+		zoneEventHandler.HandleEvent(&event)
+
+		if err := c.EventStreamService.Publish(services.ZoneEventType, mappers.FromZoneEvent(&event)); err != nil {
+			log.Error().Err(err).Msg("Failed to publish zone event to event stream")
+		}
+	})
 }
 
 func (c *Container) initMqtt() {
 	mqttRegistry := mqtt.NewSubscriptionRegistry()
 
-	stationHandler := subscriptions.NewStationSubscription(c.StationService)
-	rangingHandler := subscriptions.NewRangingSubscription(c.RangingService, c.StationService)
-
-	mqttRegistry.Register(stationHandler)
-	mqttRegistry.Register(rangingHandler)
-
 	mqttClient, _ := mqtt.Create(&c.Config.Mqtt, mqttRegistry)
 	c.MqttClient = mqttClient
 }
 
+// initSubscriptions registers the topic handlers once the services they
+// forward to exist. The client subscribes to them on every connect, which
+// has to happen after this.
+func (c *Container) initSubscriptions() {
+	stationHandler := subscriptions.NewStationSubscription(c.StationService, c.ClusterMembershipService, c.PresenceService)
+	rangingHandler := subscriptions.NewRangingSubscription(c.RangingService, c.PositionService, c.PresenceService)
+	presenceHandler := subscriptions.NewPresenceSubscription(c.PresenceService)
+	configSyncHandler := subscriptions.NewConfigSyncSubscription(c.ConfigSyncService)
+
+	c.MqttClient.Registry.Register(stationHandler)
+	c.MqttClient.Registry.Register(rangingHandler)
+	c.MqttClient.Registry.Register(presenceHandler)
+	c.MqttClient.Registry.Register(configSyncHandler)
+}
+
+func (c *Container) initNmea() {
+	if !c.Config.Nmea.Enabled {
+		return
+	}
+
+	c.NmeaServer = nmea.NewServer(&c.Config.Nmea, c.PositionService)
+}
+
+func (c *Container) initGpsd() {
+	if !c.Config.Gpsd.Enabled {
+		return
+	}
+
+	c.GpsdServer = gpsd.NewServer(&c.Config.Gpsd, c.PositionService)
+}
+
 func (c *Container) Cleanup() {
+	c.PresenceService.Stop()
+	c.ConfigSyncService.Stop()
+	c.ShadowService.Stop()
+	c.RolloutService.Stop()
+
 	if err := c.Database.Close(); err != nil {
 		log.Error().Err(err).Msg("Failed to close database connection")
 	}
@@ -119,4 +253,16 @@ func (c *Container) Cleanup() {
 	if err := c.MqttClient.Disconnect(); err != nil {
 		log.Error().Err(err).Msg("Failed to disconnect MQTT client")
 	}
+
+	if c.NmeaServer != nil {
+		if err := c.NmeaServer.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close NMEA server")
+		}
+	}
+
+	if c.GpsdServer != nil {
+		if err := c.GpsdServer.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close gpsd server")
+		}
+	}
 }
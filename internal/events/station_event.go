@@ -11,13 +11,17 @@ const (
 	StationAddedToCluster     StationEventType = "station_added_to_cluster"
 	StationRemovedFromCluster StationEventType = "station_removed_from_cluster"
 	ClusterUpdated            StationEventType = "cluster_updated"
+	StationClusterMismatch    StationEventType = "station_cluster_mismatch"
+	StationWentOnline         StationEventType = "station_online"
+	StationWentOffline        StationEventType = "station_offline"
 )
 
 type StationEvent struct {
-	Type      StationEventType `json:"type"`
-	ClusterId uint             `json:"cluster_id"`
-	StationId uint             `json:"station_id"`
-	Timestamp time.Time        `json:"timestamp"`
+	Type        StationEventType `json:"type"`
+	ClusterId   uint             `json:"cluster_id"`
+	StationId   uint             `json:"station_id"`
+	ClusterName string           `json:"cluster_name,omitempty"`
+	Timestamp   time.Time        `json:"timestamp"`
 }
 
 type StationEventBus struct {
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type ZoneEventType string
+
+const (
+	ZoneEntered       ZoneEventType = "zone_entered"
+	ZoneExited        ZoneEventType = "zone_exited"
+	ZoneDwellExceeded ZoneEventType = "zone_dwell_exceeded"
+)
+
+type ZoneEvent struct {
+	Type         ZoneEventType `json:"type"`
+	ClusterId    uint          `json:"cluster_id"`
+	ZoneId       uint          `json:"zone_id"`
+	ZoneName     string        `json:"zone_name"`
+	ZoneType     string        `json:"zone_type"`
+	StationId    uint          `json:"station_id"`
+	X            float64       `json:"x"`
+	Y            float64       `json:"y"`
+	Z            float64       `json:"z"`
+	EnteredAt    time.Time     `json:"entered_at"`
+	DwellSeconds float64       `json:"dwell_seconds"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+type ZoneEventBus struct {
+	handlers []func(ZoneEvent)
+	mu       sync.Mutex
+}
+
+func NewZoneEventBus() *ZoneEventBus {
+	return &ZoneEventBus{
+		handlers: []func(ZoneEvent){},
+	}
+}
+
+// Subscribe registers a handler for every zone event type.
+func (bus *ZoneEventBus) Subscribe(handler func(ZoneEvent)) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	bus.handlers = append(bus.handlers, handler)
+}
+
+func (bus *ZoneEventBus) Publish(event *ZoneEvent) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	eventX := *event
+
+	for _, handler := range bus.handlers {
+		go handler(eventX)
+	}
+}
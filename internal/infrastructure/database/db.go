@@ -47,7 +47,12 @@ func NewGormDB(cfg *config.DatabaseConfig) (*GormDB, error) {
 		&models.Station{},
 		&models.Cluster{},
 		&models.Ranging{},
+		&models.RangingSample{},
 		&models.StationConfiguration{},
+		&models.Zone{},
+		&models.ConfigurationProfile{},
+		&models.ConfigurationRevision{},
+		&models.Rollout{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -0,0 +1,169 @@
+package gpsd
+
+import (
+	"fmt"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/infrastructure/nmea"
+	"time"
+)
+
+const (
+	Release    = "3.25-gps-no"
+	ProtoMajor = 3
+	ProtoMinor = 15
+	Driver     = "gps-no-uwb"
+	timeFormat = "2006-01-02T15:04:05.000Z"
+
+	metresPerSecondPerKnot = 0.514444
+)
+
+type Version struct {
+	Class      string `json:"class"`
+	Release    string `json:"release"`
+	Rev        string `json:"rev"`
+	ProtoMajor int    `json:"proto_major"`
+	ProtoMinor int    `json:"proto_minor"`
+}
+
+type Watch struct {
+	Class  string `json:"class"`
+	Enable bool   `json:"enable"`
+	JSON   bool   `json:"json"`
+	NMEA   bool   `json:"nmea"`
+	Raw    int    `json:"raw"`
+	Scaled bool   `json:"scaled"`
+	Timing bool   `json:"timing"`
+	Split  bool   `json:"split24"`
+	PPS    bool   `json:"pps"`
+	Device string `json:"device,omitempty"`
+}
+
+type Device struct {
+	Class     string  `json:"class"`
+	Path      string  `json:"path"`
+	Driver    string  `json:"driver"`
+	Activated string  `json:"activated,omitempty"`
+	Flags     int     `json:"flags"`
+	Cycle     float64 `json:"cycle"`
+}
+
+type Devices struct {
+	Class   string    `json:"class"`
+	Devices []*Device `json:"devices"`
+}
+
+type TPV struct {
+	Class  string   `json:"class"`
+	Device string   `json:"device"`
+	Mode   int      `json:"mode"`
+	Time   string   `json:"time,omitempty"`
+	Lat    *float64 `json:"lat,omitempty"`
+	Lon    *float64 `json:"lon,omitempty"`
+	AltHAE *float64 `json:"altHAE,omitempty"`
+	Alt    *float64 `json:"alt,omitempty"`
+	Epx    *float64 `json:"epx,omitempty"`
+	Epy    *float64 `json:"epy,omitempty"`
+	Epv    *float64 `json:"epv,omitempty"`
+	Eph    *float64 `json:"eph,omitempty"`
+	Speed  *float64 `json:"speed,omitempty"`
+	Track  *float64 `json:"track,omitempty"`
+}
+
+type Satellite struct {
+	PRN  uint    `json:"PRN"`
+	Used bool    `json:"used"`
+	SS   float64 `json:"ss"`
+}
+
+type SKY struct {
+	Class      string       `json:"class"`
+	Device     string       `json:"device"`
+	Time       string       `json:"time,omitempty"`
+	HDOP       *float64     `json:"hdop,omitempty"`
+	VDOP       *float64     `json:"vdop,omitempty"`
+	PDOP       *float64     `json:"pdop,omitempty"`
+	NSat       int          `json:"nSat"`
+	USat       int          `json:"uSat"`
+	Satellites []*Satellite `json:"satellites"`
+}
+
+type Poll struct {
+	Class  string `json:"class"`
+	Time   string `json:"time"`
+	Active int    `json:"active"`
+	TPV    []*TPV `json:"tpv"`
+	SKY    []*SKY `json:"sky"`
+}
+
+type Error struct {
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+func DevicePath(stationId uint) string {
+	return fmt.Sprintf("uwb:%d", stationId)
+}
+
+func NewVersion() *Version {
+	return &Version{Class: "VERSION", Release: Release, Rev: Release, ProtoMajor: ProtoMajor, ProtoMinor: ProtoMinor}
+}
+
+func NewDevice(stationId uint, cycle time.Duration) *Device {
+	return &Device{Class: "DEVICE", Path: DevicePath(stationId), Driver: Driver, Flags: 1, Cycle: cycle.Seconds()}
+}
+
+// NewTPV builds a time-position-velocity report. Mode follows gpsd: 1 for no
+// fix, 2 for a 2D and 3 for a 3D fix. The error estimates are derived from the
+// solver residual scaled by the corresponding DOP; estimates whose DOP is not
+// known are left out.
+func NewTPV(stationId uint, fix *models.PositionFix, epoch nmea.Fix) *TPV {
+	report := &TPV{Class: "TPV", Device: DevicePath(stationId), Mode: 1, Time: epoch.Time.UTC().Format(timeFormat)}
+	if !epoch.Valid() {
+		return report
+	}
+
+	report.Mode = 2
+	if epoch.Dimension == 3 {
+		report.Mode = 3
+		report.AltHAE = floatPtr(epoch.Altitude)
+		report.Alt = floatPtr(epoch.Altitude)
+		report.Epv = floatPtr(fix.Residual * epoch.VDOP)
+	}
+
+	report.Lat = floatPtr(epoch.Latitude)
+	report.Lon = floatPtr(epoch.Longitude)
+	report.Eph = floatPtr(fix.Residual * epoch.HDOP)
+	if fix.EDOP > 0 && fix.NDOP > 0 {
+		report.Epx = floatPtr(fix.Residual * fix.EDOP)
+		report.Epy = floatPtr(fix.Residual * fix.NDOP)
+	}
+	report.Speed = floatPtr(epoch.SpeedKnots * metresPerSecondPerKnot)
+	if epoch.HasCourse {
+		report.Track = floatPtr(epoch.Course)
+	}
+
+	return report
+}
+
+// NewSKY reports the anchors used by the fix in place of satellites.
+func NewSKY(stationId uint, epoch nmea.Fix) *SKY {
+	report := &SKY{Class: "SKY", Device: DevicePath(stationId), Time: epoch.Time.UTC().Format(timeFormat), Satellites: make([]*Satellite, 0)}
+	if !epoch.Valid() {
+		return report
+	}
+
+	report.HDOP = floatPtr(epoch.HDOP)
+	report.VDOP = floatPtr(epoch.VDOP)
+	report.PDOP = floatPtr(epoch.PDOP)
+	report.NSat = len(epoch.Satellites)
+	report.USat = len(epoch.Satellites)
+	for _, anchorId := range epoch.Satellites {
+		report.Satellites = append(report.Satellites, &Satellite{PRN: anchorId, Used: true})
+	}
+
+	return report
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,89 @@
+package gpsd
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/infrastructure/nmea"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewTPVErrorEstimates(t *testing.T) {
+	epoch := nmea.Fix{
+		Time:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Latitude:  48.1,
+		Longitude: 11.5,
+		Altitude:  520,
+		Quality:   nmea.FixGPS,
+		HDOP:      1.5,
+		VDOP:      2,
+	}
+
+	tests := []struct {
+		name      string
+		fix       *models.PositionFix
+		dimension int
+		mode      int
+		epx       *float64
+		epy       *float64
+		eph       *float64
+		epv       *float64
+	}{
+		{
+			name:      "2D with east and north DOP",
+			fix:       &models.PositionFix{Residual: 0.2, EDOP: 0.5, NDOP: 1.4},
+			dimension: 2,
+			mode:      2,
+			epx:       floatPtr(0.1),
+			epy:       floatPtr(0.28),
+			eph:       floatPtr(0.3),
+		},
+		{
+			name:      "3D without east and north DOP",
+			fix:       &models.PositionFix{Residual: 0.2},
+			dimension: 3,
+			mode:      3,
+			eph:       floatPtr(0.3),
+			epv:       floatPtr(0.4),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			epoch.Dimension = test.dimension
+			report := NewTPV(1, test.fix, epoch)
+
+			if report.Mode != test.mode {
+				t.Errorf("mode = %d, want %d", report.Mode, test.mode)
+			}
+
+			for _, field := range []struct {
+				name     string
+				value    *float64
+				expected *float64
+			}{
+				{"epx", report.Epx, test.epx},
+				{"epy", report.Epy, test.epy},
+				{"eph", report.Eph, test.eph},
+				{"epv", report.Epv, test.epv},
+			} {
+				switch {
+				case field.expected == nil && field.value != nil:
+					t.Errorf("%s = %f, want it left out", field.name, *field.value)
+				case field.expected != nil && field.value == nil:
+					t.Errorf("%s is missing, want %f", field.name, *field.expected)
+				case field.expected != nil && math.Abs(*field.value-*field.expected) > 1e-9:
+					t.Errorf("%s = %f, want %f", field.name, *field.value, *field.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTPVWithoutFix(t *testing.T) {
+	report := NewTPV(1, nil, nmea.Fix{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)})
+
+	if report.Mode != 1 || report.Lat != nil || report.Eph != nil || report.Epx != nil {
+		t.Errorf("report = %+v, want mode 1 without position or errors", report)
+	}
+}
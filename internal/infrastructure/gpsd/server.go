@@ -0,0 +1,351 @@
+package gpsd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/infrastructure/nmea"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type PositionProvider interface {
+	GetLatest(stationId uint) (*models.PositionFix, bool)
+	GetAll() []*models.PositionFix
+}
+
+type client struct {
+	conn      net.Conn
+	watch     Watch
+	writeLock sync.Mutex
+	watchLock sync.RWMutex
+}
+
+func (c *client) send(report interface{}, deadline time.Time) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_ = c.conn.SetWriteDeadline(deadline)
+	_, err = c.conn.Write(append(payload, '\r', '\n'))
+	return err
+}
+
+// Server implements the subset of the gpsd JSON protocol needed by gpspipe
+// and libgps: VERSION on connect, ?WATCH, ?DEVICES, ?POLL and streamed TPV
+// and SKY reports. Every tag station is exposed as a device named "uwb:<id>".
+type Server struct {
+	config     *config.GpsdConfig
+	provider   PositionProvider
+	listener   net.Listener
+	clients    map[*client]bool
+	clientLock sync.Mutex
+	previous   map[uint]*models.PositionFix
+	latest     map[uint]*models.PositionFix
+	epochLock  sync.Mutex
+	done       chan struct{}
+	wg         sync.WaitGroup
+	log        zerolog.Logger
+}
+
+func NewServer(cfg *config.GpsdConfig, provider PositionProvider) *Server {
+	return &Server{
+		config:   cfg,
+		provider: provider,
+		clients:  make(map[*client]bool),
+		previous: make(map[uint]*models.PositionFix),
+		latest:   make(map[uint]*models.PositionFix),
+		done:     make(chan struct{}),
+		log:      logger.GetLogger("gpsd-server"),
+	}
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.config.Host, s.config.Port))
+	if err != nil {
+		return fmt.Errorf("failed to start gpsd listener: %w", err)
+	}
+	s.listener = listener
+
+	s.wg.Add(2)
+	go s.acceptLoop()
+	go s.broadcastLoop()
+
+	s.log.Info().Str("address", listener.Addr().String()).Msg("Started gpsd server")
+	return nil
+}
+
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+
+	close(s.done)
+	err := s.listener.Close()
+
+	s.clientLock.Lock()
+	for c := range s.clients {
+		_ = c.conn.Close()
+	}
+	s.clientLock.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return
+			default:
+				s.log.Error().Err(err).Msg("Failed to accept gpsd client")
+				continue
+			}
+		}
+
+		c := &client{conn: conn, watch: Watch{Class: "WATCH"}}
+
+		s.clientLock.Lock()
+		s.clients[c] = true
+		s.clientLock.Unlock()
+
+		s.log.Info().Str("remote", conn.RemoteAddr().String()).Msg("gpsd client connected")
+
+		if err := c.send(NewVersion(), time.Now().Add(s.interval())); err != nil {
+			s.removeClient(c)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.readLoop(c)
+	}
+}
+
+func (s *Server) readLoop(c *client) {
+	defer s.wg.Done()
+	defer s.removeClient(c)
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Split(splitCommands)
+
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+		if command == "" {
+			continue
+		}
+
+		if err := s.handleCommand(c, command); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handleCommand(c *client, command string) error {
+	name, argument, _ := strings.Cut(command, "=")
+	deadline := time.Now().Add(s.interval())
+
+	switch name {
+	case "?VERSION":
+		return c.send(NewVersion(), deadline)
+	case "?DEVICES":
+		return c.send(s.devices(), deadline)
+	case "?WATCH":
+		c.watchLock.Lock()
+		if argument != "" {
+			if err := json.Unmarshal([]byte(argument), &c.watch); err != nil {
+				c.watchLock.Unlock()
+				return c.send(&Error{Class: "ERROR", Message: "Invalid WATCH: " + err.Error()}, deadline)
+			}
+		} else {
+			c.watch.Enable = true
+			c.watch.JSON = true
+		}
+		c.watch.Class = "WATCH"
+		watch := c.watch
+		c.watchLock.Unlock()
+
+		if watch.Enable {
+			if err := c.send(s.devices(), deadline); err != nil {
+				return err
+			}
+		}
+		return c.send(&watch, deadline)
+	case "?POLL":
+		return c.send(s.poll(c), deadline)
+	default:
+		return c.send(&Error{Class: "ERROR", Message: fmt.Sprintf("Unrecognized request '%s'", name)}, deadline)
+	}
+}
+
+func (s *Server) removeClient(c *client) {
+	s.clientLock.Lock()
+	defer s.clientLock.Unlock()
+
+	if s.clients[c] {
+		delete(s.clients, c)
+		_ = c.conn.Close()
+		s.log.Info().Str("remote", c.conn.RemoteAddr().String()).Msg("gpsd client disconnected")
+	}
+}
+
+func (s *Server) broadcastLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.broadcast(now)
+		}
+	}
+}
+
+func (s *Server) broadcast(now time.Time) {
+	s.clientLock.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.clientLock.Unlock()
+
+	epochs := make(map[uint]nmea.Fix)
+	fixes := make(map[uint]*models.PositionFix)
+
+	for _, c := range clients {
+		c.watchLock.RLock()
+		watch := c.watch
+		c.watchLock.RUnlock()
+
+		if !watch.Enable || !watch.JSON {
+			continue
+		}
+
+		for _, stationId := range s.stationsFor(watch) {
+			epoch, exists := epochs[stationId]
+			if !exists {
+				fixes[stationId], epoch = s.epoch(stationId, now)
+				epochs[stationId] = epoch
+			}
+
+			deadline := now.Add(s.interval())
+			if err := c.send(NewTPV(stationId, fixes[stationId], epoch), deadline); err != nil {
+				s.removeClient(c)
+				break
+			}
+			if err := c.send(NewSKY(stationId, epoch), deadline); err != nil {
+				s.removeClient(c)
+				break
+			}
+		}
+	}
+}
+
+func (s *Server) poll(c *client) *Poll {
+	c.watchLock.RLock()
+	watch := c.watch
+	c.watchLock.RUnlock()
+
+	now := time.Now()
+	poll := &Poll{Class: "POLL", Time: now.UTC().Format(timeFormat), TPV: make([]*TPV, 0), SKY: make([]*SKY, 0)}
+
+	for _, stationId := range s.stationsFor(watch) {
+		fix, epoch := s.epoch(stationId, now)
+		poll.TPV = append(poll.TPV, NewTPV(stationId, fix, epoch))
+		poll.SKY = append(poll.SKY, NewSKY(stationId, epoch))
+		poll.Active++
+	}
+
+	return poll
+}
+
+func (s *Server) devices() *Devices {
+	devices := &Devices{Class: "DEVICES", Devices: make([]*Device, 0)}
+	for _, stationId := range s.stationsFor(Watch{}) {
+		devices.Devices = append(devices.Devices, NewDevice(stationId, s.interval()))
+	}
+	return devices
+}
+
+// stationsFor resolves the devices a client is interested in: the device of
+// its WATCH, the configured station, or every tag with a position.
+func (s *Server) stationsFor(watch Watch) []uint {
+	if watch.Device != "" {
+		stationId, err := strconv.ParseUint(strings.TrimPrefix(watch.Device, "uwb:"), 10, 32)
+		if err != nil {
+			return nil
+		}
+		return []uint{uint(stationId)}
+	}
+
+	if s.config.StationID != 0 {
+		return []uint{s.config.StationID}
+	}
+
+	fixes := s.provider.GetAll()
+	stationIds := make([]uint, 0, len(fixes))
+	for _, fix := range fixes {
+		stationIds = append(stationIds, fix.StationID)
+	}
+	return stationIds
+}
+
+// epoch maps the latest fix of a station into the shared NMEA representation
+// so both protocols report the same DOP, speed and course values.
+func (s *Server) epoch(stationId uint, now time.Time) (*models.PositionFix, nmea.Fix) {
+	fix, _ := s.provider.GetLatest(stationId)
+
+	s.epochLock.Lock()
+	defer s.epochLock.Unlock()
+
+	if latest := s.latest[stationId]; fix != nil && latest != nil && fix.Timestamp.After(latest.Timestamp) {
+		s.previous[stationId] = latest
+	}
+	if fix != nil {
+		s.latest[stationId] = fix
+	}
+
+	return fix, nmea.FromPositionFix(fix, s.previous[stationId], s.config.MaxAge, now)
+}
+
+func (s *Server) interval() time.Duration {
+	if s.config.Interval <= 0 {
+		return time.Second
+	}
+	return s.config.Interval
+}
+
+// splitCommands tokenizes client input on ';' and newlines, as gpsd accepts
+// both as command terminators.
+func splitCommands(data []byte, atEOF bool) (int, []byte, error) {
+	for i, b := range data {
+		if b == ';' || b == '\n' {
+			return i + 1, data[:i], nil
+		}
+	}
+
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
@@ -0,0 +1,27 @@
+package actor
+
+import (
+	"github.com/gin-gonic/gin"
+	common "gps-no-server/internal/common/actor"
+	"strings"
+)
+
+// Header names the user or tool responsible for a request.
+const Header = "X-Actor"
+
+// Middleware stores the actor of the request on the gin context, where
+// services can read it through actor.FromContext.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := strings.TrimSpace(c.GetHeader(Header))
+		if name == "" {
+			name = common.Anonymous
+		}
+		if len(name) > 100 {
+			name = name[:100]
+		}
+
+		c.Set(common.ContextKey, name)
+		c.Next()
+	}
+}
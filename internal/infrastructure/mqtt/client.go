@@ -1,6 +1,7 @@
 package mqtt
 
 import (
+	"errors"
 	"fmt"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/rs/zerolog"
@@ -11,6 +12,10 @@ import (
 	"time"
 )
 
+const defaultPublishTimeout = 5 * time.Second
+
+var ErrPublishTimeout = errors.New("timed out waiting for the broker")
+
 type Client struct {
 	client   mqtt.Client
 	config   *config.MqttConfig
@@ -35,20 +40,35 @@ func Create(cfg *config.MqttConfig, registry *Registry) (*Client, error) {
 		opts.SetPassword(cfg.Password)
 	}
 
+	if cfg.WillTopic != "" {
+		opts.SetWill(cfg.WillTopic, "offline", 1, true)
+	}
+
+	mqttClient := &Client{
+		config:   cfg,
+		log:      logger.GetLogger("mqtt"),
+		Registry: registry,
+	}
+
 	opts.SetOnConnectHandler(func(client mqtt.Client) {
 		log.Info().Msgf("Successfully connected to MQTT broker: %s", broker)
+
+		if cfg.WillTopic != "" {
+			client.Publish(cfg.WillTopic, 1, true, "online")
+		}
+
+		// A clean session drops all subscriptions, so they are renewed on
+		// every connect including automatic reconnects.
+		if err := mqttClient.SubscribeRegistry(); err != nil {
+			mqttClient.log.Error().Err(err).Msg("Error subscribing to MQTT topics")
+		}
 	})
 
 	opts.SetConnectionLostHandler(func(client mqtt.Client, err error) {
 		log.Info().Msgf("Connection lost: %v", err)
 	})
 
-	mqttClient := &Client{
-		client:   mqtt.NewClient(opts),
-		config:   cfg,
-		log:      logger.GetLogger("mqtt"),
-		Registry: registry,
-	}
+	mqttClient.client = mqtt.NewClient(opts)
 
 	return mqttClient, nil
 }
@@ -77,6 +97,11 @@ func (c *Client) Disconnect() error {
 		return nil
 	}
 
+	// A clean disconnect does not trigger the will, so announce it ourselves.
+	if c.config.WillTopic != "" {
+		c.client.Publish(c.config.WillTopic, 1, true, "offline").WaitTimeout(time.Second)
+	}
+
 	c.client.Disconnect(250)
 
 	if c.client.IsConnected() {
@@ -111,9 +136,18 @@ func (c *Client) messageHandler(client mqtt.Client, message mqtt.Message) {
 	}
 }
 
-func (c *Client) Publish(topic string, qos int, retained bool, payload []byte) interface{} {
-	if token := c.client.Publish(topic, byte(qos), retained, payload); token.Wait() && token.Error() != nil {
-		return token.Error()
+// Publish waits at most the configured publish timeout for the broker, so
+// callers are not blocked while the connection is down. A timeout counts as
+// a failed publish.
+func (c *Client) Publish(topic string, qos int, retained bool, payload []byte) error {
+	timeout := c.config.PublishTimeout
+	if timeout <= 0 {
+		timeout = defaultPublishTimeout
 	}
-	return nil
+
+	token := c.client.Publish(topic, byte(qos), retained, payload)
+	if !token.WaitTimeout(timeout) {
+		return fmt.Errorf("publish to %s: %w", topic, ErrPublishTimeout)
+	}
+	return token.Error()
 }
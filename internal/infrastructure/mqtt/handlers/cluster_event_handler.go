@@ -30,12 +30,10 @@ func (c *ClusterEventHandler) HandleEvent(event *events.StationEvent) {
 		return
 	}
 
-	/*
-		if err := c.mqttClient.Publish(topic, 1, false, payload); err != nil {
-			c.log.Error().Str("topic", topic).Msg("Failed to publish event")
-			return
-		}
-	*/
+	if err := c.mqttClient.Publish(topic, 1, false, payload); err != nil {
+		c.log.Error().Str("topic", topic).Interface("error", err).Msg("Failed to publish cluster event")
+		return
+	}
 
 	c.log.Info().
 		Str("type", string(event.Type)).
@@ -46,7 +44,7 @@ func (c *ClusterEventHandler) HandleEvent(event *events.StationEvent) {
 
 func (c *ClusterEventHandler) buildTopic(event *events.StationEvent) string {
 	switch event.Type {
-	case events.StationAddedToCluster, events.StationRemovedFromCluster:
+	case events.StationAddedToCluster, events.StationRemovedFromCluster, events.StationClusterMismatch:
 		return fmt.Sprintf("gpsno/clusters/%d/stations/%d", event.ClusterId, event.StationId)
 	case events.ClusterUpdated:
 		return fmt.Sprintf("gpsno/clusters/%d", event.ClusterId)
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/events"
+	"gps-no-server/internal/infrastructure/mqtt"
+)
+
+type PresenceEventHandler struct {
+	mqttClient *mqtt.Client
+	log        zerolog.Logger
+}
+
+func NewPresenceEventHandler(mqttClient *mqtt.Client) *PresenceEventHandler {
+	return &PresenceEventHandler{
+		mqttClient: mqttClient,
+		log:        logger.GetLogger("presence-event-handler"),
+	}
+}
+
+// HandleEvent publishes the latest presence of a station as a retained
+// message so late subscribers see the current status.
+func (p *PresenceEventHandler) HandleEvent(event *events.StationEvent) {
+	topic := fmt.Sprintf("gpsno/stations/%d/presence", event.StationId)
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		p.log.Error().Err(err).Msg("Failed to marshal presence event")
+		return
+	}
+
+	if err := p.mqttClient.Publish(topic, 1, true, payload); err != nil {
+		p.log.Error().Str("topic", topic).Interface("error", err).Msg("Failed to publish presence event")
+		return
+	}
+
+	p.log.Debug().
+		Str("type", string(event.Type)).
+		Str("topic", topic).
+		Msg("Published presence event to MQTT")
+}
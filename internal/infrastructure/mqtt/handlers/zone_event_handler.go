@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/events"
+	"gps-no-server/internal/infrastructure/mqtt"
+)
+
+type ZoneEventHandler struct {
+	mqttClient *mqtt.Client
+	log        zerolog.Logger
+}
+
+func NewZoneEventHandler(mqttClient *mqtt.Client) *ZoneEventHandler {
+	return &ZoneEventHandler{
+		mqttClient: mqttClient,
+		log:        logger.GetLogger("zone-event-handler"),
+	}
+}
+
+func (z *ZoneEventHandler) HandleEvent(event *events.ZoneEvent) {
+	topic := fmt.Sprintf("gpsno/clusters/%d/zones/%d/events", event.ClusterId, event.ZoneId)
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		z.log.Error().Err(err).Msg("Failed to marshal zone event")
+		return
+	}
+
+	if err := z.mqttClient.Publish(topic, 1, false, payload); err != nil {
+		z.log.Error().Str("topic", topic).Interface("error", err).Msg("Failed to publish zone event")
+		return
+	}
+
+	z.log.Debug().
+		Str("type", string(event.Type)).
+		Str("topic", topic).
+		Msg("Published zone event to MQTT")
+}
@@ -0,0 +1,134 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/services"
+	"strings"
+	"time"
+)
+
+// ConfigAckRaw is the answer a device publishes after receiving a pushed
+// configuration.
+type ConfigAckRaw struct {
+	MacAddress string `json:"mac_address"`
+	Revision   uint   `json:"revision"`
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+}
+
+// ConfigReportRaw is the configuration a device reports as currently active.
+type ConfigReportRaw struct {
+	MacAddress string `json:"mac_address"`
+	UWB        struct {
+		Mode           string   `json:"mode"`
+		Channel        uint8    `json:"channel"`
+		PreambleCode   uint8    `json:"preamble_code"`
+		PreambleLength string   `json:"preamble_length"`
+		DataRate       uint     `json:"data_rate"`
+		PRF            uint8    `json:"prf"`
+		SFD            string   `json:"sfd"`
+		TxPower        *float64 `json:"tx_power"`
+		AntennaDelay   uint16   `json:"antenna_delay"`
+	} `json:"uwb"`
+}
+
+type ConfigSyncSubscription struct {
+	log               zerolog.Logger
+	configSyncService *services.ConfigSyncService
+}
+
+func NewConfigSyncSubscription(configSyncService *services.ConfigSyncService) *ConfigSyncSubscription {
+	return &ConfigSyncSubscription{
+		log:               logger.GetLogger("config-sync-subscription"),
+		configSyncService: configSyncService,
+	}
+}
+
+func (c *ConfigSyncSubscription) GetTopics() []string {
+	return []string{
+		"gpsno/simulation/devices/+/uwb/config/ack",
+		"gpsno/simulation/devices/+/uwb/config",
+	}
+}
+
+func (c *ConfigSyncSubscription) HandleMessage(message mqtt.Message) {
+	topic := message.Topic()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if strings.HasSuffix(topic, "/ack") {
+		c.handleAck(ctx, topic, message.Payload())
+		return
+	}
+
+	c.handleReport(ctx, topic, message.Payload())
+}
+
+func (c *ConfigSyncSubscription) handleAck(ctx context.Context, topic string, payload []byte) {
+	var ackRaw ConfigAckRaw
+	if err := json.Unmarshal(payload, &ackRaw); err != nil {
+		c.log.Error().Err(err).Str("topic", topic).Msg("Failed to parse configuration acknowledgement")
+		return
+	}
+
+	if ackRaw.MacAddress == "" {
+		ackRaw.MacAddress = macFromTopic(topic)
+	}
+
+	applied := true
+	switch strings.ToLower(ackRaw.Status) {
+	case "applied", "ok", "":
+	case "failed", "error", "rejected":
+		applied = false
+	default:
+		c.log.Warn().Str("topic", topic).Str("status", ackRaw.Status).Msg("Ignoring unknown acknowledgement status")
+		return
+	}
+
+	if err := c.configSyncService.Acknowledge(ctx, ackRaw.MacAddress, ackRaw.Revision, applied, ackRaw.Error); err != nil {
+		c.log.Error().Err(err).Str("topic", topic).Str("mac", ackRaw.MacAddress).Msg("Failed to process configuration acknowledgement")
+	}
+}
+
+func (c *ConfigSyncSubscription) handleReport(ctx context.Context, topic string, payload []byte) {
+	var reportRaw ConfigReportRaw
+	if err := json.Unmarshal(payload, &reportRaw); err != nil {
+		c.log.Error().Err(err).Str("topic", topic).Msg("Failed to parse reported configuration")
+		return
+	}
+
+	if reportRaw.MacAddress == "" {
+		reportRaw.MacAddress = macFromTopic(topic)
+	}
+
+	reported := models.ShadowState{
+		UWBMode:         models.UWBMode(strings.ToUpper(reportRaw.UWB.Mode)),
+		UWBChannel:      reportRaw.UWB.Channel,
+		UWBPreambleCode: reportRaw.UWB.PreambleCode,
+		UWBPreambleLen:  reportRaw.UWB.PreambleLength,
+		UWBDataRate:     reportRaw.UWB.DataRate,
+		UWBPRF:          reportRaw.UWB.PRF,
+		UWBSFD:          models.UWBSFD(reportRaw.UWB.SFD),
+		UWBTxPower:      reportRaw.UWB.TxPower,
+		UWBAntennaDelay: reportRaw.UWB.AntennaDelay,
+	}
+
+	if err := c.configSyncService.Report(ctx, reportRaw.MacAddress, reported); err != nil {
+		c.log.Error().Err(err).Str("topic", topic).Str("mac", reportRaw.MacAddress).Msg("Failed to process reported configuration")
+	}
+}
+
+// macFromTopic returns the device segment of a gpsno/simulation/devices/<mac>/...
+// topic.
+func macFromTopic(topic string) string {
+	if segments := strings.Split(topic, "/"); len(segments) > 3 {
+		return segments[3]
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/services"
+	"strings"
+	"time"
+)
+
+// StatusRaw is the payload devices publish on their status topic, usually
+// as MQTT last will. A plain "online" or "offline" payload is accepted too.
+type StatusRaw struct {
+	Status     string `json:"status"`
+	MacAddress string `json:"mac_address"`
+}
+
+type PresenceSubscription struct {
+	log             zerolog.Logger
+	presenceService *services.PresenceService
+}
+
+func NewPresenceSubscription(presenceService *services.PresenceService) *PresenceSubscription {
+	return &PresenceSubscription{
+		log:             logger.GetLogger("presence-subscription"),
+		presenceService: presenceService,
+	}
+}
+
+func (c *PresenceSubscription) GetTopics() []string {
+	return []string{
+		"gpsno/simulation/devices/+/device/status",
+	}
+}
+
+func (c *PresenceSubscription) HandleMessage(message mqtt.Message) {
+	topic := message.Topic()
+	payload := strings.TrimSpace(string(message.Payload()))
+
+	var statusRaw StatusRaw
+	if err := json.Unmarshal([]byte(payload), &statusRaw); err != nil {
+		statusRaw.Status = payload
+	}
+
+	// The device segment of the topic identifies the station when the
+	// payload does not carry its MAC address.
+	if statusRaw.MacAddress == "" {
+		if segments := strings.Split(topic, "/"); len(segments) > 3 {
+			statusRaw.MacAddress = segments[3]
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var err error
+	switch strings.ToLower(statusRaw.Status) {
+	case "online":
+		err = c.presenceService.Touch(ctx, []string{statusRaw.MacAddress}, time.Now())
+	case "offline":
+		err = c.presenceService.MarkOffline(ctx, statusRaw.MacAddress, time.Now())
+	default:
+		c.log.Warn().Str("topic", topic).Str("status", statusRaw.Status).Msg("Ignoring unknown station status")
+		return
+	}
+
+	if err != nil {
+		c.log.Error().Err(err).Str("topic", topic).Str("mac", statusRaw.MacAddress).Msg("Failed to update station presence")
+	}
+}
@@ -17,24 +17,37 @@ type RangingItem struct {
 	SourceAddress      string       `json:"source_address"`
 	DestinationAddress string       `json:"destination_address"`
 	Distance           DistanceData `json:"distance"`
+	Signal             *SignalData  `json:"signal,omitempty"`
 }
 
 type DistanceData struct {
-	RawDistance    float64 `json:"raw_distance"`
-	ScaledDistance float64 `json:"scaled_distance"`
+	RawDistance    float64  `json:"raw_distance"`
+	ScaledDistance *float64 `json:"scaled_distance"`
+}
+
+type SignalData struct {
+	RSSI           *float64 `json:"rssi"`
+	FirstPathPower *float64 `json:"first_path_power"`
+	Quality        *float64 `json:"quality"`
 }
 
 type RangingSubscription struct {
-	log            zerolog.Logger
-	rangingService *services.RangingService
-	stationService *services.StationService
+	log             zerolog.Logger
+	rangingService  *services.RangingService
+	positionService *services.PositionService
+	presenceService *services.PresenceService
 }
 
-func NewRangingSubscription(rangingService *services.RangingService, stationService *services.StationService) *RangingSubscription {
+func NewRangingSubscription(
+	rangingService *services.RangingService,
+	positionService *services.PositionService,
+	presenceService *services.PresenceService,
+) *RangingSubscription {
 	return &RangingSubscription{
-		log:            logger.GetLogger("ranging-subscription"),
-		rangingService: rangingService,
-		stationService: stationService,
+		log:             logger.GetLogger("ranging-subscription"),
+		rangingService:  rangingService,
+		positionService: positionService,
+		presenceService: presenceService,
 	}
 }
 
@@ -57,27 +70,51 @@ func (c *RangingSubscription) HandleMessage(message mqtt.Message) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var rangingModels []*models.Ranging
+	receivedAt := time.Now()
+	samples := make([]*models.RangingSample, 0, len(rangingList))
+	seen := make(map[string]bool)
 	for _, rangingData := range rangingList {
-		source := &models.Station{MacAddress: rangingData.SourceAddress}
-		destination := &models.Station{MacAddress: rangingData.DestinationAddress}
+		seen[rangingData.SourceAddress] = true
+		seen[rangingData.DestinationAddress] = true
 
-		sourceStation, _ := c.stationService.GetByMac(ctx, source.MacAddress, nil)
-		destinationStation, _ := c.stationService.GetByMac(ctx, destination.MacAddress, nil)
+		sample := &models.RangingSample{
+			Source:         &models.Station{MacAddress: rangingData.SourceAddress},
+			Destination:    &models.Station{MacAddress: rangingData.DestinationAddress},
+			Timestamp:      receivedAt,
+			RawDistance:    rangingData.Distance.RawDistance,
+			ScaledDistance: rangingData.Distance.ScaledDistance,
+		}
 
-		rangingModel := &models.Ranging{
-			Source:      sourceStation,
-			Destination: destinationStation,
-			RawDistance: rangingData.Distance.RawDistance,
+		if rangingData.Signal != nil {
+			sample.RSSI = rangingData.Signal.RSSI
+			sample.FirstPathPower = rangingData.Signal.FirstPathPower
+			sample.SignalQuality = rangingData.Signal.Quality
 		}
 
-		rangingModels = append(rangingModels, rangingModel)
+		samples = append(samples, sample)
+	}
+
+	persisted, err := c.rangingService.UpsertBatch(ctx, samples, nil)
+	if err != nil {
+		c.log.Error().Err(err).Str("topic", topic).Msg("Failed to save ranging data")
+		return
 	}
 
-	/*
-		if _, err := c.rangingService.UpdateAllOrCreate(ctx, rangingModels, nil); err != nil {
-			c.log.Error().Err(err).Msg("Failed to save rangingService data")
-			return
+	// Both ends of a ranging had to respond, so both count as seen.
+	if c.presenceService != nil {
+		macAddresses := make([]string, 0, len(seen))
+		for macAddress := range seen {
+			macAddresses = append(macAddresses, macAddress)
 		}
-	*/
+
+		if err := c.presenceService.Touch(ctx, macAddresses, receivedAt); err != nil {
+			c.log.Error().Err(err).Str("topic", topic).Msg("Failed to update station presence")
+		}
+	}
+
+	c.log.Debug().Str("topic", topic).Int("count", len(persisted)).Msg("Ranging data saved successfully")
+
+	if c.positionService != nil {
+		c.positionService.UpdateForRangings(ctx, persisted)
+	}
 }
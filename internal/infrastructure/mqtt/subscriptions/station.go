@@ -27,22 +27,31 @@ type StationRaw struct {
 		CreatedAt  string  `json:"created_at"`
 		StartedAt  string  `json:"started_at"`
 		Uptime     int64   `json:"uptime"`
-		Position   struct {
-			X float64 `json:"x"`
-			Y float64 `json:"y"`
+		Position   *struct {
+			X float64  `json:"x"`
+			Y float64  `json:"y"`
+			Z *float64 `json:"z"`
 		} `json:"position"`
 	} `json:"device"`
 }
 
 type StationSubscription struct {
-	log            zerolog.Logger
-	stationService *services.StationService
+	log               zerolog.Logger
+	stationService    *services.StationService
+	membershipService *services.ClusterMembershipService
+	presenceService   *services.PresenceService
 }
 
-func NewStationSubscription(stationService *services.StationService) *StationSubscription {
+func NewStationSubscription(
+	stationService *services.StationService,
+	membershipService *services.ClusterMembershipService,
+	presenceService *services.PresenceService,
+) *StationSubscription {
 	return &StationSubscription{
-		log:            logger.GetLogger("station-subscription"),
-		stationService: stationService,
+		log:               logger.GetLogger("station-subscription"),
+		stationService:    stationService,
+		membershipService: membershipService,
+		presenceService:   presenceService,
 	}
 }
 
@@ -69,11 +78,42 @@ func (c *StationSubscription) HandleMessage(message mqtt.Message) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if _, err := c.stationService.UpdateOrCreate(ctx, station, nil); err != nil {
+	receivedAt := time.Now()
+	savedStation, err := c.stationService.UpdateOrCreate(ctx, station, nil)
+	if err != nil {
 		c.log.Error().Err(err).Str("mac", station.MacAddress).Msg("Failed to save station")
 		return
 	}
 
+	if err := c.presenceService.Touch(ctx, []string{savedStation.MacAddress}, receivedAt); err != nil {
+		c.log.Error().Err(err).Str("mac", station.MacAddress).Msg("Failed to update station presence")
+	}
+
+	var startedAt *time.Time
+	if parsed, err := time.Parse(time.RFC3339, stationRaw.Device.StartedAt); err == nil {
+		startedAt = &parsed
+	}
+
+	if err := c.presenceService.ReportUptime(ctx, savedStation.ID, startedAt, stationRaw.Device.Uptime, receivedAt); err != nil {
+		c.log.Error().Err(err).Str("mac", station.MacAddress).Msg("Failed to save station uptime")
+	}
+
+	if stationRaw.Device.Position != nil {
+		position := models.Position{
+			X: &stationRaw.Device.Position.X,
+			Y: &stationRaw.Device.Position.Y,
+			Z: stationRaw.Device.Position.Z,
+		}
+
+		if err := c.stationService.UpdateReportedPosition(ctx, savedStation, position); err != nil {
+			c.log.Error().Err(err).Str("mac", station.MacAddress).Msg("Failed to save reported position")
+		}
+	}
+
+	if err := c.membershipService.Reconcile(ctx, savedStation, stationRaw.UWB.Cluster.Name, stationRaw.UWB.Cluster.Stations); err != nil {
+		c.log.Error().Err(err).Str("mac", station.MacAddress).Msg("Failed to reconcile reported cluster")
+	}
+
 	c.log.Debug().Str("mac", station.MacAddress).Msg("Station data saved successfully")
 
 }
@@ -0,0 +1,57 @@
+package nmea
+
+import (
+	"gps-no-server/internal/core/models"
+	"gps-no-server/internal/core/positioning"
+	"math"
+	"time"
+)
+
+const (
+	metresPerDegree = 111320.0
+	knotsPerMetre   = 1.943844
+)
+
+// FromPositionFix maps a solver fix onto NMEA fields using the DOP values of
+// the anchor geometry. Degraded fixes are reported as estimated so receivers
+// can discard them; fixes that are stale or not georeferenced are invalid.
+func FromPositionFix(fix *models.PositionFix, previous *models.PositionFix, maxAge time.Duration, now time.Time) Fix {
+	result := Fix{Time: now}
+	if fix == nil || fix.Geo == nil || fix.FixType == models.FixNone || (maxAge > 0 && now.Sub(fix.Timestamp) > maxAge) {
+		return result
+	}
+
+	vdop := fix.VDOP
+	if fix.Dimension != 3 {
+		vdop = positioning.MaxDOP
+	}
+
+	result.Time = fix.Timestamp
+	result.Latitude = fix.Geo.Latitude
+	result.Longitude = fix.Geo.Longitude
+	result.Altitude = fix.Geo.Altitude
+	result.Dimension = fix.Dimension
+	result.Satellites = fix.AnchorIDs
+	result.HDOP = fix.HDOP
+	result.VDOP = vdop
+	result.PDOP = fix.GDOP
+
+	result.Quality = FixGPS
+	if fix.FixType == models.FixDegraded {
+		result.Quality = FixEstimated
+	}
+
+	if previous != nil && previous.Geo != nil && fix.Timestamp.After(previous.Timestamp) {
+		north := (fix.Geo.Latitude - previous.Geo.Latitude) * metresPerDegree
+		east := (fix.Geo.Longitude - previous.Geo.Longitude) * metresPerDegree * math.Cos(fix.Geo.Latitude*math.Pi/180)
+		elapsed := fix.Timestamp.Sub(previous.Timestamp).Seconds()
+
+		result.SpeedKnots = math.Hypot(north, east) / elapsed * knotsPerMetre
+		if math.Hypot(north, east) > 1e-3 {
+			result.Course = math.Mod(math.Atan2(east, north)*180/math.Pi+360, 360)
+			result.HasCourse = true
+		}
+	}
+
+	return result
+}
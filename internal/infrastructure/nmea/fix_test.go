@@ -0,0 +1,34 @@
+package nmea
+
+import (
+	"gps-no-server/internal/core/models"
+	"testing"
+	"time"
+)
+
+func TestFromPositionFixQuality(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	geo := &models.GeoPosition{Latitude: 48.1, Longitude: 11.5}
+
+	tests := []struct {
+		name     string
+		fix      *models.PositionFix
+		expected FixQuality
+	}{
+		{name: "no fix", fix: nil, expected: FixInvalid},
+		{name: "none", fix: &models.PositionFix{FixType: models.FixNone, Geo: geo, Timestamp: now}, expected: FixInvalid},
+		{name: "not georeferenced", fix: &models.PositionFix{FixType: models.Fix3D, Timestamp: now}, expected: FixInvalid},
+		{name: "stale", fix: &models.PositionFix{FixType: models.Fix3D, Geo: geo, Timestamp: now.Add(-time.Minute)}, expected: FixInvalid},
+		{name: "2D", fix: &models.PositionFix{FixType: models.Fix2D, Geo: geo, Timestamp: now}, expected: FixGPS},
+		{name: "3D with high quality", fix: &models.PositionFix{FixType: models.Fix3D, Quality: 0.99, Geo: geo, Timestamp: now}, expected: FixGPS},
+		{name: "degraded", fix: &models.PositionFix{FixType: models.FixDegraded, Geo: geo, Timestamp: now}, expected: FixEstimated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if quality := FromPositionFix(test.fix, nil, 10*time.Second, now).Quality; quality != test.expected {
+				t.Errorf("quality = %d, want %d", quality, test.expected)
+			}
+		})
+	}
+}
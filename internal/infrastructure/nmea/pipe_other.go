@@ -0,0 +1,12 @@
+//go:build !unix
+
+package nmea
+
+import (
+	"errors"
+	"io"
+)
+
+func openPipe(path string) (io.WriteCloser, error) {
+	return nil, errors.New("named pipes are not supported on this platform")
+}
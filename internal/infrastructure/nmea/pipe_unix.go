@@ -0,0 +1,30 @@
+//go:build unix
+
+package nmea
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// openPipe creates the FIFO if needed and opens it without blocking on a
+// reader. Epochs written while no reader drains the pipe are dropped once the
+// kernel buffer is full.
+func openPipe(path string) (io.WriteCloser, error) {
+	if err := syscall.Mkfifo(path, 0o644); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return nil, errors.New(path + " exists and is not a named pipe")
+	}
+
+	return os.OpenFile(path, os.O_RDWR|syscall.O_NONBLOCK, os.ModeNamedPipe)
+}
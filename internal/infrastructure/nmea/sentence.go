@@ -0,0 +1,166 @@
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const TalkerID = "GP"
+
+type FixQuality int
+
+const (
+	FixInvalid   FixQuality = 0
+	FixGPS       FixQuality = 1
+	FixEstimated FixQuality = 6
+)
+
+// Fix holds everything needed to render one epoch of NMEA output.
+type Fix struct {
+	Time       time.Time
+	Latitude   float64
+	Longitude  float64
+	Altitude   float64
+	Quality    FixQuality
+	Dimension  int
+	Satellites []uint
+	PDOP       float64
+	HDOP       float64
+	VDOP       float64
+	SpeedKnots float64
+	Course     float64
+	HasCourse  bool
+}
+
+func (f Fix) Valid() bool {
+	return f.Quality != FixInvalid
+}
+
+// Checksum is the XOR of all bytes between '$' and '*'.
+func Checksum(body string) byte {
+	var checksum byte
+	for i := 0; i < len(body); i++ {
+		checksum ^= body[i]
+	}
+	return checksum
+}
+
+func Sentence(fields ...string) string {
+	body := strings.Join(fields, ",")
+	return fmt.Sprintf("$%s*%02X\r\n", body, Checksum(body))
+}
+
+func FormatGGA(fix Fix) string {
+	if !fix.Valid() {
+		return Sentence(TalkerID+"GGA", formatTime(fix.Time), "", "", "", "", "0", "00", "", "", "M", "", "M", "", "")
+	}
+
+	lat, latHemisphere := formatCoordinate(fix.Latitude, 2, "N", "S")
+	lon, lonHemisphere := formatCoordinate(fix.Longitude, 3, "E", "W")
+
+	return Sentence(
+		TalkerID+"GGA",
+		formatTime(fix.Time),
+		lat, latHemisphere,
+		lon, lonHemisphere,
+		fmt.Sprintf("%d", fix.Quality),
+		fmt.Sprintf("%02d", min(len(fix.Satellites), 99)),
+		formatDOP(fix.HDOP),
+		fmt.Sprintf("%.2f", fix.Altitude), "M",
+		"0.0", "M",
+		"", "",
+	)
+}
+
+func FormatRMC(fix Fix) string {
+	status := "V"
+	mode := "N"
+	if fix.Valid() {
+		status = "A"
+		mode = "A"
+		if fix.Quality == FixEstimated {
+			mode = "E"
+		}
+	}
+
+	lat, latHemisphere := "", ""
+	lon, lonHemisphere := "", ""
+	speed, course := "", ""
+	if fix.Valid() {
+		lat, latHemisphere = formatCoordinate(fix.Latitude, 2, "N", "S")
+		lon, lonHemisphere = formatCoordinate(fix.Longitude, 3, "E", "W")
+		speed = fmt.Sprintf("%.2f", fix.SpeedKnots)
+		if fix.HasCourse {
+			course = fmt.Sprintf("%.1f", fix.Course)
+		}
+	}
+
+	return Sentence(
+		TalkerID+"RMC",
+		formatTime(fix.Time),
+		status,
+		lat, latHemisphere,
+		lon, lonHemisphere,
+		speed, course,
+		fix.Time.UTC().Format("020106"),
+		"", "",
+		mode,
+	)
+}
+
+// FormatGSA lists up to twelve anchors in place of satellite PRNs.
+func FormatGSA(fix Fix) string {
+	mode := "1"
+	if fix.Valid() {
+		mode = "2"
+		if fix.Dimension == 3 {
+			mode = "3"
+		}
+	}
+
+	fields := []string{TalkerID + "GSA", "A", mode}
+	for i := 0; i < 12; i++ {
+		if fix.Valid() && i < len(fix.Satellites) {
+			fields = append(fields, fmt.Sprintf("%02d", fix.Satellites[i]%100))
+		} else {
+			fields = append(fields, "")
+		}
+	}
+
+	if fix.Valid() {
+		fields = append(fields, formatDOP(fix.PDOP), formatDOP(fix.HDOP), formatDOP(fix.VDOP))
+	} else {
+		fields = append(fields, "", "", "")
+	}
+
+	return Sentence(fields...)
+}
+
+func formatTime(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%02d%02d%02d.%02d", t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/int(10*time.Millisecond))
+}
+
+// formatCoordinate renders degrees as (d)ddmm.mmmmm with a hemisphere letter.
+func formatCoordinate(value float64, degreeDigits int, positive, negative string) (string, string) {
+	hemisphere := positive
+	if value < 0 {
+		hemisphere = negative
+		value = -value
+	}
+
+	degrees := math.Floor(value)
+	minutes := (value - degrees) * 60
+	if minutes >= 59.999995 {
+		degrees++
+		minutes = 0
+	}
+
+	return fmt.Sprintf("%0*d%08.5f", degreeDigits, int(degrees), minutes), hemisphere
+}
+
+func formatDOP(value float64) string {
+	return fmt.Sprintf("%.1f", math.Min(value, 99.9))
+}
@@ -0,0 +1,136 @@
+package nmea
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected byte
+	}{
+		{name: "empty", body: "", expected: 0x00},
+		{name: "reference GGA", body: "GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,", expected: 0x47},
+		{name: "single character", body: "A", expected: 'A'},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if checksum := Checksum(test.body); checksum != test.expected {
+				t.Errorf("checksum = %02X, want %02X", checksum, test.expected)
+			}
+		})
+	}
+}
+
+func TestSentence(t *testing.T) {
+	expected := "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47\r\n"
+	if sentence := Sentence("GPGGA", "123519", "4807.038", "N", "01131.000", "E", "1", "08", "0.9", "545.4", "M", "46.9", "M", "", ""); sentence != expected {
+		t.Errorf("sentence = %q, want %q", sentence, expected)
+	}
+}
+
+func TestFormatGGA(t *testing.T) {
+	tests := []struct {
+		name     string
+		fix      Fix
+		expected string
+	}{
+		{
+			name: "valid fix",
+			fix: Fix{
+				Time:       time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC),
+				Latitude:   48.1173,
+				Longitude:  11.516666666666667,
+				Altitude:   545.4,
+				Quality:    FixGPS,
+				Satellites: []uint{1, 2, 3, 4, 5, 6, 7, 8},
+				HDOP:       0.9,
+			},
+			expected: "$GPGGA,123519.00,4807.03800,N,01131.00000,E,1,08,0.9,545.40,M,0.0,M,,*62\r\n",
+		},
+		{
+			name: "southern and western hemisphere",
+			fix: Fix{
+				Time:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Latitude:   -33.8688,
+				Longitude:  -151.20953333333333,
+				Altitude:   10,
+				Quality:    FixEstimated,
+				Satellites: []uint{1, 2, 3},
+				HDOP:       1.2,
+			},
+			expected: "$GPGGA,000000.00,3352.12800,S,15112.57200,W,6,03,1.2,10.00,M,0.0,M,,*5F\r\n",
+		},
+		{
+			name:     "invalid fix",
+			fix:      Fix{Time: time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC)},
+			expected: "$GPGGA,123519.00,,,,,0,00,,,M,,M,,*45\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if sentence := FormatGGA(test.fix); sentence != test.expected {
+				t.Errorf("sentence = %q, want %q", sentence, test.expected)
+			}
+		})
+	}
+}
+
+func TestFormatRMC(t *testing.T) {
+	fix := Fix{
+		Time:      time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC),
+		Latitude:  48.1173,
+		Longitude: 11.516666666666667,
+	}
+
+	tests := []struct {
+		name     string
+		quality  FixQuality
+		expected string
+	}{
+		{name: "autonomous", quality: FixGPS, expected: "$GPRMC,123519.00,A,4807.03800,N,01131.00000,E,0.00,,230394,,,A*40\r\n"},
+		{name: "estimated", quality: FixEstimated, expected: "$GPRMC,123519.00,A,4807.03800,N,01131.00000,E,0.00,,230394,,,E*44\r\n"},
+		{name: "invalid", quality: FixInvalid, expected: "$GPRMC,123519.00,V,,,,,,,230394,,,N*7F\r\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fix.Quality = test.quality
+			if sentence := FormatRMC(fix); sentence != test.expected {
+				t.Errorf("sentence = %q, want %q", sentence, test.expected)
+			}
+		})
+	}
+}
+
+func TestFormatCoordinate(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      float64
+		digits     int
+		coordinate string
+		hemisphere string
+	}{
+		{name: "zero", value: 0, digits: 2, coordinate: "0000.00000", hemisphere: "N"},
+		{name: "negative longitude", value: -0.5, digits: 3, coordinate: "00030.00000", hemisphere: "W"},
+		{name: "minutes round up to the next degree", value: 47.9999999999, digits: 2, coordinate: "4800.00000", hemisphere: "N"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			positive, negative := "N", "S"
+			if test.digits == 3 {
+				positive, negative = "E", "W"
+			}
+
+			coordinate, hemisphere := formatCoordinate(test.value, test.digits, positive, negative)
+			if coordinate != test.coordinate || hemisphere != test.hemisphere {
+				t.Errorf("coordinate = %s %s, want %s %s", coordinate, hemisphere, test.coordinate, test.hemisphere)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package nmea
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gps-no-server/internal/common/config"
+	"gps-no-server/internal/common/logger"
+	"gps-no-server/internal/core/models"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type PositionProvider interface {
+	GetLatest(stationId uint) (*models.PositionFix, bool)
+}
+
+type client struct {
+	conn      net.Conn
+	stationId atomic.Uint32
+}
+
+// Server streams NMEA sentences for tag stations to TCP clients and an
+// optional named pipe. Clients follow the configured station unless they
+// select another one by sending "STATION <id>" or "$PGPSNO,STATION,<id>*hh".
+type Server struct {
+	config     *config.NmeaConfig
+	provider   PositionProvider
+	listener   net.Listener
+	pipe       io.WriteCloser
+	clients    map[*client]bool
+	clientLock sync.Mutex
+	previous   map[uint]*models.PositionFix
+	latest     map[uint]*models.PositionFix
+	done       chan struct{}
+	wg         sync.WaitGroup
+	log        zerolog.Logger
+}
+
+func NewServer(cfg *config.NmeaConfig, provider PositionProvider) *Server {
+	return &Server{
+		config:   cfg,
+		provider: provider,
+		clients:  make(map[*client]bool),
+		previous: make(map[uint]*models.PositionFix),
+		latest:   make(map[uint]*models.PositionFix),
+		done:     make(chan struct{}),
+		log:      logger.GetLogger("nmea-server"),
+	}
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.config.Host, s.config.Port))
+	if err != nil {
+		return fmt.Errorf("failed to start NMEA listener: %w", err)
+	}
+	s.listener = listener
+
+	if s.config.PipePath != "" {
+		pipe, err := openPipe(s.config.PipePath)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to open NMEA pipe: %w", err)
+		}
+		s.pipe = pipe
+	}
+
+	s.wg.Add(2)
+	go s.acceptLoop()
+	go s.broadcastLoop()
+
+	s.log.Info().Str("address", listener.Addr().String()).Uint("station_id", s.config.StationID).Msg("Started NMEA server")
+	return nil
+}
+
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+
+	close(s.done)
+	err := s.listener.Close()
+
+	s.clientLock.Lock()
+	for c := range s.clients {
+		_ = c.conn.Close()
+	}
+	s.clientLock.Unlock()
+
+	s.wg.Wait()
+
+	if s.pipe != nil {
+		if pipeErr := s.pipe.Close(); pipeErr != nil && err == nil {
+			err = pipeErr
+		}
+	}
+
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return
+			default:
+				s.log.Error().Err(err).Msg("Failed to accept NMEA client")
+				continue
+			}
+		}
+
+		c := &client{conn: conn}
+		c.stationId.Store(uint32(s.config.StationID))
+
+		s.clientLock.Lock()
+		s.clients[c] = true
+		s.clientLock.Unlock()
+
+		s.log.Info().Str("remote", conn.RemoteAddr().String()).Msg("NMEA client connected")
+
+		s.wg.Add(1)
+		go s.readLoop(c)
+	}
+}
+
+func (s *Server) readLoop(c *client) {
+	defer s.wg.Done()
+	defer s.removeClient(c)
+
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		if stationId, ok := parseStationCommand(scanner.Text()); ok {
+			c.stationId.Store(uint32(stationId))
+			s.log.Debug().Str("remote", c.conn.RemoteAddr().String()).Uint("station_id", stationId).Msg("NMEA client selected station")
+		}
+	}
+}
+
+func (s *Server) removeClient(c *client) {
+	s.clientLock.Lock()
+	defer s.clientLock.Unlock()
+
+	if s.clients[c] {
+		delete(s.clients, c)
+		_ = c.conn.Close()
+		s.log.Info().Str("remote", c.conn.RemoteAddr().String()).Msg("NMEA client disconnected")
+	}
+}
+
+func (s *Server) broadcastLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.broadcast(now)
+		}
+	}
+}
+
+func (s *Server) broadcast(now time.Time) {
+	epochs := make(map[uint][]byte)
+	render := func(stationId uint) []byte {
+		if epoch, exists := epochs[stationId]; exists {
+			return epoch
+		}
+		epoch := []byte(s.render(stationId, now))
+		epochs[stationId] = epoch
+		return epoch
+	}
+
+	if s.pipe != nil && s.config.StationID != 0 {
+		if _, err := s.pipe.Write(render(s.config.StationID)); err != nil {
+			s.log.Debug().Err(err).Msg("Dropped NMEA epoch for pipe")
+		}
+	}
+
+	s.clientLock.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.clientLock.Unlock()
+
+	for _, c := range clients {
+		stationId := uint(c.stationId.Load())
+		if stationId == 0 {
+			continue
+		}
+
+		_ = c.conn.SetWriteDeadline(now.Add(s.interval()))
+		if _, err := c.conn.Write(render(stationId)); err != nil {
+			s.removeClient(c)
+		}
+	}
+}
+
+func (s *Server) interval() time.Duration {
+	if s.config.Interval <= 0 {
+		return time.Second
+	}
+	return s.config.Interval
+}
+
+func (s *Server) render(stationId uint, now time.Time) string {
+	fix, _ := s.provider.GetLatest(stationId)
+
+	if latest := s.latest[stationId]; fix != nil && latest != nil && fix.Timestamp.After(latest.Timestamp) {
+		s.previous[stationId] = latest
+	}
+	if fix != nil {
+		s.latest[stationId] = fix
+	}
+
+	epoch := FromPositionFix(fix, s.previous[stationId], s.config.MaxAge, now)
+	return FormatGGA(epoch) + FormatRMC(epoch) + FormatGSA(epoch)
+}
+
+func parseStationCommand(line string) (uint, bool) {
+	line = strings.TrimSpace(line)
+
+	if strings.HasPrefix(line, "$") {
+		body, checksum, found := strings.Cut(line[1:], "*")
+		if found {
+			expected, err := strconv.ParseUint(checksum, 16, 8)
+			if err != nil || byte(expected) != Checksum(body) {
+				return 0, false
+			}
+		}
+
+		fields := strings.Split(body, ",")
+		if len(fields) != 3 || fields[0] != "PGPSNO" || !strings.EqualFold(fields[1], "STATION") {
+			return 0, false
+		}
+		line = "STATION " + fields[2]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "STATION") {
+		return 0, false
+	}
+
+	stationId, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint(stationId), true
+}